@@ -2,30 +2,38 @@ package api
 
 import (
 	"api-test/src/common"
+	"api-test/src/common/ratelimit"
 	"api-test/src/config"
 	"api-test/src/database/postgres"
+	"api-test/src/database/redis"
 	adminAPI "api-test/src/modules/admin/api"
 	"api-test/src/modules/admin/usecase"
 	apiCarrito "api-test/src/modules/carritocompra/api"
+	apiClientes "api-test/src/modules/clientes/api"
 	apiProductos "api-test/src/modules/productos/api"
 	"context"
 	"fmt"
 	"net"
 	"os"
+	"slices"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	goredis "github.com/redis/go-redis/v9"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
 )
 
 type Rest struct {
-	conf          *config.Config
-	log           common.Logger
-	tenant        *common.TenantConnectionManager
-	psql          postgres.Database
-	migrations    usecase.TenantMigrations
+	conf       *config.Config
+	log        common.Logger
+	tenant     *common.TenantConnectionManager
+	psql       postgres.Database
+	migrations usecase.TenantMigrations
+	// nil si ni la caché ni los límites de peticiones usan Redis
+	redis         redis.Redis
+	resolvers     []tenantResolver
 	EXCLUDE_PATHS []string
 }
 
@@ -35,13 +43,15 @@ func NewRest(
 	tenant *common.TenantConnectionManager,
 	psql postgres.Database,
 	migrations usecase.TenantMigrations,
+	redis redis.Redis,
 ) *Rest {
-	return &Rest{
+	rest := &Rest{
 		conf:       conf,
 		log:        log,
 		tenant:     tenant,
 		psql:       psql,
 		migrations: migrations,
+		redis:      redis,
 		EXCLUDE_PATHS: []string{
 			"/api/v1/login",
 			"/api/v1/register",
@@ -49,27 +59,54 @@ func NewRest(
 			"/api/v1/refresh",
 		},
 	}
+	rest.resolvers = rest.tenantResolvers()
+	return rest
 }
 
-func (r *Rest) Run() {
-	r.log.Info(context.Background(), "Starting Rest API")
-	app := fiber.New(fiber.Config{
+func (r *Rest) fiberConfig() fiber.Config {
+	return fiber.Config{
 		DisableStartupMessage: true,
 		JSONEncoder:           sonic.Marshal,
 		JSONDecoder:           sonic.Unmarshal,
-	})
+		// Los archivos de importación superan el límite por defecto de fiber
+		BodyLimit: max(fiber.DefaultBodyLimit, r.conf.TenantLifecycle.ImportMaxSize<<20),
+		// c.IP() lee PROXY_HEADER solo en las peticiones de TRUSTED_PROXIES
+		ProxyHeader:             r.conf.Proxy.Header,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          r.conf.Proxy.TrustedProxies,
+		EnableIPValidation:      true,
+	}
+}
+
+func (r *Rest) Run() {
+	r.log.Info(context.Background(), "Starting Rest API")
+	var client goredis.UniversalClient
+	if r.redis != nil {
+		client = r.redis.Client()
+	}
+	limiter, err := ratelimit.New(r.conf, client)
+	if err != nil {
+		r.log.Error(context.Background(), "Error creating rate limit store", "error", err)
+		os.Exit(1)
+	}
+	app := fiber.New(r.fiberConfig())
 	app.Use(recover.New(recover.Config{
 		EnableStackTrace: true,
 	}))
 
 	app.Use(r.ErrorHandler())
 	app.Use(helmet.New()) // Helmet middleware to secure default headers
-	app.Use(r.RequestLimitMiddleware())
+	app.Use(r.IPLimitMiddleware(limiter))
 	app.Use(r.CORSMiddleware())
+	if slices.Contains(r.conf.TenantResolution.Resolvers, "path") {
+		app.Use(r.TenantPathMiddleware())
+	}
 	app.Use(r.LoggerMiddleware())
 	app.Use(r.FieldMiddleware())
 	app.Use(r.AuthenticationMiddleware())
 	app.Use(r.TenantMiddleware())
+	app.Use(r.RequestLimitMiddleware(limiter))
+	app.Use(r.QuotaMiddleware())
 	// app.Use(r.AuthorizationMiddleware()) // TODO: pendiente definir método de manejo de permisos
 	// app.Use(r.FilterMiddleware()) // TODO: pendiente definir método de manejo de filtros que llegan a sql para consultas dinámicas
 
@@ -82,6 +119,9 @@ func (r *Rest) Run() {
 		r.log.Error(context.Background(), "Error registering all tenants", "error", err)
 	}
 	admin.Register()
+	admin.StartJobs(context.Background())
+	go r.tenant.StartEviction(context.Background())
+	go r.tenant.StartHealthChecks(context.Background())
 
 	// carritocompra
 	apiCarrito.NewCarritoCompraAPI(r.log, apiGroup, r.conf, r.tenant).Register()
@@ -89,6 +129,9 @@ func (r *Rest) Run() {
 	// productos
 	apiProductos.NewProductosAPI(r.log, apiGroup, r.conf, r.tenant).Register()
 
+	// clientes
+	apiClientes.NewClientesAPI(r.log, apiGroup, r.conf, r.tenant).Register()
+
 	r.log.Info(context.Background(), "Rest API started")
 	host := net.JoinHostPort("0.0.0.0", fmt.Sprintf("%d", r.conf.Port))
 	if err := app.Listen(host); err != nil {
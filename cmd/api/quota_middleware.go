@@ -0,0 +1,34 @@
+package api
+
+import (
+	"api-test/src/common"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Cuenta las peticiones de cada tenant contra el límite mensual de su plan
+func (r *Rest) QuotaMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Las rutas sin tenant no se cuentan
+		if _, ok := c.Locals(r.tenant.TenantKey).(uuid.UUID); !ok {
+			return c.Next()
+		}
+
+		err := r.tenant.Consume(common.Context(c), common.ResourceAPICalls, 1)
+		if err != nil {
+			status := common.StatusCode(err, fiber.StatusInternalServerError)
+			if status == fiber.StatusTooManyRequests {
+				// El límite se reinicia al comenzar el mes
+				now := time.Now().UTC()
+				reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			}
+			r.log.Error(c.Context(), "Quota Middleware", "path", c.Path(), "status", status, "error", err.Error())
+			return fiber.NewError(status, err.Error())
+		}
+		return c.Next()
+	}
+}
@@ -1,35 +1,145 @@
 package api
 
 import (
+	"api-test/src/common/ratelimit"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/google/uuid"
 )
 
+// Límite de una petición: contador y peticiones permitidas por ventana
+type requestLimit struct {
+	key   string
+	limit int
+}
+
+// IP limit
+// Va antes de la autenticación para limitar también las peticiones sin token
+// o con un token inválido, que no llegan a RequestLimitMiddleware.
+func (r *Rest) IPLimitMiddleware(store ratelimit.Store) fiber.Handler {
+	conf := r.conf.RateLimit
+	window := time.Duration(conf.IPWindow) * time.Second
+
+	return func(c *fiber.Ctx) error {
+		if !conf.Enabled || conf.IPLimit <= 0 || window <= 0 {
+			return c.Next()
+		}
+		count, reset, err := store.Increment(c.Context(), "pre_auth:ip:"+c.IP(), window)
+		if err != nil {
+			r.log.Warn(c.Context(), "IP Limit Middleware", "path", c.Path(), "error", err.Error())
+			return c.Next()
+		}
+		if count > int64(conf.IPLimit) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(int(time.Until(reset).Seconds()+0.5), 1)))
+			r.log.Error(c.Context(), "IP Limit Middleware", "path", c.Path(), "status", 429, "ip", c.IP())
+			return fiber.NewError(fiber.StatusTooManyRequests, "Too many requests")
+		}
+		return c.Next()
+	}
+}
+
 // Request limit
-func (r *Rest) RequestLimitMiddleware() fiber.Handler {
-	return limiter.New(limiter.Config{
-		Next: func(c *fiber.Ctx) bool {
-			return r.conf.IsDev()
-		},
-		Max:        20,
-		Expiration: 30 * time.Second,
-		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": true,
-				"msg":   "Too many requests",
-			})
-		},
-	})
+// Va después de la autenticación para identificar al usuario y al tenant. Las
+// cabeceras RateLimit-* informan el límite con menos peticiones disponibles.
+func (r *Rest) RequestLimitMiddleware(store ratelimit.Store) fiber.Handler {
+	conf := r.conf.RateLimit
+	window := time.Duration(conf.Window) * time.Second
+	// Prefijos más largos primero para aplicar el grupo más específico
+	groups := slices.SortedFunc(maps.Keys(conf.Groups), func(a, b string) int { return len(b) - len(a) })
+
+	return func(c *fiber.Ctx) error {
+		if !conf.Enabled {
+			return c.Next()
+		}
+
+		identity := r.requestLimitKey(c)
+		limits := []requestLimit{{key: identity, limit: r.planRequestLimit(c)}}
+		if i := slices.IndexFunc(groups, func(group string) bool { return strings.HasPrefix(c.Path(), group) }); i >= 0 {
+			limits = append(limits, requestLimit{key: "group:" + groups[i] + ":" + identity, limit: conf.Groups[groups[i]]})
+		}
+
+		var limit, remaining int64
+		var reset time.Time
+		exceeded, reported := false, false
+		for _, l := range limits {
+			if l.limit <= 0 {
+				continue
+			}
+			count, windowReset, err := store.Increment(c.Context(), l.key, window)
+			if err != nil {
+				// Si el almacenamiento falla la petición continúa sin límite
+				r.log.Warn(c.Context(), "Request Limit Middleware", "path", c.Path(), "error", err.Error())
+				continue
+			}
+			left := max(int64(l.limit)-count, 0)
+			if !reported || left < remaining {
+				limit, remaining, reset = int64(l.limit), left, windowReset
+				reported = true
+			}
+			exceeded = exceeded || count > int64(l.limit)
+		}
+		if !reported {
+			return c.Next()
+		}
+
+		seconds := strconv.Itoa(max(int(time.Until(reset).Seconds()+0.5), 1))
+		c.Set("RateLimit-Limit", strconv.FormatInt(limit, 10))
+		c.Set("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Set("RateLimit-Reset", seconds)
+		if exceeded {
+			c.Set(fiber.HeaderRetryAfter, seconds)
+			r.log.Error(c.Context(), "Request Limit Middleware", "path", c.Path(), "status", 429, "key", identity)
+			return fiber.NewError(fiber.StatusTooManyRequests, "Too many requests")
+		}
+		return c.Next()
+	}
+}
+
+// Identifica la petición con el primer valor disponible de RATE_LIMIT_KEY_BY.
+// Solo se usan el usuario y el tenant verificados por la autenticación, una
+// cabecera sin verificar permitiría a cada petición usar un contador nuevo.
+func (r *Rest) requestLimitKey(c *fiber.Ctx) string {
+	for _, by := range r.conf.RateLimit.KeyBy {
+		switch by {
+		case "user":
+			if id, ok := c.Locals(r.tenant.UserIDKey).(uuid.UUID); ok {
+				return "user:" + id.String()
+			}
+		case "tenant":
+			if id, ok := c.Locals(r.tenant.TenantKey).(uuid.UUID); ok {
+				return "tenant:" + id.String()
+			}
+		case "ip":
+			return "ip:" + c.IP()
+		}
+	}
+	return "ip:" + c.IP()
+}
+
+// Límite del plan del tenant, RATE_LIMIT_DEFAULT sin tenant o sin plan configurado
+func (r *Rest) planRequestLimit(c *fiber.Ctx) int {
+	if id, ok := c.Locals(r.tenant.TenantKey).(uuid.UUID); ok {
+		if config, err := r.tenant.GetTenantConfig(id); err == nil && config.Plan != "" {
+			if limit, ok := r.conf.RateLimit.Plans[config.Plan]; ok {
+				return limit
+			}
+		}
+	}
+	return r.conf.RateLimit.Default
 }
 
 // CORS
 func (r *Rest) CORSMiddleware() fiber.Handler {
 	return cors.New(cors.Config{
-		AllowOrigins: "*", // TODO: Cambiar a lista de dominios
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization, X-Tenant-ID",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
+		AllowOrigins:  "*", // TODO: Cambiar a lista de dominios
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-Tenant-ID, X-Read-Your-Writes",
+		AllowMethods:  "GET, POST, PUT, DELETE, OPTIONS",
+		ExposeHeaders: "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After",
 	})
 }
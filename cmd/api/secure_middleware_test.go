@@ -0,0 +1,121 @@
+package api
+
+import (
+	"api-test/src/common"
+	"api-test/src/common/ratelimit"
+	"api-test/src/config"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func Test_IPLimitMiddleware(t *testing.T) {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.RateLimit.Enabled = true
+	conf.RateLimit.IPLimit = 2
+	conf.RateLimit.IPWindow = 30
+	r := &Rest{conf: conf, log: common.NewLogger(), tenant: common.NewTenantConnectionManager(conf)}
+
+	app := fiber.New()
+	app.Use(r.IPLimitMiddleware(ratelimit.NewMemoryStore()))
+	// Simula la autenticación que rechaza las peticiones sin token
+	app.Use(func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	})
+
+	want := []int{401, 401, 429}
+	for i, status := range want {
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/productos", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != status {
+			t.Fatalf("request %d: got %d, want %d", i+1, resp.StatusCode, status)
+		}
+		if status == 429 && resp.Header.Get(fiber.HeaderRetryAfter) == "" {
+			t.Error("missing Retry-After")
+		}
+	}
+}
+
+func Test_IPLimitMiddleware_TrustedProxy(t *testing.T) {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.RateLimit.Enabled = true
+	conf.RateLimit.IPLimit = 1
+	conf.RateLimit.IPWindow = 30
+	conf.Proxy.Header = "X-Real-IP"
+
+	// app.Test conecta desde 0.0.0.0
+	for _, tc := range []struct {
+		name    string
+		trusted []string
+		want    []int
+	}{
+		// Sin proxy de confianza la cabecera se ignora y cuenta la IP de la conexión
+		{name: "untrusted", want: []int{200, 429}},
+		{name: "trusted", trusted: []string{"0.0.0.0"}, want: []int{200, 200}},
+	} {
+		conf.Proxy.TrustedProxies = tc.trusted
+		r := &Rest{conf: conf, log: common.NewLogger(), tenant: common.NewTenantConnectionManager(conf)}
+		app := fiber.New(r.fiberConfig())
+		app.Use(r.IPLimitMiddleware(ratelimit.NewMemoryStore()))
+		app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+		for i, status := range tc.want {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-Real-IP", fmt.Sprintf("203.0.113.%d", i+1))
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != status {
+				t.Fatalf("%s request %d: got %d, want %d", tc.name, i+1, resp.StatusCode, status)
+			}
+		}
+	}
+}
+
+func Test_requestLimitKey(t *testing.T) {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.RateLimit.KeyBy = []string{"user", "tenant", "ip"}
+	r := &Rest{conf: conf, log: common.NewLogger(), tenant: common.NewTenantConnectionManager(conf)}
+	userID, tenantID := uuid.New(), uuid.New()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		if c.Get("X-User") != "" {
+			c.Locals(r.tenant.UserIDKey, userID)
+		}
+		if c.Get("X-Tenant") != "" {
+			c.Locals(r.tenant.TenantKey, tenantID)
+		}
+		return c.SendString(r.requestLimitKey(c))
+	})
+
+	for _, tc := range []struct {
+		headers map[string]string
+		want    string
+	}{
+		// Una api key sin verificar no cambia el contador de la petición
+		{headers: map[string]string{"X-API-Key": "random"}, want: "ip:0.0.0.0"},
+		{headers: map[string]string{"X-API-Key": "random", "X-Tenant": "1"}, want: "tenant:" + tenantID.String()},
+		{headers: map[string]string{"X-User": "1", "X-Tenant": "1"}, want: "user:" + userID.String()},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		for name, value := range tc.headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.headers, body, tc.want)
+		}
+	}
+}
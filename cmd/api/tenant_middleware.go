@@ -3,9 +3,9 @@ package api
 import (
 	"api-test/src/common"
 	"slices"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // Tenant Middleware para capturar el tenant ID
@@ -24,17 +24,17 @@ func (r *Rest) TenantMiddleware() fiber.Handler {
 		if c.Method() == "GET" && c.Path() == "/api/v1/tenants" {
 			return c.Next()
 		}
-
-		// Capturar el tenant ID del header "X-Tenant-Id"
-		tenantID := c.GetReqHeaders()["X-Tenant-Id"]
-		if len(tenantID) == 0 {
-			r.log.Error(c.Context(), "Tenant Middleware", "path", c.Path(), "status", 401, "error", "No tenant found")
-			return fiber.NewError(401, "No tenant found assigned")
+		// Excluir la administración de tenants (/tenants/:id), el caso de uso exige
+		// ser miembro, dueño u operador según la acción
+		if strings.HasPrefix(c.Path(), "/api/v1/tenants/") {
+			return c.Next()
 		}
-		tenantUUID, err := uuid.Parse(tenantID[0])
+
+		// Resolver el tenant en el orden de TENANT_RESOLVERS
+		tenantUUID, err := r.resolveTenant(c)
 		if err != nil {
 			r.log.Error(c.Context(), "Tenant Middleware", "path", c.Path(), "status", 401, "error", err.Error())
-			return fiber.NewError(401, "Invalid tenant ID")
+			return fiber.NewError(401, err.Error())
 		}
 
 		// Validar el JWT
@@ -51,8 +51,24 @@ func (r *Rest) TenantMiddleware() fiber.Handler {
 			return fiber.NewError(401, err.Error())
 		}
 
+		// Rechazar tenants suspendidos o en aprovisionamiento
+		if config, err := r.tenant.GetTenantConfig(tenantUUID); err == nil {
+			if config.Suspended {
+				r.log.Error(c.Context(), "Tenant Middleware", "path", c.Path(), "status", 403, "error", "Tenant suspended")
+				return fiber.NewError(403, "Tenant is suspended, contact the administrator to reactivate it")
+			}
+			if config.Provisioning {
+				r.log.Error(c.Context(), "Tenant Middleware", "path", c.Path(), "status", 409, "error", "Tenant provisioning")
+				return fiber.NewError(409, "Tenant is still being provisioned")
+			}
+		}
+
 		// Inyectar el tenant ID en el contexto
 		c.Locals(r.tenant.TenantKey, tenantUUID)
+		// Leer de la conexión principal lo que se acaba de escribir
+		if strings.EqualFold(c.Get("X-Read-Your-Writes"), "true") {
+			c.Locals(common.ReadPrimaryKey, true)
+		}
 
 		return c.Next()
 	}
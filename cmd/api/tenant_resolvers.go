@@ -0,0 +1,127 @@
+package api
+
+import (
+	"api-test/src/common"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Slug del tenant tomado del prefijo /t/{slug} de la ruta
+const tenantSlugLocal = "TenantSlug"
+
+// Obtiene el tenant de la petición, false si el resolver no aplica
+type tenantResolver func(c *fiber.Ctx) (uuid.UUID, bool, error)
+
+// Resolvers en el orden de TENANT_RESOLVERS
+func (r *Rest) tenantResolvers() []tenantResolver {
+	available := map[string]tenantResolver{
+		"header":    r.headerResolver,
+		"subdomain": r.subdomainResolver,
+		"path":      r.pathResolver,
+		"claim":     r.claimResolver,
+	}
+	var resolvers []tenantResolver
+	for _, name := range r.conf.TenantResolution.Resolvers {
+		if resolver, ok := available[strings.TrimSpace(name)]; ok {
+			resolvers = append(resolvers, resolver)
+		}
+	}
+	return resolvers
+}
+
+// Retorna el tenant del primer resolver que lo encuentra
+func (r *Rest) resolveTenant(c *fiber.Ctx) (uuid.UUID, error) {
+	for _, resolver := range r.resolvers {
+		tenantID, ok, err := resolver(c)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if ok {
+			return tenantID, nil
+		}
+	}
+	return uuid.Nil, errors.New("No tenant found assigned")
+}
+
+// Header X-Tenant-Id con el id del tenant
+func (r *Rest) headerResolver(c *fiber.Ctx) (uuid.UUID, bool, error) {
+	header := c.Get("X-Tenant-Id")
+	if header == "" {
+		return uuid.Nil, false, nil
+	}
+	tenantID, err := uuid.Parse(header)
+	if err != nil {
+		return uuid.Nil, false, errors.New("Invalid tenant ID")
+	}
+	return tenantID, true, nil
+}
+
+// Subdominio de TENANT_BASE_DOMAIN con el slug del tenant
+func (r *Rest) subdomainResolver(c *fiber.Ctx) (uuid.UUID, bool, error) {
+	base := r.conf.TenantResolution.BaseDomain
+	host := c.Hostname()
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	slug, ok := strings.CutSuffix(host, "."+base)
+	if base == "" || !ok || slug == "" || strings.Contains(slug, ".") {
+		return uuid.Nil, false, nil
+	}
+	return r.slugResolver(slug)
+}
+
+// Prefijo /t/{slug} de la ruta, quitado por TenantPathMiddleware
+func (r *Rest) pathResolver(c *fiber.Ctx) (uuid.UUID, bool, error) {
+	slug, ok := c.Locals(tenantSlugLocal).(string)
+	if !ok {
+		return uuid.Nil, false, nil
+	}
+	return r.slugResolver(slug)
+}
+
+// Tenant por defecto del token, o el único al que tiene acceso el usuario
+func (r *Rest) claimResolver(c *fiber.Ctx) (uuid.UUID, bool, error) {
+	claims, err := common.ValidateJWT(c.Context(), c.Get("Authorization"), r.conf)
+	if err != nil {
+		return uuid.Nil, false, nil
+	}
+	if claims.DefaultTenant != uuid.Nil {
+		return claims.DefaultTenant, true, nil
+	}
+	if len(claims.Tenants) == 1 {
+		return claims.Tenants[0], true, nil
+	}
+	return uuid.Nil, false, nil
+}
+
+func (r *Rest) slugResolver(slug string) (uuid.UUID, bool, error) {
+	tenantID, ok := r.tenant.TenantBySlug(slug)
+	if !ok {
+		return uuid.Nil, false, fmt.Errorf("unknown tenant: %s", slug)
+	}
+	return tenantID, true, nil
+}
+
+// TenantPathMiddleware quita el prefijo /t/{slug} de la ruta para que las
+// rutas y las exclusiones de los demás middlewares no cambien
+func (r *Rest) TenantPathMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rest, ok := strings.CutPrefix(c.Path(), "/t/")
+		if !ok {
+			return c.Next()
+		}
+		slug, path, _ := strings.Cut(rest, "/")
+		if slug == "" {
+			return c.Next()
+		}
+		// c.Path reutiliza el buffer de la petición, se copia antes de reescribirla
+		c.Locals(tenantSlugLocal, strings.Clone(slug))
+		c.Path("/" + path)
+		return c.Next()
+	}
+}
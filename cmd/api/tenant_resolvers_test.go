@@ -0,0 +1,71 @@
+package api
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func Test_resolveTenant(t *testing.T) {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.TenantResolution.Resolvers = []string{"path", "subdomain", "header"}
+	conf.TenantResolution.BaseDomain = "api.example.com"
+	manager := common.NewTenantConnectionManager(conf)
+	acme := uuid.New()
+	_ = manager.RegisterTenant(&common.TenantConfig{TenantID: acme, Slug: "acme"}, nil)
+
+	r := &Rest{conf: conf, tenant: manager}
+	r.resolvers = r.tenantResolvers()
+	app := fiber.New()
+	app.Use(r.TenantPathMiddleware())
+	app.Get("/api/v1/productos", func(c *fiber.Ctx) error {
+		tenantID, err := r.resolveTenant(c)
+		if err != nil {
+			return fiber.NewError(401, err.Error())
+		}
+		return c.SendString(tenantID.String())
+	})
+
+	header := uuid.New()
+	tests := []struct {
+		name   string
+		target string
+		header string
+		status int
+		want   string
+	}{
+		{name: "path", target: "/t/acme/api/v1/productos", status: 200, want: acme.String()},
+		{name: "subdomain", target: "http://acme.api.example.com/api/v1/productos", status: 200, want: acme.String()},
+		{name: "path before header", target: "/t/acme/api/v1/productos", header: header.String(), status: 200, want: acme.String()},
+		{name: "header", target: "/api/v1/productos", header: header.String(), status: 200, want: header.String()},
+		{name: "unknown slug", target: "/t/other/api/v1/productos", status: 401},
+		{name: "no tenant", target: "/api/v1/productos", status: 401},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-Id", tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.want == "" {
+				return
+			}
+			body := make([]byte, 64)
+			n, _ := resp.Body.Read(body)
+			if got := string(body[:n]); got != tt.want {
+				t.Errorf("expected tenant %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
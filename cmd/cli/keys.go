@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/usecase"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const keysUsage = `usage: keys <command>
+
+commands:
+  rotate        re-wrap the data key of every tenant with ENCRYPTION_ACTIVE_KEY
+  credentials   rotate the database password of every ready tenant, running
+                instances reload the new passwords on their next credentials sync
+`
+
+// Keys administra las claves de cifrado de las credenciales de los tenants
+type Keys struct {
+	log     common.Logger
+	tenants usecase.Tenant
+	out     io.Writer
+}
+
+// Run retorna el código de salida del proceso
+func (k *Keys) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keysUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "rotate":
+		report, err := k.tenants.RotateEncryptionKey(ctx)
+		if err != nil {
+			k.log.Error(ctx, "Error rotating encryption key", "error", err)
+			return 1
+		}
+		return k.write(ctx, report, report.Failed)
+	case "credentials":
+		report, err := k.tenants.RotateAllCredentials(ctx, 0)
+		if err != nil {
+			k.log.Error(ctx, "Error rotating credentials", "error", err)
+			return 1
+		}
+		return k.write(ctx, report, report.Failed)
+	}
+	fmt.Fprint(os.Stderr, keysUsage)
+	return 2
+}
+
+// Escribe el reporte y retorna 1 si hubo tenants fallidos
+func (k *Keys) write(ctx context.Context, report any, failed int) int {
+	encoder := json.NewEncoder(k.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		k.log.Error(ctx, "Error writing result", "error", err)
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func NewKeys(log common.Logger, tenants usecase.Tenant) *Keys {
+	return &Keys{
+		log:     log,
+		tenants: tenants,
+		out:     os.Stdout,
+	}
+}
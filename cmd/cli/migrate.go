@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/usecase"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+const migrateUsage = `usage: migrate <command> [flags]
+
+commands:
+  status     show the migration status of the admin database, a tenant or every tenant
+  up-to      migrate a tenant up to a version
+  down-to    roll back a tenant down to a version
+  seed       apply the pending seeds of a tenant
+`
+
+// Migrate ejecuta los comandos de migraciones desde la línea de comandos
+type Migrate struct {
+	log        common.Logger
+	migrations usecase.TenantMigrations
+	out        io.Writer
+}
+
+// Run retorna el código de salida del proceso
+func (m *Migrate) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	var (
+		result any
+		err    error
+	)
+	switch args[0] {
+	case "status":
+		result, err = m.status(ctx, args[1:])
+	case "up-to":
+		result, err = m.migrateTo(ctx, domain.MigrationDirectionUp, args[1:])
+	case "down-to":
+		result, err = m.migrateTo(ctx, domain.MigrationDirectionDown, args[1:])
+	case "seed":
+		result, err = m.seed(ctx, args[1:])
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	if err != nil {
+		m.log.Error(ctx, "Error running migrate command", "command", args[0], "error", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(m.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		m.log.Error(ctx, "Error writing result", "error", err)
+		return 1
+	}
+	return 0
+}
+
+func (m *Migrate) status(ctx context.Context, args []string) (any, error) {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	admin := flags.Bool("admin", false, "show the admin database status")
+	tenant := flags.String("tenant", "", "tenant id, every tenant when empty")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *admin {
+		return m.migrations.GetAdminMigrationStatus(ctx)
+	}
+	if *tenant == "" {
+		return m.migrations.GetFleetMigrationStatus(ctx)
+	}
+	tenantID, err := uuid.Parse(*tenant)
+	if err != nil {
+		return nil, err
+	}
+	return m.migrations.GetTenantMigrationStatus(ctx, tenantID)
+}
+
+func (m *Migrate) migrateTo(ctx context.Context, direction string, args []string) (any, error) {
+	flags := flag.NewFlagSet(direction+"-to", flag.ContinueOnError)
+	tenant := flags.String("tenant", "", "tenant id")
+	version := flags.Int64("version", -1, "target version")
+	reason := flags.String("reason", "", "reason recorded in the audit log")
+	dryRun := flags.Bool("dry-run", false, "only list the statements to be executed")
+	confirm := flags.Bool("confirm-destructive", false, "allow rolling back destructive migrations")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	tenantID, err := uuid.Parse(*tenant)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant id: %w", err)
+	}
+	if *version < 0 {
+		return nil, errors.New("a version is required")
+	}
+	return m.migrations.MigrateTenantTo(ctx, tenantID, direction, domain.DTOMigrationTarget{
+		Version:            *version,
+		Reason:             *reason,
+		DryRun:             *dryRun,
+		ConfirmDestructive: *confirm,
+	})
+}
+
+func (m *Migrate) seed(ctx context.Context, args []string) (any, error) {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	tenant := flags.String("tenant", "", "tenant id")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	tenantID, err := uuid.Parse(*tenant)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant id: %w", err)
+	}
+	return m.migrations.RunSeeds(ctx, tenantID)
+}
+
+func NewMigrate(log common.Logger, migrations usecase.TenantMigrations) *Migrate {
+	return &Migrate{
+		log:        log,
+		migrations: migrations,
+		out:        os.Stdout,
+	}
+}
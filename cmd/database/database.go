@@ -4,9 +4,11 @@ import (
 	"api-test/src/common"
 	"api-test/src/config"
 	"api-test/src/database/postgres"
+	"api-test/src/modules/admin/repository/implements"
 	"api-test/src/modules/admin/usecase"
 	"context"
 	"embed"
+	"net/url"
 )
 
 type Database struct {
@@ -20,14 +22,17 @@ type Database struct {
 func NewDatabase(conf *config.Config, log common.Logger, tenant *common.TenantConnectionManager,
 	adminMigrationsFS,
 	tenantMigrationsFS,
-	commonMigrationsFS embed.FS) *Database {
+	commonMigrationsFS,
+	seedsFS embed.FS) *Database {
 	adminMigrations := usecase.NewTenantMigrations(
 		log,
 		conf,
 		tenant,
+		implements.NewAuditRepository(log, tenant),
 		adminMigrationsFS,
 		tenantMigrationsFS,
-		commonMigrationsFS)
+		commonMigrationsFS,
+		seedsFS)
 	return &Database{
 		conf:            conf,
 		log:             log,
@@ -51,6 +56,32 @@ func (d *Database) Run() error {
 	if err != nil {
 		return err
 	}
+	// Base de datos de los tenants con schema propio, se abre en el primer uso
+	sharedDSN, err := withDatabase(d.conf.DSN, d.conf.SharedDBName, "")
+	if err != nil {
+		return err
+	}
+	err = d.tenant.RegisterTenant(&common.TenantConfig{
+		TenantID:         d.tenant.SharedDBID(),
+		Name:             "KOSVI shared",
+		ConnectionString: sharedDSN,
+	}, psql.Connect)
+	if err != nil {
+		return err
+	}
+	// Schema de las tablas compartidas, solo para migrarlas
+	pooledDSN, err := withDatabase(d.conf.DSN, d.conf.SharedDBName, d.conf.PooledSchema)
+	if err != nil {
+		return err
+	}
+	err = d.tenant.RegisterTenant(&common.TenantConfig{
+		TenantID:         d.tenant.PooledDBID(),
+		Name:             "KOSVI pooled",
+		ConnectionString: pooledDSN,
+	}, psql.Connect)
+	if err != nil {
+		return err
+	}
 	d.log.Info(context.Background(), "Database KOSVI Started")
 	if err := d.AdminMigrations(); err != nil {
 		return err
@@ -70,6 +101,40 @@ func (d *Database) AdminMigrations() error {
 	return nil
 }
 
+// Tenants retorna el caso de uso de tenants para los comandos de consola
+func (d *Database) Tenants() usecase.Tenant {
+	return usecase.NewTenant(d.log,
+		implements.NewTenantRepository(d.log, d.tenant),
+		implements.NewJobRepository(d.log, d.tenant),
+		implements.NewTenantEventRepository(d.log, d.tenant),
+		implements.NewTenantDataRepository(d.log, d.tenant),
+		implements.NewPlanRepository(d.log, d.tenant),
+		implements.NewSettingsRepository(d.log, d.tenant),
+		d.adminMigrations, d.conf, d.tenant, d.psql)
+}
+
+// RegisterTenants registra la configuración de todos los tenants listos, las
+// conexiones se abren en el primer uso
+func (d *Database) RegisterTenants() error {
+	return d.Tenants().RegisterAllTenants(context.Background())
+}
+
+// Cambia la base de datos del dsn conservando el usuario y los parámetros,
+// con schema fija además el search_path
+func withDatabase(dsn string, database string, schema string) (string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+	u.Path = "/" + database
+	if schema != "" {
+		query := u.Query()
+		query.Set("search_path", schema+",public")
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
 func (d *Database) Stop() error {
 	d.log.Info(context.Background(), "Stopping Database")
 	return d.tenant.CloseAll()
@@ -3,19 +3,37 @@ package main
 import (
 	"api-test/cmd/api"
 	"api-test/cmd/banner"
+	"api-test/cmd/cli"
 	"api-test/cmd/database"
 	"api-test/src/common"
+	"api-test/src/common/kms"
+	"api-test/src/common/ratelimit"
 	"api-test/src/config"
 	"api-test/src/database/postgres"
+	"api-test/src/database/redis"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository/implements"
 	"api-test/src/modules/admin/usecase"
 	"context"
+	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 func main() {
+	migrateTenants := flag.Bool("migrate-tenants", false, "run tenant migrations for every registered tenant on startup")
+	migrateOnly := flag.Bool("migrate-only", false, "exit after running the startup migrations")
+	migrateConcurrency := flag.Int("migrate-concurrency", 0, "number of tenants migrated in parallel")
+	migrateTimeout := flag.Int("migrate-timeout", 0, "timeout in seconds for each tenant migration")
+	migrateContinue := flag.Bool("migrate-continue-on-error", false, "keep migrating the remaining tenants after a failure")
+	migrateTenantIDs := flag.String("migrate-tenant-ids", "", "comma separated tenant ids to migrate, all tenants when empty")
+	flag.Parse()
+
 	conf := config.NewConfig()
 	log := common.NewLogger()
 	if err := conf.Load(); err != nil {
@@ -25,6 +43,11 @@ func main() {
 		}
 		os.Exit(1)
 	}
+	// Validar el proveedor de claves antes de descifrar credenciales
+	if _, err := kms.New(conf); err != nil {
+		log.Error(context.Background(), "Error loading key provider", "provider", conf.KMS.Provider, "error", err)
+		os.Exit(1)
+	}
 	tenantManager := common.NewTenantConnectionManager(conf)
 	log = common.NewLoggerWithTenantManager(tenantManager)
 
@@ -35,22 +58,110 @@ func main() {
 		tenantManager,
 		postgres.Admins,
 		postgres.Tenants,
-		postgres.Common)
+		postgres.Common,
+		postgres.Seeds)
 	if err := database.Run(); err != nil {
 		log.Error(context.Background(), "Error starting database", "error", err)
 		os.Exit(1)
 	}
 
 	migrations := usecase.NewTenantMigrations(log, conf, tenantManager,
+		implements.NewAuditRepository(log, tenantManager),
 		postgres.Admins,
 		postgres.Tenants,
-		postgres.Common)
+		postgres.Common,
+		postgres.Seeds)
+
+	// Comandos de migraciones: api migrate <status|up-to|down-to> [flags]
+	if flag.Arg(0) == "migrate" {
+		if err := database.RegisterTenants(); err != nil {
+			log.Error(context.Background(), "Error registering tenants", "error", err)
+			os.Exit(1)
+		}
+		code := cli.NewMigrate(log, migrations).Run(context.Background(), flag.Args()[1:])
+		if err := database.Stop(); err != nil {
+			log.Error(context.Background(), "Error stopping database", "error", err)
+		}
+		os.Exit(code)
+	}
+
+	// Rotación de claves y contraseñas: api keys <rotate|credentials>
+	if flag.Arg(0) == "keys" {
+		code := cli.NewKeys(log, database.Tenants()).Run(context.Background(), flag.Args()[1:])
+		if err := database.Stop(); err != nil {
+			log.Error(context.Background(), "Error stopping database", "error", err)
+		}
+		os.Exit(code)
+	}
+
+	if *migrateTenants {
+		opts := domain.DTOFleetMigration{
+			Concurrency:     *migrateConcurrency,
+			TimeoutSeconds:  *migrateTimeout,
+			ContinueOnError: *migrateContinue,
+		}
+		for _, id := range strings.Split(*migrateTenantIDs, ",") {
+			if strings.TrimSpace(id) == "" {
+				continue
+			}
+			tenantID, err := uuid.Parse(strings.TrimSpace(id))
+			if err != nil {
+				log.Error(context.Background(), "Invalid tenant id", "tenant_id", id, "error", err)
+				os.Exit(1)
+			}
+			opts.TenantIDs = append(opts.TenantIDs, tenantID)
+		}
+		if err := database.RegisterTenants(); err != nil {
+			log.Error(context.Background(), "Error registering tenants", "error", err)
+			os.Exit(1)
+		}
+		report, err := migrations.RunFleetMigrations(context.Background(), opts)
+		if err != nil {
+			log.Error(context.Background(), "Error running tenant migrations", "error", err)
+			os.Exit(1)
+		}
+		for _, result := range report.Results {
+			if result.Status != domain.MigrationResultSucceeded {
+				log.Warn(context.Background(), "Tenant migration not applied", "tenant_id", result.TenantID, "status", result.Status, "error", result.Error)
+			}
+		}
+		if report.Failed > 0 && (*migrateOnly || !opts.ContinueOnError) {
+			os.Exit(1)
+		}
+	}
+	if *migrateOnly {
+		log.Info(context.Background(), "Migrations completed")
+		if err := database.Stop(); err != nil {
+			log.Error(context.Background(), "Error stopping database", "error", err)
+		}
+		return
+	}
 
 	if conf.IsDev() {
 		log.Warn(context.Background(), "Starting API in development mode")
 	}
+
+	// Redis se conecta solo si la caché o los límites de peticiones lo usan
+	var rdb redis.Redis
+	if conf.Cache.Store == "redis" || conf.RateLimit.Store == ratelimit.StoreRedis {
+		var err error
+		if rdb, err = redis.NewRedis(log, conf); err != nil {
+			log.Error(context.Background(), "Error connecting to redis", "error", err)
+			os.Exit(1)
+		}
+	}
+	switch conf.Cache.Store {
+	case "", "none":
+	case "memory":
+		tenantManager.SetCache(common.NewMemoryCache())
+	case "redis":
+		tenantManager.SetCache(rdb.Cache())
+	default:
+		log.Error(context.Background(), "Unknown cache store", "store", conf.Cache.Store)
+		os.Exit(1)
+	}
 	go func() {
-		api.NewRest(conf, log, tenantManager, database.PSQL(), migrations).Run()
+		api.NewRest(conf, log, tenantManager, database.PSQL(), migrations, rdb).Run()
 	}()
 
 	// graceful shutdown
@@ -64,5 +175,10 @@ func main() {
 	if err := database.Stop(); err != nil {
 		log.Error(ctx, "Error stopping database", "error", err)
 	}
+	if rdb != nil {
+		if err := rdb.Close(); err != nil {
+			log.Error(ctx, "Error closing redis", "error", err)
+		}
+	}
 	log.Info(ctx, "Shutting down")
 }
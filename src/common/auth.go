@@ -28,15 +28,20 @@ func GenerateJWT(ctx context.Context, config *config.Config, user domain.DTOUser
 
 	// Access Token
 	tenants := []uuid.UUID{}
+	defaultTenant := uuid.Nil
 	for _, tenant := range user.UserTenants {
 		tenants = append(tenants, tenant.TenantID)
+		if tenant.DefaultTenant {
+			defaultTenant = tenant.TenantID
+		}
 	}
 
 	expired := now.Add(time.Duration(config.JWT.TTL) * time.Second)
 	accessClaims := domain.Claims{
-		UserID:  user.ID,
-		Tenants: tenants,
-		Type:    domain.TokenTypeAccess,
+		UserID:        user.ID,
+		Tenants:       tenants,
+		DefaultTenant: defaultTenant,
+		Type:          domain.TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expired),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss indica que la clave no está en la caché o ya expiró
+var ErrCacheMiss = errors.New("cache: key not found")
+
+// Cache guarda valores con expiración. Las etiquetas agrupan claves para
+// invalidarlas juntas, por ejemplo todas las lecturas de una tabla.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set guarda el valor, ttl cero no expira
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	Delete(ctx context.Context, keys ...string) error
+	// TTL retorna el tiempo que le queda a la clave, cero si no expira
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// InvalidateTags elimina las claves guardadas con alguna de las etiquetas
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+// SetCache registra la caché de los repositorios, sin ella no se guardan lecturas
+func (m *TenantConnectionManager) SetCache(cache Cache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache = cache
+}
+
+// Cache retorna la caché de los repositorios, nil si no está configurada
+func (m *TenantConnectionManager) Cache() Cache {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cache
+}
+
+// Caché en memoria del proceso, para pruebas y despliegues de una instancia
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	tags    map[string]map[string]struct{}
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func NewMemoryCache() Cache {
+	return &memoryCache{
+		entries: map[string]memoryCacheEntry{},
+		tags:    map[string]map[string]struct{}{},
+	}
+}
+
+// Get implements Cache.
+func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entry(key)
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return entry.value, nil
+}
+
+// Set implements Cache.
+func (c *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.entries[key] = entry
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = map[string]struct{}{}
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+	return nil
+}
+
+// Delete implements Cache.
+func (c *memoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// TTL implements Cache.
+func (c *memoryCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entry(key)
+	if !ok {
+		return 0, ErrCacheMiss
+	}
+	if entry.expires.IsZero() {
+		return 0, nil
+	}
+	return time.Until(entry.expires), nil
+}
+
+// InvalidateTags implements Cache.
+func (c *memoryCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			delete(c.entries, key)
+		}
+		delete(c.tags, tag)
+	}
+	return nil
+}
+
+// Entrada vigente de la clave, las vencidas se eliminan al consultarlas
+func (c *memoryCache) entry(key string) (memoryCacheEntry, bool) {
+	entry, ok := c.entries[key]
+	if ok && !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		delete(c.entries, key)
+		return memoryCacheEntry{}, false
+	}
+	return entry, ok
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+func TestMemoryCache_GetSetTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+
+	if _, err := cache.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected cache miss, got %v", err)
+	}
+	if err := cache.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := cache.Get(ctx, "a"); err != nil || string(value) != "1" {
+		t.Fatalf("unexpected value %q %v", value, err)
+	}
+	if ttl, err := cache.TTL(ctx, "a"); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("unexpected ttl %s %v", ttl, err)
+	}
+
+	if err := cache.Set(ctx, "b", []byte("2"), 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(15 * time.Millisecond)
+	if _, err := cache.Get(ctx, "b"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected expired key, got %v", err)
+	}
+
+	if err := cache.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.TTL(ctx, "a"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected deleted key, got %v", err)
+	}
+}
+
+func TestMemoryCache_InvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+	_ = cache.Set(ctx, "p:1", []byte("1"), 0, "productos")
+	_ = cache.Set(ctx, "p:search", []byte("[]"), 0, "productos")
+	_ = cache.Set(ctx, "c:1", []byte("1"), 0, "clientes")
+
+	if err := cache.InvalidateTags(ctx, "productos"); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"p:1", "p:search"} {
+		if _, err := cache.Get(ctx, key); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("expected %s invalidated, got %v", key, err)
+		}
+	}
+	if _, err := cache.Get(ctx, "c:1"); err != nil {
+		t.Errorf("expected c:1 kept, got %v", err)
+	}
+}
+
+type cachedTestModel struct {
+	bun.BaseModel `bun:"table:productos"`
+
+	ID     int64  `bun:"id,pk"`
+	Nombre string `bun:"nombre"`
+}
+
+// Repositorio que cuenta las lecturas, los demás métodos no se usan
+type countingRepository struct {
+	Repository[cachedTestModel, int64]
+	items map[int64]cachedTestModel
+	reads int
+}
+
+func (r *countingRepository) GetById(ctx context.Context, id int64, relations ...string) (*cachedTestModel, error) {
+	r.reads++
+	item := r.items[id]
+	return &item, nil
+}
+
+func (r *countingRepository) Update(ctx context.Context, id int64, item cachedTestModel) (*cachedTestModel, error) {
+	r.items[id] = item
+	return &item, nil
+}
+
+func TestCachedRepository_InvalidatesOnWrite(t *testing.T) {
+	m := newTestManager(10)
+	m.SetCache(NewMemoryCache())
+	inner := &countingRepository{items: map[int64]cachedTestModel{1: {ID: 1, Nombre: "café"}}}
+	repo := NewCachedRepository(NewLogger(), m, Repository[cachedTestModel, int64](inner), time.Minute)
+
+	tenantA := context.WithValue(context.Background(), m.TenantKey, uuid.New())
+	tenantB := context.WithValue(context.Background(), m.TenantKey, uuid.New())
+	for range 2 {
+		if item, err := repo.GetById(tenantA, 1); err != nil || item.Nombre != "café" {
+			t.Fatalf("unexpected item %v %v", item, err)
+		}
+	}
+	if inner.reads != 1 {
+		t.Fatalf("expected 1 read, got %d", inner.reads)
+	}
+	// Las claves de cada tenant son distintas
+	if _, err := repo.GetById(tenantB, 1); err != nil {
+		t.Fatal(err)
+	}
+	if inner.reads != 2 {
+		t.Fatalf("expected 2 reads, got %d", inner.reads)
+	}
+
+	if _, err := repo.Update(tenantA, 1, cachedTestModel{ID: 1, Nombre: "té"}); err != nil {
+		t.Fatal(err)
+	}
+	if item, _ := repo.GetById(tenantA, 1); item.Nombre != "té" || inner.reads != 3 {
+		t.Fatalf("expected fresh read after update, got %q with %d reads", item.Nombre, inner.reads)
+	}
+}
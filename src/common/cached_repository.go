@@ -0,0 +1,226 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// Guarda en la caché las lecturas de GetById y Search. Las claves incluyen el
+// tenant y todas llevan la etiqueta de la tabla del tenant, cualquier
+// escritura la invalida. En las transacciones se invalida antes del commit,
+// una lectura concurrente puede guardar el valor anterior hasta que expire.
+type cachedRepository[Table any, ID any] struct {
+	Repository[Table, ID]
+	log    Logger
+	tenant *TenantConnectionManager
+	cache  Cache
+	ttl    time.Duration
+	table  string
+}
+
+// GetById implements Repository.
+func (r *cachedRepository[Table, ID]) GetById(ctx context.Context, id ID, relations ...string) (*Table, error) {
+	tenantID, ok := r.tenantID(ctx)
+	if !ok {
+		return r.Repository.GetById(ctx, id, relations...)
+	}
+	key := r.key(tenantID, "id", fmt.Sprint(id), strings.Join(relations, ","))
+	var item Table
+	if r.load(ctx, key, &item) {
+		return &item, nil
+	}
+
+	result, err := r.Repository.GetById(ctx, id, relations...)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, tenantID, key, result)
+	return result, nil
+}
+
+// Search implements Repository.
+func (r *cachedRepository[Table, ID]) Search(ctx context.Context, filters *QueryParams, relations ...string) ([]Table, error) {
+	tenantID, ok := r.tenantID(ctx)
+	if !ok {
+		return r.Repository.Search(ctx, filters, relations...)
+	}
+	raw, _ := json.Marshal(filters)
+	sum := sha256.Sum256(append(raw, strings.Join(relations, ",")...))
+	key := r.key(tenantID, "search", hex.EncodeToString(sum[:16]))
+	var items []Table
+	if r.load(ctx, key, &items) {
+		return items, nil
+	}
+
+	result, err := r.Repository.Search(ctx, filters, relations...)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, tenantID, key, result)
+	return result, nil
+}
+
+// Create implements Repository.
+func (r *cachedRepository[Table, ID]) Create(ctx context.Context, item Table) (*Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.Create(ctx, item)
+}
+
+// Update implements Repository.
+func (r *cachedRepository[Table, ID]) Update(ctx context.Context, id ID, item Table) (*Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.Update(ctx, id, item)
+}
+
+// Delete implements Repository.
+func (r *cachedRepository[Table, ID]) Delete(ctx context.Context, id ID) (int64, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.Delete(ctx, id)
+}
+
+// CreateMany implements Repository.
+func (r *cachedRepository[Table, ID]) CreateMany(ctx context.Context, items []Table) ([]Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.CreateMany(ctx, items)
+}
+
+// UpdateMany implements Repository.
+func (r *cachedRepository[Table, ID]) UpdateMany(ctx context.Context, items []Table) ([]Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.UpdateMany(ctx, items)
+}
+
+// DeleteMany implements Repository.
+func (r *cachedRepository[Table, ID]) DeleteMany(ctx context.Context, ids []ID) (int64, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.DeleteMany(ctx, ids)
+}
+
+// WithTransaction implements Repository.
+// Se invalida también después del commit para descartar lo leído durante la transacción.
+func (r *cachedRepository[Table, ID]) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error {
+	defer r.invalidate(ctx)
+	return r.Repository.WithTransaction(ctx, fn)
+}
+
+// CreateTx implements Repository.
+func (r *cachedRepository[Table, ID]) CreateTx(ctx context.Context, tx bun.Tx, item Table) (*Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.CreateTx(ctx, tx, item)
+}
+
+// UpdateTx implements Repository.
+func (r *cachedRepository[Table, ID]) UpdateTx(ctx context.Context, tx bun.Tx, id ID, item Table) (*Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.UpdateTx(ctx, tx, id, item)
+}
+
+// DeleteTx implements Repository.
+func (r *cachedRepository[Table, ID]) DeleteTx(ctx context.Context, tx bun.Tx, id ID) (int64, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.DeleteTx(ctx, tx, id)
+}
+
+// CreateManyTx implements Repository.
+func (r *cachedRepository[Table, ID]) CreateManyTx(ctx context.Context, tx bun.Tx, items []Table) ([]Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.CreateManyTx(ctx, tx, items)
+}
+
+// UpdateManyTx implements Repository.
+func (r *cachedRepository[Table, ID]) UpdateManyTx(ctx context.Context, tx bun.Tx, items []Table) ([]Table, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.UpdateManyTx(ctx, tx, items)
+}
+
+// DeleteManyTx implements Repository.
+func (r *cachedRepository[Table, ID]) DeleteManyTx(ctx context.Context, tx bun.Tx, ids []ID) (int64, error) {
+	defer r.invalidate(ctx)
+	return r.Repository.DeleteManyTx(ctx, tx, ids)
+}
+
+func (r *cachedRepository[Table, ID]) tenantID(ctx context.Context) (uuid.UUID, bool) {
+	tenantID, ok := ctx.Value(r.tenant.TenantKey).(uuid.UUID)
+	return tenantID, ok
+}
+
+// Clave tenant:tabla:partes
+func (r *cachedRepository[Table, ID]) key(tenantID uuid.UUID, parts ...string) string {
+	return tenantID.String() + ":" + r.table + ":" + strings.Join(parts, ":")
+}
+
+func (r *cachedRepository[Table, ID]) tag(tenantID uuid.UUID) string {
+	return tenantID.String() + ":" + r.table
+}
+
+// Si la caché falla se lee de la base de datos
+func (r *cachedRepository[Table, ID]) load(ctx context.Context, key string, dest any) bool {
+	raw, err := r.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrCacheMiss) {
+			r.log.Warn(ctx, "Error reading cache", "key", key, "error", err)
+		}
+		return false
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		r.log.Warn(ctx, "Error decoding cached value", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+func (r *cachedRepository[Table, ID]) store(ctx context.Context, tenantID uuid.UUID, key string, value any) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		r.log.Warn(ctx, "Error encoding cached value", "key", key, "error", err)
+		return
+	}
+	if err := r.cache.Set(ctx, key, raw, r.ttl, r.tag(tenantID)); err != nil {
+		r.log.Warn(ctx, "Error writing cache", "key", key, "error", err)
+	}
+}
+
+func (r *cachedRepository[Table, ID]) invalidate(ctx context.Context) {
+	tenantID, ok := r.tenantID(ctx)
+	if !ok {
+		return
+	}
+	if err := r.cache.InvalidateTags(ctx, r.tag(tenantID)); err != nil {
+		r.log.Error(ctx, "Error invalidating cache", "table", r.table, "tenant_id", tenantID, "error", err)
+	}
+}
+
+// NewCachedRepository agrega la caché del TenantConnectionManager a las
+// lecturas del repositorio. Sin caché configurada o si el modelo tiene
+// columnas cifradas, que quedarían descifradas en la caché, retorna el
+// repositorio sin cambios.
+func NewCachedRepository[Table any, ID any](log Logger, tenant *TenantConnectionManager, repo Repository[Table, ID], ttl time.Duration) Repository[Table, ID] {
+	cache := tenant.Cache()
+	if cache == nil {
+		return repo
+	}
+	model := reflect.TypeFor[Table]()
+	if encrypted, _ := encryptedModelOf(model); encrypted != nil {
+		log.Warn(context.Background(), "Model with encrypted columns is not cached", "model", model.Name())
+		return repo
+	}
+	return &cachedRepository[Table, ID]{
+		Repository: repo,
+		log:        log,
+		tenant:     tenant,
+		cache:      cache,
+		ttl:        ttl,
+		table:      pgdialect.New().Tables().Get(model).Name,
+	}
+}
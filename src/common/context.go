@@ -11,6 +11,8 @@ import (
 var (
 	TenantKey = "TenantID"
 	UserIDKey = "UserID"
+	// Las lecturas usan la conexión principal en lugar de las réplicas
+	ReadPrimaryKey = "ReadPrimary"
 )
 
 func Context(c *fiber.Ctx) context.Context {
@@ -20,6 +22,9 @@ func Context(c *fiber.Ctx) context.Context {
 		return c.Context()
 	}
 	ctx := context.WithValue(c.Context(), TenantKey, tenantID)
+	if primary, ok := c.Locals(ReadPrimaryKey).(bool); ok && primary {
+		ctx = WithPrimary(ctx)
+	}
 	userID, ok := c.Locals(UserIDKey).(uuid.UUID)
 	if !ok {
 		NewLogger().Warn(c.Context(), "User not found")
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -98,6 +99,33 @@ func ThirdPartyError(service string, err error) AppError {
 	}
 }
 
+// ServiceUnavailableError para recursos que no están disponibles temporalmente
+func ServiceUnavailableError(message string) AppError {
+	return AppError{
+		Type:    "service_unavailable",
+		Code:    http.StatusServiceUnavailable,
+		Message: message,
+	}
+}
+
+// QuotaExceededError para recursos que superan el límite del plan
+func QuotaExceededError(message string) AppError {
+	return AppError{
+		Type:    "quota_exceeded",
+		Code:    http.StatusPaymentRequired,
+		Message: message,
+	}
+}
+
+// TooManyRequestsError para límites de peticiones por período
+func TooManyRequestsError(message string) AppError {
+	return AppError{
+		Type:    "too_many_requests",
+		Code:    http.StatusTooManyRequests,
+		Message: message,
+	}
+}
+
 // InternalServerError para errores internos genéricos
 func InternalServerError(err error) AppError {
 	return AppError{
@@ -107,3 +135,12 @@ func InternalServerError(err error) AppError {
 		Internal: err,
 	}
 }
+
+// StatusCode devuelve el código HTTP de un AppError o el código por defecto
+func StatusCode(err error, fallback int) int {
+	var appErr AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return fallback
+}
@@ -0,0 +1,258 @@
+package common
+
+import (
+	"api-test/src/common/filters"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Prefijo de los valores cifrados a nivel de columna. El nombre de la columna
+// es dato asociado (AAD) y un valor no se puede mover a otra columna.
+const encryptedPrefix = "enc:v2:"
+
+// Columna de un modelo marcada con el tag encrypt. Con encrypt:"blind:<columna>"
+// se guarda además un blind index (HMAC) en esa columna para filtrar por igualdad.
+//
+//	Documento     string `bun:"documento" encrypt:"blind:documento_bidx"`
+//	DocumentoBIdx string `bun:"documento_bidx"`
+//	Direccion     string `bun:"direccion" encrypt:""`
+type encryptedField struct {
+	index       int
+	column      string
+	blindIndex  int
+	blindColumn string
+}
+
+type encryptedModel struct {
+	fields  []encryptedField
+	columns map[string]encryptedField
+}
+
+// Obtiene las columnas cifradas del modelo, nil si no tiene
+func encryptedModelOf(model reflect.Type) (*encryptedModel, error) {
+	for model.Kind() == reflect.Pointer {
+		model = model.Elem()
+	}
+	if model.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i := range model.NumField() {
+		columns[columnName(model.Field(i))] = i
+	}
+
+	m := &encryptedModel{columns: map[string]encryptedField{}}
+	for i := range model.NumField() {
+		field := model.Field(i)
+		tag, ok := field.Tag.Lookup("encrypt")
+		if !ok {
+			continue
+		}
+		if !isStringField(field.Type) {
+			return nil, fmt.Errorf("%s.%s: only string fields can be encrypted", model.Name(), field.Name)
+		}
+
+		encrypted := encryptedField{index: i, column: columnName(field), blindIndex: -1}
+		if blind, ok := strings.CutPrefix(tag, "blind:"); ok {
+			index, exists := columns[blind]
+			if !exists || !isStringField(model.Field(index).Type) {
+				return nil, fmt.Errorf("%s.%s: blind index column %s not found", model.Name(), field.Name, blind)
+			}
+			encrypted.blindIndex = index
+			encrypted.blindColumn = blind
+		} else if tag != "" {
+			return nil, fmt.Errorf("%s.%s: invalid encrypt tag %q", model.Name(), field.Name, tag)
+		}
+		m.fields = append(m.fields, encrypted)
+		m.columns[encrypted.column] = encrypted
+	}
+	if len(m.fields) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+func columnName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("bun"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func isStringField(t reflect.Type) bool {
+	return t.Kind() == reflect.String || (t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.String)
+}
+
+// Cifra las columnas del registro y calcula sus blind index. Los valores
+// vienen de los DTOs y siempre se cifran, aunque parezcan cifrados: el
+// repositorio sella cada registro una sola vez, sobre una copia.
+func (m *encryptedModel) seal(cipher *FieldCipher, item reflect.Value) error {
+	for _, field := range m.fields {
+		value, ok := stringValue(item.Field(field.index))
+		if !ok || value == "" {
+			continue
+		}
+		if field.blindIndex >= 0 {
+			setStringValue(item.Field(field.blindIndex), cipher.BlindIndex(value))
+		}
+		encrypted, err := cipher.Encrypt(value, field.column)
+		if err != nil {
+			return err
+		}
+		setStringValue(item.Field(field.index), encrypted)
+	}
+	return nil
+}
+
+// Descifra las columnas del registro
+func (m *encryptedModel) open(cipher *FieldCipher, item reflect.Value) error {
+	for _, field := range m.fields {
+		value, ok := stringValue(item.Field(field.index))
+		if !ok {
+			continue
+		}
+		plaintext, err := cipher.Decrypt(value, field.column)
+		if err != nil {
+			return fmt.Errorf("decrypting %s: %w", field.column, err)
+		}
+		setStringValue(item.Field(field.index), plaintext)
+	}
+	return nil
+}
+
+// Rewriter para el QueryBuilder: los filtros de igualdad sobre columnas
+// cifradas se aplican sobre el blind index, el resto de operadores no es posible
+func (m *encryptedModel) rewriter(cipher *FieldCipher) filters.FieldRewriter {
+	return func(field, operator string, value interface{}) (string, string, interface{}, error) {
+		encrypted, ok := m.columns[field]
+		if !ok {
+			return field, operator, value, nil
+		}
+
+		switch strings.ToLower(operator) {
+		case "isnull", "isnotnull":
+			return field, operator, value, nil
+		case "eq", "neq", "in", "notin":
+			if encrypted.blindColumn == "" {
+				return "", "", nil, BadRequestError(fmt.Sprintf("el campo %s está cifrado y no tiene blind index", field))
+			}
+		default:
+			return "", "", nil, BadRequestError(fmt.Sprintf("el campo %s está cifrado, solo admite eq, neq, in y notin", field))
+		}
+
+		switch v := value.(type) {
+		case string:
+			return encrypted.blindColumn, operator, cipher.BlindIndex(v), nil
+		case []interface{}:
+			indexes := make([]string, len(v))
+			for i, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return "", "", nil, BadRequestError(fmt.Sprintf("el campo %s requiere valores string", field))
+				}
+				indexes[i] = cipher.BlindIndex(s)
+			}
+			return encrypted.blindColumn, operator, indexes, nil
+		}
+		return "", "", nil, BadRequestError(fmt.Sprintf("el campo %s requiere valores string", field))
+	}
+}
+
+func stringValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	return v.String(), true
+}
+
+func setStringValue(v reflect.Value, value string) {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.ValueOf(&value))
+		return
+	}
+	v.SetString(value)
+}
+
+// FieldCipher cifra columnas con claves derivadas de la clave de campos del tenant
+type FieldCipher struct {
+	aead     cipher.AEAD
+	blindKey []byte
+}
+
+func NewFieldCipher(key []byte) (*FieldCipher, error) {
+	encryptionKey, err := hkdf.Key(sha256.New, key, nil, "field-encryption", 32)
+	if err != nil {
+		return nil, err
+	}
+	blindKey, err := hkdf.Key(sha256.New, key, nil, "blind-index", 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldCipher{aead: aead, blindKey: blindKey}, nil
+}
+
+// Encrypt cifra el valor de la columna, que se autentica como AAD
+func (c *FieldCipher) Encrypt(plaintext string, column string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(column))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt descifra el valor de la columna. Solo el valor vacío se acepta sin
+// cifrar, cualquier otro indica una escritura sin cifrar o un dato dañado.
+func (c *FieldCipher) Decrypt(value string, column string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return "", errors.New("value is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("invalid encrypted value")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():], []byte(column))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex es un HMAC determinístico del valor normalizado (sin espacios
+// al inicio o final y en minúsculas) para buscar por igualdad
+func (c *FieldCipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.blindKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,128 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type encryptedTestModel struct {
+	ID            int64   `bun:"id,pk"`
+	Documento     string  `bun:"documento" encrypt:"blind:documento_bidx"`
+	DocumentoBIdx string  `bun:"documento_bidx"`
+	Direccion     *string `bun:"direccion" encrypt:""`
+}
+
+func TestEncryptedModel_SealOpen(t *testing.T) {
+	model, err := encryptedModelOf(reflect.TypeFor[encryptedTestModel]())
+	if err != nil || model == nil {
+		t.Fatalf("expected encrypted model, got %v %v", model, err)
+	}
+	cipher, err := NewFieldCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	direccion := "Calle 10"
+	item := encryptedTestModel{ID: 1, Documento: "900123", Direccion: &direccion}
+	if err := model.seal(cipher, reflect.ValueOf(&item).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(item.Documento, encryptedPrefix) || !strings.HasPrefix(*item.Direccion, encryptedPrefix) {
+		t.Fatalf("expected encrypted values, got %q %q", item.Documento, *item.Direccion)
+	}
+	if item.DocumentoBIdx != cipher.BlindIndex(" 900123 ") {
+		t.Errorf("unexpected blind index %q", item.DocumentoBIdx)
+	}
+
+	if err := model.open(cipher, reflect.ValueOf(&item).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if item.Documento != "900123" || *item.Direccion != "Calle 10" {
+		t.Errorf("unexpected decrypted values %q %q", item.Documento, *item.Direccion)
+	}
+}
+
+func TestEncryptedModel_Rewriter(t *testing.T) {
+	model, _ := encryptedModelOf(reflect.TypeFor[encryptedTestModel]())
+	cipher, _ := NewFieldCipher(make([]byte, 32))
+	rewrite := model.rewriter(cipher)
+
+	field, _, value, err := rewrite("documento", "eq", "900123")
+	if err != nil || field != "documento_bidx" || value != cipher.BlindIndex("900123") {
+		t.Errorf("expected blind index filter, got %q %v %v", field, value, err)
+	}
+	if _, _, _, err := rewrite("documento", "contains", "900"); err == nil {
+		t.Error("expected error filtering encrypted column with contains")
+	}
+	if _, _, _, err := rewrite("direccion", "eq", "Calle 10"); err == nil {
+		t.Error("expected error filtering encrypted column without blind index")
+	}
+	if field, _, _, err := rewrite("id", "gt", 1); err != nil || field != "id" {
+		t.Errorf("expected unchanged filter, got %q %v", field, err)
+	}
+}
+
+func TestEncryptedModel_SealAlwaysEncrypts(t *testing.T) {
+	model, _ := encryptedModelOf(reflect.TypeFor[encryptedTestModel]())
+	cipher, _ := NewFieldCipher(make([]byte, 32))
+
+	// Un cliente envía un valor con el prefijo para guardarlo sin cifrar
+	forged := encryptedPrefix + "900123"
+	item := encryptedTestModel{ID: 1, Documento: forged}
+	if err := model.seal(cipher, reflect.ValueOf(&item).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if item.Documento == forged || item.DocumentoBIdx != cipher.BlindIndex(forged) {
+		t.Fatalf("forged value stored without encryption: %q", item.Documento)
+	}
+	if err := model.open(cipher, reflect.ValueOf(&item).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if item.Documento != forged {
+		t.Errorf("unexpected decrypted value %q", item.Documento)
+	}
+}
+
+func TestFieldCipher_ColumnBinding(t *testing.T) {
+	cipher, _ := NewFieldCipher(make([]byte, 32))
+	encrypted, err := cipher.Encrypt("900123", "documento")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := cipher.Decrypt(encrypted, "documento"); err != nil || plaintext != "900123" {
+		t.Fatalf("unexpected decrypted value %q %v", plaintext, err)
+	}
+	// El valor de una columna no se puede copiar a otra
+	if _, err := cipher.Decrypt(encrypted, "direccion"); err == nil {
+		t.Error("expected error decrypting a value moved to another column")
+	}
+}
+
+func TestFieldCipher_DecryptRejectsUnencrypted(t *testing.T) {
+	cipher, _ := NewFieldCipher(make([]byte, 32))
+	nonce := make([]byte, cipher.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		t.Fatal(err)
+	}
+	// Un valor cifrado sin la columna como AAD no se acepta
+	unbound := encryptedPrefix + base64.StdEncoding.EncodeToString(cipher.aead.Seal(nonce, nonce, []byte("Calle 10"), nil))
+	for _, value := range []string{unbound, "enc:v1:" + base64.StdEncoding.EncodeToString(nonce), "sin cifrar", encryptedPrefix + "no-base64!"} {
+		if _, err := cipher.Decrypt(value, "direccion"); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+	if plaintext, err := cipher.Decrypt("", "direccion"); err != nil || plaintext != "" {
+		t.Errorf("empty value should stay empty: %q %v", plaintext, err)
+	}
+
+	// Las columnas NULL no se descifran
+	model, _ := encryptedModelOf(reflect.TypeFor[encryptedTestModel]())
+	item := encryptedTestModel{ID: 1}
+	if err := model.open(cipher, reflect.ValueOf(&item).Elem()); err != nil {
+		t.Errorf("NULL column should be skipped: %v", err)
+	}
+}
@@ -8,8 +8,13 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// FieldRewriter permite cambiar el campo, operador o valor de un filtro antes
+// de aplicarlo, por ejemplo para filtrar columnas cifradas por su blind index
+type FieldRewriter func(field, operator string, value interface{}) (string, string, interface{}, error)
+
 type QueryBuilder struct {
-	parser *Parser
+	parser   *Parser
+	rewriter FieldRewriter
 }
 func NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{
@@ -17,6 +22,14 @@ func NewQueryBuilder() *QueryBuilder {
 	}
 }
 
+// WithRewriter retorna una copia del builder que aplica el rewriter a cada filtro
+func (qb *QueryBuilder) WithRewriter(rewriter FieldRewriter) *QueryBuilder {
+	return &QueryBuilder{
+		parser:   qb.parser,
+		rewriter: rewriter,
+	}
+}
+
 func (qb *QueryBuilder) ApplyFilters(query *bun.SelectQuery, filterData interface{}, modelName string) (*bun.SelectQuery, error) {
 	if filterData == nil {
 		return query, nil
@@ -116,6 +129,13 @@ func (qb *QueryBuilder) applyLogicalGroup(query *bun.SelectQuery, filters []inte
 
 
 func (qb *QueryBuilder) applyOperator(query *bun.SelectQuery, field, operator string, value interface{}) (*bun.SelectQuery, error) {
+	if qb.rewriter != nil {
+		var err error
+		field, operator, value, err = qb.rewriter(field, operator, value)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	isJsonField := strings.Contains(field, "->")
 	
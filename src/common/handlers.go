@@ -45,9 +45,11 @@ func (h *GenericHandler[CreateDTO, ResponseDTO, UpdateDTO, ID]) Create(c *fiber.
 	// Use case
 	result, err := h.UseCase.Create(Context(c), dto)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(Response[any]{
+		// Los errores de la aplicación (AppError) conservan su código, ej. 503 con el breaker abierto
+		status := StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(Response[any]{
 			Status:  "error",
-			Code:    fiber.StatusInternalServerError,
+			Code:    status,
 			Message: "Internal server error",
 			Errors: []APIError{
 				{
@@ -86,9 +88,10 @@ func (h *GenericHandler[CreateDTO, ResponseDTO, UpdateDTO, ID]) Get(c *fiber.Ctx
 	// Use case
 	result, err := h.UseCase.GetById(Context(c), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(Response[any]{
+		status := StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(Response[any]{
 			Status:  "error",
-			Code:    fiber.StatusInternalServerError,
+			Code:    status,
 			Message: "Error retrieving resource",
 			Errors: []APIError{
 				{
@@ -126,9 +129,10 @@ func (h *GenericHandler[CreateDTO, ResponseDTO, UpdateDTO, ID]) Search(c *fiber.
 	// Use case
 	result, err := h.UseCase.Search(Context(c), &filters)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(Response[any]{
+		status := StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(Response[any]{
 			Status:  "error",
-			Code:    fiber.StatusInternalServerError,
+			Code:    status,
 			Message: "Error retrieving resources",
 			Errors: []APIError{
 				{
@@ -192,9 +196,10 @@ func (h *GenericHandler[CreateDTO, ResponseDTO, UpdateDTO, ID]) Update(c *fiber.
 	// Use case
 	result, err := h.UseCase.Update(Context(c), id, dto)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(Response[any]{
+		status := StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(Response[any]{
 			Status:  "error",
-			Code:    fiber.StatusInternalServerError,
+			Code:    status,
 			Message: "Error updating resource",
 			Errors: []APIError{
 				{
@@ -233,9 +238,10 @@ func (h *GenericHandler[CreateDTO, ResponseDTO, UpdateDTO, ID]) Delete(c *fiber.
 	// Use case
 	err = h.UseCase.Delete(Context(c), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(Response[any]{
+		status := StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(Response[any]{
 			Status:  "error",
-			Code:    fiber.StatusInternalServerError,
+			Code:    status,
 			Message: "Error deleting resource",
 			Errors: []APIError{
 				{
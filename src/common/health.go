@@ -0,0 +1,169 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Estados de la conexión de un tenant
+const (
+	HealthUnknown  = "unknown"
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	// Circuit breaker abierto, las peticiones fallan de inmediato
+	HealthOpen = "open"
+)
+
+type TenantHealth struct {
+	TenantID uuid.UUID
+	// Tenant de la réplica, uuid.Nil para la conexión principal
+	ReplicaOf uuid.UUID
+	Name      string
+	State     string
+	Failures  int
+	LastCheck time.Time
+	LastError string
+	OpenedAt  time.Time
+}
+
+// Fallos seguidos de la conexión, openedAt es cero con el breaker cerrado
+type tenantHealth struct {
+	failures  int
+	lastCheck time.Time
+	lastError error
+	openedAt  time.Time
+}
+
+// Registra el resultado de abrir o probar la conexión del tenant.
+// Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) record(tenantID uuid.UUID, err error) {
+	health, exists := m.health[tenantID]
+	if !exists {
+		health = &tenantHealth{}
+		m.health[tenantID] = health
+	}
+	health.lastCheck = time.Now()
+	if err == nil {
+		*health = tenantHealth{lastCheck: health.lastCheck}
+		return
+	}
+
+	health.failures++
+	health.lastError = err
+	// Una prueba fallida vuelve a esperar OpenTimeout
+	if threshold := m.config.Health.FailureThreshold; threshold > 0 && health.failures >= threshold {
+		health.openedAt = health.lastCheck
+	}
+}
+
+// Error con el que fallan las peticiones mientras el breaker está abierto.
+// Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) breakerError(tenantID uuid.UUID) error {
+	health, exists := m.health[tenantID]
+	if !exists || health.openedAt.IsZero() {
+		return nil
+	}
+	return ThirdPartyError("tenant database", health.lastError)
+}
+
+// CheckHealth hace ping a las conexiones abiertas y, pasado OpenTimeout,
+// prueba de nuevo las de los tenants con el breaker abierto
+func (m *TenantConnectionManager) CheckHealth(ctx context.Context) {
+	timeout := time.Duration(m.config.Health.Timeout) * time.Second
+	openTimeout := time.Duration(m.config.Health.OpenTimeout) * time.Second
+
+	m.mu.Lock()
+	pings := map[uuid.UUID]*bun.DB{}
+	for tenantID, pool := range m.pools {
+		pings[tenantID] = pool.db
+	}
+	var probes []uuid.UUID
+	for tenantID, health := range m.health {
+		if health.openedAt.IsZero() {
+			continue
+		}
+		if time.Since(health.openedAt) < openTimeout {
+			// Aún no toca probarlo
+			delete(pings, tenantID)
+		} else if _, open := pings[tenantID]; !open {
+			probes = append(probes, tenantID)
+		}
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for tenantID, db := range pings {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			err := db.PingContext(pingCtx)
+
+			m.mu.Lock()
+			m.record(tenantID, err)
+			m.mu.Unlock()
+		}()
+	}
+	for _, tenantID := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Al abrir la conexión se registra el resultado
+			m.getDB(tenantID, true)
+		}()
+	}
+	wg.Wait()
+}
+
+// StartHealthChecks ejecuta CheckHealth periódicamente hasta cancelar el contexto
+func (m *TenantConnectionManager) StartHealthChecks(ctx context.Context) {
+	interval := time.Duration(m.config.Health.Interval) * time.Second
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.CheckHealth(ctx)
+		}
+	}
+}
+
+// Health retorna el estado de la conexión de los tenants registrados
+func (m *TenantConnectionManager) Health() []TenantHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]TenantHealth, 0, len(m.configs))
+	for tenantID, config := range m.configs {
+		status := TenantHealth{TenantID: tenantID, ReplicaOf: config.primary, Name: config.Name, State: HealthUnknown}
+		if health, exists := m.health[tenantID]; exists {
+			status.Failures = health.failures
+			status.LastCheck = health.lastCheck
+			status.OpenedAt = health.openedAt
+			if health.lastError != nil {
+				status.LastError = health.lastError.Error()
+			}
+			switch {
+			case !health.openedAt.IsZero():
+				status.State = HealthOpen
+			case health.failures > 0:
+				status.State = HealthDegraded
+			default:
+				status.State = HealthHealthy
+			}
+		}
+		result = append(result, status)
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+// Package kms define los proveedores de claves usados para envolver las
+// claves de datos de los tenants (envelope encryption).
+package kms
+
+import (
+	"api-test/src/config"
+	"context"
+	"fmt"
+)
+
+// KeyProvider envuelve y desenvuelve claves de datos con una clave de
+// cifrado de claves (KEK) que puede vivir fuera del proceso.
+type KeyProvider interface {
+	// Name identifica el proveedor (env, file, vault)
+	Name() string
+	// ActiveKeyID es la clave usada por Wrap
+	ActiveKeyID() string
+	// Wrap cifra la clave de datos con la clave activa y retorna su id
+	Wrap(ctx context.Context, plaintext []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap descifra una clave de datos envuelta con la clave keyID
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+const (
+	ProviderEnv   = "env"
+	ProviderFile  = "file"
+	ProviderVault = "vault"
+)
+
+// New crea el proveedor configurado en KMS_PROVIDER
+func New(conf *config.Config) (KeyProvider, error) {
+	switch conf.KMS.Provider {
+	case "", ProviderEnv:
+		return NewEnvProvider(conf.MasterEncryptionKey, conf.EncryptionKeys, conf.EncryptionActiveKey)
+	case ProviderFile:
+		return NewFileProvider(conf.KMS.KeyFile, conf.EncryptionActiveKey)
+	case ProviderVault:
+		return NewVaultProvider(VaultConfig{
+			Address:   conf.KMS.VaultAddress,
+			Token:     conf.KMS.VaultToken,
+			Namespace: conf.KMS.VaultNamespace,
+			Mount:     conf.KMS.VaultTransitMount,
+			Key:       conf.KMS.VaultTransitKey,
+		})
+	}
+	return nil, fmt.Errorf("kms: unknown provider %q", conf.KMS.Provider)
+}
@@ -0,0 +1,155 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Id de la clave definida en MASTER_ENCRYPTION_KEY
+const MasterKeyID = "master"
+
+// Proveedor con las claves cargadas en memoria, cifra con AES-256-GCM
+type localProvider struct {
+	name   string
+	keys   map[string][]byte
+	active string
+}
+
+// NewEnvProvider usa la clave maestra y las claves de ENCRYPTION_KEYS (id:base64,id:base64)
+func NewEnvProvider(masterKey, keys, activeKey string) (KeyProvider, error) {
+	provider := &localProvider{name: ProviderEnv, keys: map[string][]byte{}, active: activeKey}
+	if masterKey != "" {
+		key, err := decodeKey(masterKey)
+		if err != nil {
+			return nil, fmt.Errorf("kms: master key: %w", err)
+		}
+		provider.keys[MasterKeyID] = key
+	}
+	if err := provider.parse(strings.Split(keys, ",")); err != nil {
+		return nil, err
+	}
+	if provider.active == "" {
+		provider.active = MasterKeyID
+	}
+	return provider, provider.validate()
+}
+
+// NewFileProvider lee las claves de un archivo con una clave id:base64 por
+// línea. El archivo no debe tener permisos para el grupo ni para otros.
+func NewFileProvider(path, activeKey string) (KeyProvider, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("kms: key file %s must not be accessible by group or others (mode %s)", path, info.Mode().Perm())
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+
+	provider := &localProvider{name: ProviderFile, keys: map[string][]byte{}, active: activeKey}
+	if err := provider.parse(strings.Split(string(content), "\n")); err != nil {
+		return nil, err
+	}
+	// Con una sola clave no es necesario indicar la activa
+	if provider.active == "" && len(provider.keys) == 1 {
+		for id := range provider.keys {
+			provider.active = id
+		}
+	}
+	return provider, provider.validate()
+}
+
+func (p *localProvider) parse(entries []string) error {
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, value, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return errors.New("kms: invalid key, expected id:base64")
+		}
+		key, err := decodeKey(value)
+		if err != nil {
+			return fmt.Errorf("kms: key %s: %w", id, err)
+		}
+		p.keys[id] = key
+	}
+	return nil
+}
+
+func (p *localProvider) validate() error {
+	if _, ok := p.keys[p.active]; !ok {
+		return fmt.Errorf("kms: active key %q not found", p.active)
+	}
+	return nil
+}
+
+func (p *localProvider) Name() string {
+	return p.name
+}
+
+func (p *localProvider) ActiveKeyID() string {
+	return p.active
+}
+
+// Wrap implements KeyProvider. El resultado es nonce || ciphertext y el id de la clave es el AAD.
+func (p *localProvider) Wrap(_ context.Context, plaintext []byte) (string, []byte, error) {
+	aesGCM, err := newGCM(p.keys[p.active])
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, err
+	}
+	return p.active, aesGCM.Seal(nonce, nonce, plaintext, []byte(p.active)), nil
+}
+
+// Unwrap implements KeyProvider.
+func (p *localProvider) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("kms: key %q not found", keyID)
+	}
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aesGCM.NonceSize() {
+		return nil, errors.New("kms: invalid wrapped key")
+	}
+	nonce, ciphertext := wrapped[:aesGCM.NonceSize()], wrapped[aesGCM.NonceSize():]
+	return aesGCM.Open(nil, nonce, ciphertext, []byte(keyID))
+}
+
+func decodeKey(value string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("error decodificando clave: %w", err)
+	}
+	// AES-256 requiere 32 bytes
+	if len(key) != 32 {
+		return nil, errors.New("la clave debe ser de 32 bytes (256 bits)")
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,126 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func TestEnvProvider_Keys(t *testing.T) {
+	provider, err := NewEnvProvider(testKey(1), " k1:"+testKey(2)+", k2:"+testKey(3)+",", "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Name() != ProviderEnv || provider.ActiveKeyID() != "k2" {
+		t.Fatalf("unexpected provider: %s %s", provider.Name(), provider.ActiveKeyID())
+	}
+
+	// Las claves anteriores siguen abriendo lo que cifraron
+	ctx := context.Background()
+	for _, id := range []string{MasterKeyID, "k1", "k2"} {
+		local := provider.(*localProvider)
+		previous := local.active
+		local.active = id
+		keyID, wrapped, err := provider.Wrap(ctx, []byte("data key"))
+		local.active = previous
+		if err != nil || keyID != id {
+			t.Fatalf("%s: wrap failed: %s %v", id, keyID, err)
+		}
+		plain, err := provider.Unwrap(ctx, keyID, wrapped)
+		if err != nil || string(plain) != "data key" {
+			t.Errorf("%s: unwrap failed: %q %v", id, plain, err)
+		}
+		// El id de la clave es el AAD
+		if _, err := provider.Unwrap(ctx, "k1", wrapped); id != "k1" && err == nil {
+			t.Errorf("%s: unwrapped with another key id", id)
+		}
+	}
+	if _, err := provider.Unwrap(ctx, "missing", []byte("x")); err == nil {
+		t.Error("expected an error for an unknown key id")
+	}
+
+	// Sin clave activa se usa la maestra
+	provider, err = NewEnvProvider(testKey(1), "", "")
+	if err != nil || provider.ActiveKeyID() != MasterKeyID {
+		t.Errorf("expected the master key as active: %v", err)
+	}
+}
+
+func TestEnvProvider_InvalidKeys(t *testing.T) {
+	short := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16))
+	tests := map[string]struct{ master, keys, active string }{
+		"unknown active key": {testKey(1), "k1:" + testKey(2), "k2"},
+		"no keys":            {"", "", ""},
+		"short master key":   {short, "", ""},
+		"short key":          {testKey(1), "k1:" + short, "k1"},
+		"invalid base64":     {testKey(1), "k1:not-base64!", "k1"},
+		"missing id":         {testKey(1), ":" + testKey(2), ""},
+		"missing separator":  {testKey(1), testKey(2), ""},
+	}
+	for name, tt := range tests {
+		if _, err := NewEnvProvider(tt.master, tt.keys, tt.active); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func writeKeyFile(t *testing.T, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile respeta la umask, Chmod fija el modo pedido
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileProvider(t *testing.T) {
+	content := "# claves de los tenants\nk1:" + testKey(1) + "\n\nk2:" + testKey(2) + "\n"
+	provider, err := NewFileProvider(writeKeyFile(t, content, 0o600), "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Name() != ProviderFile || provider.ActiveKeyID() != "k2" {
+		t.Fatalf("unexpected provider: %s %s", provider.Name(), provider.ActiveKeyID())
+	}
+
+	// Con una sola clave es la activa
+	provider, err = NewFileProvider(writeKeyFile(t, "only:"+testKey(1), 0o400), "")
+	if err != nil || provider.ActiveKeyID() != "only" {
+		t.Errorf("expected the only key as active: %v", err)
+	}
+
+	if _, err := NewFileProvider(writeKeyFile(t, content, 0o600), ""); err == nil {
+		t.Error("expected an error without active key and several keys")
+	}
+	if _, err := NewFileProvider(writeKeyFile(t, content, 0o600), "k3"); err == nil {
+		t.Error("expected an error for an unknown active key")
+	}
+	if _, err := NewFileProvider(writeKeyFile(t, "k1:"+base64.StdEncoding.EncodeToString([]byte("short")), 0o600), "k1"); err == nil {
+		t.Error("expected an error for a short key")
+	}
+	if _, err := NewFileProvider(filepath.Join(t.TempDir(), "missing"), "k1"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestFileProvider_Permissions(t *testing.T) {
+	content := "k1:" + testKey(1)
+	for _, mode := range []os.FileMode{0o640, 0o604, 0o660, 0o644} {
+		_, err := NewFileProvider(writeKeyFile(t, content, mode), "k1")
+		if err == nil || !strings.Contains(err.Error(), "group or others") {
+			t.Errorf("mode %s: expected a permissions error, got %v", mode, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type VaultConfig struct {
+	Address   string
+	Token     string
+	Namespace string
+	Mount     string
+	Key       string
+	Client    *http.Client
+}
+
+// Proveedor compatible con el motor Transit de HashiCorp Vault. La clave de
+// datos nunca sale del proceso sin cifrar y la KEK nunca entra en él.
+type vaultProvider struct {
+	config VaultConfig
+	client *http.Client
+}
+
+func NewVaultProvider(config VaultConfig) (KeyProvider, error) {
+	if config.Address == "" || config.Token == "" || config.Key == "" {
+		return nil, errors.New("kms: vault requires VAULT_ADDR, VAULT_TOKEN and VAULT_TRANSIT_KEY")
+	}
+	if config.Mount == "" {
+		config.Mount = "transit"
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &vaultProvider{config: config, client: client}, nil
+}
+
+func (p *vaultProvider) Name() string {
+	return ProviderVault
+}
+
+func (p *vaultProvider) ActiveKeyID() string {
+	return p.config.Key
+}
+
+// Wrap implements KeyProvider.
+func (p *vaultProvider) Wrap(ctx context.Context, plaintext []byte) (string, []byte, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}
+	if err := p.call(ctx, "encrypt", p.config.Key, body, &response); err != nil {
+		return "", nil, err
+	}
+	return p.config.Key, []byte(response.Data.Ciphertext), nil
+}
+
+// Unwrap implements KeyProvider.
+func (p *vaultProvider) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"ciphertext": string(wrapped)}
+	if err := p.call(ctx, "decrypt", keyID, body, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
+
+func (p *vaultProvider) call(ctx context.Context, operation, key string, body any, result any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(p.config.Address, "/"), p.config.Mount, operation, url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", p.config.Token)
+	if p.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kms: vault %s: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		return fmt.Errorf("kms: vault %s failed with status %d: %s", operation, resp.StatusCode, strings.Join(vaultErr.Errors, "; "))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,72 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Stub del motor Transit: el "cifrado" solo agrega un prefijo al base64
+func newTransitStub(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/tenants":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"ciphertext": "vault:v1:" + body["plaintext"]}})
+		case "/v1/transit/decrypt/tenants":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVaultProvider_WrapUnwrap(t *testing.T) {
+	server := newTransitStub(t)
+	defer server.Close()
+
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "token", Key: "tenants"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID, wrapped, err := provider.Wrap(context.Background(), []byte("data-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "tenants" || !strings.HasPrefix(string(wrapped), "vault:v1:") {
+		t.Fatalf("unexpected wrap result %q %q", keyID, wrapped)
+	}
+	plaintext, err := provider.Unwrap(context.Background(), keyID, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "data-key" {
+		t.Errorf("expected data-key, got %q", plaintext)
+	}
+}
+
+func TestVaultProvider_Error(t *testing.T) {
+	server := newTransitStub(t)
+	defer server.Close()
+
+	provider, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "invalid", Key: "tenants"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := provider.Wrap(context.Background(), []byte("data-key")); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected permission denied, got %v", err)
+	}
+}
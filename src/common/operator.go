@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// IsOperator indica si el usuario del contexto es operador de la plataforma
+// (OPERATOR_USER_IDS). Solo los operadores actúan sobre todos los tenants o
+// cambian lo que afecta a los demás: migraciones de la flota, planes, pools,
+// réplicas y salud.
+func (m *TenantConnectionManager) IsOperator(ctx context.Context) bool {
+	userID, ok := ctx.Value(m.UserIDKey).(uuid.UUID)
+	return ok && userID != uuid.Nil && slices.Contains(m.config.Operators.UserIDs, userID)
+}
+
+// RequireOperator falla con ForbiddenError si el usuario del contexto no es operador
+func (m *TenantConnectionManager) RequireOperator(ctx context.Context) error {
+	if !m.IsOperator(ctx) {
+		return ForbiddenError("operator role required")
+	}
+	return nil
+}
+
+// OperatorOnly rechaza las peticiones de los usuarios que no son operadores
+func (m *TenantConnectionManager) OperatorOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !m.IsOperator(c.Context()) {
+			return fiber.NewError(fiber.StatusForbidden, "Operator role required")
+		}
+		return c.Next()
+	}
+}
+
+// TenantOrOperator permite la petición si el tenant del parámetro es el del
+// contexto, validado por TenantMiddleware contra el token, o si el usuario es
+// operador
+func (m *TenantConnectionManager) TenantOrOperator(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenantID, ok := c.Locals(m.TenantKey).(uuid.UUID)
+		if ok && c.Params(param) == tenantID.String() {
+			return c.Next()
+		}
+		if !m.IsOperator(c.Context()) {
+			return fiber.NewError(fiber.StatusForbidden, "Tenant not authorized")
+		}
+		return c.Next()
+	}
+}
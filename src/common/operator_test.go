@@ -0,0 +1,54 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Simula AuthenticationMiddleware y TenantMiddleware con el usuario y el tenant dados
+func operatorTestApp(m *TenantConnectionManager, userID uuid.UUID, tenantID uuid.UUID) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(m.UserIDKey, userID)
+		c.Locals(m.TenantKey, tenantID)
+		return c.Next()
+	})
+	app.Get("/fleet", m.OperatorOnly(), func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+	app.Get("/tenants/:id", m.TenantOrOperator("id"), func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+	return app
+}
+
+func TestTenantConnectionManager_OperatorMiddlewares(t *testing.T) {
+	m := newTestManager(10)
+	operator := uuid.New()
+	m.config.Operators.UserIDs = []uuid.UUID{operator}
+	own, other := uuid.New(), uuid.New()
+
+	cases := []struct {
+		name   string
+		userID uuid.UUID
+		path   string
+		status int
+	}{
+		{"tenant user on fleet", uuid.New(), "/fleet", http.StatusForbidden},
+		{"operator on fleet", operator, "/fleet", http.StatusOK},
+		{"tenant user on own tenant", uuid.New(), "/tenants/" + own.String(), http.StatusOK},
+		{"tenant user on another tenant", uuid.New(), "/tenants/" + other.String(), http.StatusForbidden},
+		{"operator on another tenant", operator, "/tenants/" + other.String(), http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := operatorTestApp(m, tc.userID, own).Test(httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("expected %d, got %d", tc.status, resp.StatusCode)
+			}
+		})
+	}
+}
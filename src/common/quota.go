@@ -0,0 +1,93 @@
+package common
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Recurso de las llamadas a la API, se limita por mes. Los demás recursos son
+// las tablas de los modelos y limitan la cantidad total de filas.
+const ResourceAPICalls = "api_calls"
+
+// Recurso de los usuarios asociados al tenant (tenants.user_tenants)
+const ResourceUsers = "users"
+
+// QuotaEnforcer aplica los límites del plan de cada tenant
+type QuotaEnforcer interface {
+	// Consume suma n unidades al uso del recurso, falla con QuotaExceededError
+	// o TooManyRequestsError si supera el límite del plan
+	Consume(ctx context.Context, tenantID uuid.UUID, resource string, n int64) error
+	// Release resta n unidades al uso del recurso
+	Release(ctx context.Context, tenantID uuid.UUID, resource string, n int64)
+}
+
+// SetQuotas registra quién aplica los límites de los planes, sin él no hay límites
+func (m *TenantConnectionManager) SetQuotas(quotas QuotaEnforcer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quotas = quotas
+}
+
+// Consume aplica el límite del recurso al tenant del contexto
+func (m *TenantConnectionManager) Consume(ctx context.Context, resource string, n int64) error {
+	tenantID, quotas, ok := m.quotaTenant(ctx)
+	if !ok || n <= 0 {
+		return nil
+	}
+	return quotas.Consume(ctx, tenantID, resource, n)
+}
+
+// Release devuelve unidades del recurso al tenant del contexto
+func (m *TenantConnectionManager) Release(ctx context.Context, resource string, n int64) {
+	tenantID, quotas, ok := m.quotaTenant(ctx)
+	if !ok || n <= 0 {
+		return
+	}
+	quotas.Release(ctx, tenantID, resource, n)
+}
+
+type pendingReleasesKey struct{}
+
+// Unidades liberadas dentro de una transacción, por recurso
+type pendingReleases struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// ReleaseAfterCommit devuelve las unidades cuando hace commit la transacción
+// de WithTransaction del contexto. Fuera de ella no se sabe si el cambio se
+// confirma y no se devuelven, el uso se corrige al recalcularlo.
+func (m *TenantConnectionManager) ReleaseAfterCommit(ctx context.Context, resource string, n int64) {
+	pending, ok := ctx.Value(pendingReleasesKey{}).(*pendingReleases)
+	if !ok || n <= 0 {
+		return
+	}
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	pending.counts[resource] += n
+}
+
+// Contexto de una transacción para ReleaseAfterCommit, commit devuelve las
+// unidades acumuladas y se llama solo si la transacción hizo commit
+func (m *TenantConnectionManager) withPendingReleases(ctx context.Context) (context.Context, func()) {
+	pending := &pendingReleases{counts: map[string]int64{}}
+	commit := func() {
+		pending.mu.Lock()
+		defer pending.mu.Unlock()
+		for resource, n := range pending.counts {
+			m.Release(ctx, resource, n)
+		}
+		clear(pending.counts)
+	}
+	return context.WithValue(ctx, pendingReleasesKey{}, pending), commit
+}
+
+func (m *TenantConnectionManager) quotaTenant(ctx context.Context) (uuid.UUID, QuotaEnforcer, bool) {
+	tenantID, ok := ctx.Value(m.TenantKey).(uuid.UUID)
+	m.mu.Lock()
+	quotas := m.quotas
+	m.mu.Unlock()
+	return tenantID, quotas, ok && quotas != nil
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Contadores en memoria, cada instancia aplica los límites por separado
+type memoryStore struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	// Última limpieza de las ventanas vencidas
+	cleaned time.Time
+}
+
+type memoryWindow struct {
+	count int64
+	reset time.Time
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{windows: map[string]*memoryWindow{}, cleaned: time.Now()}
+}
+
+// Increment implements Store.
+func (s *memoryStore) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	now := time.Now()
+	_, reset := windowBounds(now, window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanup(now)
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.reset) {
+		w = &memoryWindow{reset: reset}
+		s.windows[key] = w
+	}
+	w.count++
+	return w.count, w.reset, nil
+}
+
+// Elimina las ventanas vencidas como máximo una vez por minuto
+func (s *memoryStore) cleanup(now time.Time) {
+	if now.Sub(s.cleaned) < time.Minute {
+		return
+	}
+	s.cleaned = now
+	for key, w := range s.windows {
+		if !now.Before(w.reset) {
+			delete(s.windows, key)
+		}
+	}
+}
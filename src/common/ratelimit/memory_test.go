@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreCountsPerKey(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		count, reset, err := store.Increment(ctx, "tenant:a", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Fatalf("count = %d, want %d", count, want)
+		}
+		if !reset.After(time.Now()) {
+			t.Fatalf("reset %s is not in the future", reset)
+		}
+	}
+	if count, _, _ := store.Increment(ctx, "tenant:b", time.Hour); count != 1 {
+		t.Fatalf("other key count = %d, want 1", count)
+	}
+}
+
+func TestMemoryStoreResetsWindow(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, _, err := store.Increment(ctx, "user:a", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(25 * time.Millisecond)
+	count, _, err := store.Increment(ctx, "user:a", 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("count after window = %d, want 1", count)
+	}
+}
@@ -0,0 +1,45 @@
+// Package ratelimit cuenta las peticiones por ventana de tiempo fija en
+// memoria o en Redis, para que los límites se compartan entre instancias.
+package ratelimit
+
+import (
+	"api-test/src/config"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store guarda los contadores de las ventanas
+type Store interface {
+	// Increment suma una petición a la ventana actual de key y retorna el
+	// total de la ventana y cuándo termina
+	Increment(ctx context.Context, key string, window time.Duration) (count int64, reset time.Time, err error)
+}
+
+const (
+	StoreMemory = "memory"
+	StoreRedis  = "redis"
+)
+
+// New crea el almacenamiento configurado en RATE_LIMIT_STORE, client es la
+// conexión a Redis y solo se usa con redis
+func New(conf *config.Config, client redis.UniversalClient) (Store, error) {
+	switch conf.RateLimit.Store {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StoreRedis:
+		if client == nil {
+			return nil, fmt.Errorf("ratelimit: redis store requires a redis connection")
+		}
+		return NewRedisStore(client, conf.RateLimit.Prefix), nil
+	}
+	return nil, fmt.Errorf("ratelimit: unknown store %q", conf.RateLimit.Store)
+}
+
+// Inicio y fin de la ventana que contiene now
+func windowBounds(now time.Time, window time.Duration) (time.Time, time.Time) {
+	start := now.Truncate(window)
+	return start, start.Add(window)
+}
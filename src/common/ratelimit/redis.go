@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Contadores en Redis compartidos por todas las instancias, la clave incluye
+// el inicio de la ventana y expira al terminarla
+type redisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedisStore(client redis.UniversalClient, prefix string) Store {
+	return &redisStore{client: client, prefix: prefix}
+}
+
+// Increment implements Store.
+func (s *redisStore) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	start, reset := windowBounds(time.Now(), window)
+	windowKey := fmt.Sprintf("%s%s:%d", s.prefix, key, start.Unix())
+
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, windowKey)
+	pipe.ExpireAt(ctx, windowKey, reset)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+	return incr.Val(), reset, nil
+}
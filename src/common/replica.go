@@ -0,0 +1,129 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// GetReadDB retorna una réplica del tenant para consultas de solo lectura.
+// Las réplicas se usan por turnos; si el tenant no tiene o ninguna está
+// disponible (breaker abierto, error al conectar) retorna la conexión principal.
+func (m *TenantConnectionManager) GetReadDB(tenantID uuid.UUID) (*bun.DB, error) {
+	m.mu.Lock()
+	replicas := m.replicas[tenantID]
+	m.mu.Unlock()
+
+	if len(replicas) > 0 {
+		next := int(m.nextReplica.Add(1) % uint64(len(replicas)))
+		for i := range replicas {
+			if db, err := m.GetDB(replicas[(next+i)%len(replicas)]); err == nil {
+				return db, nil
+			}
+		}
+	}
+	return m.GetDB(tenantID)
+}
+
+// GetReadDBContext retorna la conexión de lectura del tenant del contexto, la
+// principal si la petición pidió leer sus propias escrituras
+func (m *TenantConnectionManager) GetReadDBContext(ctx context.Context) (*bun.DB, error) {
+	tenantID, ok := ctx.Value(m.TenantKey).(uuid.UUID)
+	if !ok {
+		return nil, fmt.Errorf("no tenant found in context")
+	}
+	if primary, _ := ctx.Value(ReadPrimaryKey).(bool); primary {
+		return m.GetDB(tenantID)
+	}
+	return m.GetReadDB(tenantID)
+}
+
+// WithPrimary hace que las lecturas del contexto usen la conexión principal,
+// para leer lo que se acaba de escribir sin el retraso de las réplicas
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ReadPrimaryKey, true)
+}
+
+// UpdateReplicas cambia las réplicas del tenant sin cerrar su conexión principal,
+// las conexiones a las réplicas anteriores se cierran después de TENANT_POOLS_DRAIN
+func (m *TenantConnectionManager) UpdateReplicas(tenantID uuid.UUID, replicas []string) error {
+	m.mu.Lock()
+	config, exists := m.configs[tenantID]
+	if !exists {
+		m.mu.Unlock()
+		return fmt.Errorf("no configuration found for tenant: %s", tenantID)
+	}
+	updated := *config
+	updated.Replicas = replicas
+	m.configs[tenantID] = &updated
+	stale := m.setReplicas(&updated, m.connects[tenantID])
+	m.mu.Unlock()
+
+	for _, db := range stale {
+		m.closeLater(db)
+	}
+	return nil
+}
+
+// Registra las réplicas de la configuración y retorna las conexiones de las
+// anteriores para cerrarlas. Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) setReplicas(config *TenantConfig, connectFunc ConnectFunc) []*bun.DB {
+	stale := m.removeReplicas(config.TenantID)
+	for i, host := range config.Replicas {
+		// Una réplica guardada fuera de la lista no recibe las credenciales
+		if !m.ReplicaAllowed(host) {
+			continue
+		}
+		dsn, err := replicaDSN(config.ConnectionString, host)
+		if err != nil {
+			continue
+		}
+		id := uuid.NewSHA1(config.TenantID, []byte(fmt.Sprintf("replica-%d", i)))
+		m.configs[id] = &TenantConfig{
+			TenantID:         id,
+			Name:             config.Name + " (replica " + host + ")",
+			ConnectionString: dsn,
+			primary:          config.TenantID,
+		}
+		m.connects[id] = connectFunc
+		m.replicas[config.TenantID] = append(m.replicas[config.TenantID], id)
+	}
+	return stale
+}
+
+// Quita las réplicas del tenant. Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) removeReplicas(tenantID uuid.UUID) []*bun.DB {
+	var stale []*bun.DB
+	for _, id := range m.replicas[tenantID] {
+		if db := m.detach(id); db != nil {
+			stale = append(stale, db)
+		}
+		delete(m.configs, id)
+		delete(m.connects, id)
+		delete(m.health, id)
+	}
+	delete(m.replicas, tenantID)
+	return stale
+}
+
+// ReplicaAllowed indica si el host está en TENANT_REPLICA_HOSTS
+func (m *TenantConnectionManager) ReplicaAllowed(host string) bool {
+	return slices.ContainsFunc(m.config.Replicas.AllowedHosts, func(allowed string) bool {
+		return strings.EqualFold(allowed, host)
+	})
+}
+
+// Cambia el host del dsn conservando las credenciales, la base de datos y el search_path
+func replicaDSN(dsn string, host string) (string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+	u.Host = host
+	return u.String(), nil
+}
@@ -4,7 +4,11 @@ import (
 	"api-test/src/common/filters"
 	"api-test/src/config"
 	"context"
+	"fmt"
+	"reflect"
+	"slices"
 
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
 
@@ -13,21 +17,22 @@ type Repository[Table any, ID any] interface {
 	Create(ctx context.Context, item Table) (*Table, error)
 	GetById(ctx context.Context, id ID, relations ...string) (*Table, error)
 	Update(ctx context.Context, id ID, item Table) (*Table, error)
-	Delete(ctx context.Context, id ID) error
+	// Los borrados retornan la cantidad de filas eliminadas
+	Delete(ctx context.Context, id ID) (int64, error)
 
 	// Bulk
 	CreateMany(ctx context.Context, items []Table) ([]Table, error)
 	UpdateMany(ctx context.Context, items []Table) ([]Table, error)
-	DeleteMany(ctx context.Context, ids []ID) error
+	DeleteMany(ctx context.Context, ids []ID) (int64, error)
 
 	// Transaction
 	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error
 	CreateTx(ctx context.Context, tx bun.Tx, item Table) (*Table, error)
 	UpdateTx(ctx context.Context, tx bun.Tx, id ID, item Table) (*Table, error)
-	DeleteTx(ctx context.Context, tx bun.Tx, id ID) error
+	DeleteTx(ctx context.Context, tx bun.Tx, id ID) (int64, error)
 	CreateManyTx(ctx context.Context, tx bun.Tx, items []Table) ([]Table, error)
 	UpdateManyTx(ctx context.Context, tx bun.Tx, items []Table) ([]Table, error)
-	DeleteManyTx(ctx context.Context, tx bun.Tx, ids []ID) error
+	DeleteManyTx(ctx context.Context, tx bun.Tx, ids []ID) (int64, error)
 
 	// Search
 	Search(ctx context.Context, filters *QueryParams, relations ...string) ([]Table, error)
@@ -38,207 +43,369 @@ type repository[Table any, ID any] struct {
 	log Logger
 	tenant *TenantConnectionManager
 	queryBuilder filters.QueryBuilder
+	// Columnas con el tag encrypt, nil si el modelo no tiene
+	encrypted *encryptedModel
 }
 
 func (r *repository[Table, ID]) Create(ctx context.Context, item Table) (*Table, error) {
-	db, err := r.tenant.GetDBContext(ctx)
-	if err != nil {
+	if err := r.seal(ctx, &item); err != nil {
 		return nil, err
 	}
 
-	_, err = db.NewInsert().Model(&item).Exec(ctx)
+	err := r.run(ctx, func(db bun.IDB) error {
+		_, err := r.stamp(ctx, db.NewInsert().Model(&item)).Exec(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
 
-	return &item, nil
+	return &item, r.open(ctx, &item)
 }
 
 func (r *repository[Table, ID]) GetById(ctx context.Context, id ID, relations ...string) (*Table, error) {
-	db, err := r.tenant.GetDBContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	var item Table
-	q := db.NewSelect().Model(&item)
-	for _, relation := range relations {
-		q = q.Relation(relation)
-	}
-
-	err = q.Where("id = ?", id).Scan(ctx)
+	err := r.read(ctx, func(db bun.IDB) error {
+		q := db.NewSelect().Model(&item)
+		for _, relation := range relations {
+			q = q.Relation(relation)
+		}
+		return q.Where("id = ?", id).Scan(ctx)
+	})
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return &item, nil
+	return &item, r.open(ctx, &item)
 }
 
 func (r *repository[Table, ID]) Update(ctx context.Context, id ID, item Table) (*Table, error) {
-	db, err := r.tenant.GetDBContext(ctx)
-	if err != nil {
+	if err := r.seal(ctx, &item); err != nil {
 		return nil, err
 	}
 
-	_, err = db.NewUpdate().Model(&item).Where("id = ?", id).Exec(ctx)
+	err := r.run(ctx, func(db bun.IDB) error {
+		_, err := db.NewUpdate().Model(&item).Where("id = ?", id).Exec(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return &item, nil
+	return &item, r.open(ctx, &item)
 }
 
-func (r *repository[Table, ID]) Delete(ctx context.Context, id ID) error {
-	db, err := r.tenant.GetDBContext(ctx)
-	if err != nil {
-		return err
-	}
-
+func (r *repository[Table, ID]) Delete(ctx context.Context, id ID) (int64, error) {
 	var item Table
-	_, err = db.NewDelete().Model(&item).Where("id = ?", id).Exec(ctx)
+	var deleted int64
+	err := r.run(ctx, func(db bun.IDB) error {
+		res, err := db.NewDelete().Model(&item).Where("id = ?", id).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
 	if err != nil {
-		return CheckDBErrorType(err)
+		return 0, CheckDBErrorType(err)
 	}
-	return nil
+	return deleted, nil
 }
 
 func (r *repository[Table, ID]) Search(ctx context.Context, filter *QueryParams, relations ...string) ([]Table, error) {
-	db, err := r.tenant.GetDBContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	var items []Table
-	q := db.NewSelect().Model(&items)
-	for _, relation := range relations {
-		q = q.Relation(relation)
-	}
-
-	if filter != nil && !filter.IsEmpty() {
-		q, err = r.queryBuilder.BuildQuery(q, &filters.FilterParams{
-			Filters: filter.Filter,
-			Sort:    filter.Sort,
-			Pagination: &filters.PaginationParams{
-				Page: filter.Page,
-				Size: filter.Size,
-			},
-		}, q.GetTableName())
+	queryBuilder := &r.queryBuilder
+	if r.encrypted != nil && filter != nil && !filter.IsEmpty() {
+		cipher, err := r.cipher(ctx)
 		if err != nil {
 			return nil, err
 		}
+		queryBuilder = queryBuilder.WithRewriter(r.encrypted.rewriter(cipher))
 	}
 
-	err = q.Scan(ctx)
+	var items []Table
+	err := r.read(ctx, func(db bun.IDB) error {
+		q := db.NewSelect().Model(&items)
+		for _, relation := range relations {
+			q = q.Relation(relation)
+		}
+
+		if filter != nil && !filter.IsEmpty() {
+			var err error
+			q, err = queryBuilder.BuildQuery(q, &filters.FilterParams{
+				Filters: filter.Filter,
+				Sort:    filter.Sort,
+				Pagination: &filters.PaginationParams{
+					Page: filter.Page,
+					Size: filter.Size,
+				},
+			}, q.GetTableName())
+			if err != nil {
+				return err
+			}
+		}
+		return q.Scan(ctx)
+	})
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return items, nil
+	return items, r.open(ctx, pointers(items)...)
 }
 
 // Bulk
 func (r *repository[Table, ID]) CreateMany(ctx context.Context, items []Table) ([]Table, error) {
-	db, err := r.tenant.GetDBContext(ctx)
+	items, err := r.sealCopy(ctx, items)
 	if err != nil {
 		return nil, err
 	}
-
-	_, err = db.NewInsert().Model(&items).Exec(ctx)
+	err = r.run(ctx, func(db bun.IDB) error {
+		_, err := r.stamp(ctx, db.NewInsert().Model(&items)).Exec(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return items, nil
+	return items, r.open(ctx, pointers(items)...)
 }
 
 func (r *repository[Table, ID]) UpdateMany(ctx context.Context, items []Table) ([]Table, error) {
-	db, err := r.tenant.GetDBContext(ctx)
+	items, err := r.sealCopy(ctx, items)
 	if err != nil {
 		return nil, err
 	}
-
-	_, err = db.NewUpdate().Model(&items).Exec(ctx)
+	err = r.run(ctx, func(db bun.IDB) error {
+		_, err := db.NewUpdate().Model(&items).Exec(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return items, nil
+	return items, r.open(ctx, pointers(items)...)
 }
 
-func (r *repository[Table, ID]) DeleteMany(ctx context.Context, ids []ID) error {
-	db, err := r.tenant.GetDBContext(ctx)
-	if err != nil {
-		return err
-	}
-
+func (r *repository[Table, ID]) DeleteMany(ctx context.Context, ids []ID) (int64, error) {
 	var item Table
-	_, err = db.NewDelete().Model(&item).Where("id IN (?)", bun.In(ids)).Exec(ctx)
+	var deleted int64
+	err := r.run(ctx, func(db bun.IDB) error {
+		res, err := db.NewDelete().Model(&item).Where("id IN (?)", bun.In(ids)).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
 	if err != nil {
-		return CheckDBErrorType(err)
+		return 0, CheckDBErrorType(err)
 	}
-	return nil
+	return deleted, nil
 }
 
 // Transaction
+// Las cuotas liberadas por los borrados de la transacción se devuelven después del commit.
 func (r *repository[Table, ID]) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error {
 	db, err := r.tenant.GetDBContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	return db.RunInTx(ctx, nil, fn)
+	txCtx, commit := r.tenant.withPendingReleases(ctx)
+	err = db.RunInTx(txCtx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if tenantID, ok := r.tenant.RowLevelTenant(ctx); ok {
+			if err := SetTenant(ctx, tx, tenantID); err != nil {
+				return err
+			}
+		}
+		return fn(ctx, tx)
+	})
+	if err != nil {
+		return err
+	}
+	commit()
+	return nil
 }
 
 func (r *repository[Table, ID]) CreateManyTx(ctx context.Context, tx bun.Tx, items []Table) ([]Table, error) {
-	_, err := tx.NewInsert().Model(&items).Exec(ctx)
+	items, err := r.sealCopy(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.stamp(ctx, tx.NewInsert().Model(&items)).Exec(ctx)
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return items, nil
+	return items, r.open(ctx, pointers(items)...)
 }
 
 func (r *repository[Table, ID]) UpdateManyTx(ctx context.Context, tx bun.Tx, items []Table) ([]Table, error) {
-	_, err := tx.NewUpdate().Model(&items).Exec(ctx)
+	items, err := r.sealCopy(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.NewUpdate().Model(&items).Exec(ctx)
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return items, nil
+	return items, r.open(ctx, pointers(items)...)
 }
 
-func (r *repository[Table, ID]) DeleteManyTx(ctx context.Context, tx bun.Tx, ids []ID) error {
+func (r *repository[Table, ID]) DeleteManyTx(ctx context.Context, tx bun.Tx, ids []ID) (int64, error) {
 	var item Table
-	_, err := tx.NewDelete().Model(&item).Where("id IN (?)", bun.In(ids)).Exec(ctx)
+	res, err := tx.NewDelete().Model(&item).Where("id IN (?)", bun.In(ids)).Exec(ctx)
 	if err != nil {
-		return CheckDBErrorType(err)
+		return 0, CheckDBErrorType(err)
 	}
-	return nil
+	return res.RowsAffected()
 }
 
 func (r *repository[Table, ID]) CreateTx(ctx context.Context, tx bun.Tx, item Table) (*Table, error) {
-	_, err := tx.NewInsert().Model(&item).Exec(ctx)
+	err := r.seal(ctx, &item)
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.stamp(ctx, tx.NewInsert().Model(&item)).Exec(ctx)
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return &item, nil
+	return &item, r.open(ctx, &item)
 }
 
 func (r *repository[Table, ID]) UpdateTx(ctx context.Context, tx bun.Tx, id ID, item Table) (*Table, error) {
-	_, err := tx.NewUpdate().Model(&item).Where("id = ?", id).Exec(ctx)
+	err := r.seal(ctx, &item)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.NewUpdate().Model(&item).Where("id = ?", id).Exec(ctx)
 	if err != nil {
 		return nil, CheckDBErrorType(err)
 	}
-	return &item, nil
+	return &item, r.open(ctx, &item)
 }
 
-func (r *repository[Table, ID]) DeleteTx(ctx context.Context, tx bun.Tx, id ID) error {
+func (r *repository[Table, ID]) DeleteTx(ctx context.Context, tx bun.Tx, id ID) (int64, error) {
 	var item Table
-	_, err := tx.NewDelete().Model(&item).Where("id = ?", id).Exec(ctx)
+	res, err := tx.NewDelete().Model(&item).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return 0, CheckDBErrorType(err)
+	}
+	return res.RowsAffected()
+}
+
+// Ejecuta fn con la conexión del tenant del contexto. Con tablas compartidas
+// se ejecuta en una transacción que fija app.tenant_id, así las políticas RLS
+// filtran las filas aunque la consulta no incluya el tenant.
+func (r *repository[Table, ID]) run(ctx context.Context, fn func(db bun.IDB) error) error {
+	db, err := r.tenant.GetDBContext(ctx)
+	if err != nil {
+		return err
+	}
+	return r.runOn(ctx, db, fn)
+}
+
+// Como run pero con la conexión de lectura, una réplica si el tenant tiene
+func (r *repository[Table, ID]) read(ctx context.Context, fn func(db bun.IDB) error) error {
+	db, err := r.tenant.GetReadDBContext(ctx)
+	if err != nil {
+		return err
+	}
+	return r.runOn(ctx, db, fn)
+}
+
+func (r *repository[Table, ID]) runOn(ctx context.Context, db *bun.DB, fn func(db bun.IDB) error) error {
+	tenantID, ok := r.tenant.RowLevelTenant(ctx)
+	if !ok {
+		return fn(db)
+	}
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := SetTenant(ctx, tx, tenantID); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// Agrega la columna tenant_id a los inserts sobre tablas compartidas
+func (r *repository[Table, ID]) stamp(ctx context.Context, q *bun.InsertQuery) *bun.InsertQuery {
+	if tenantID, ok := r.tenant.RowLevelTenant(ctx); ok {
+		q = q.Value("tenant_id", "?", tenantID)
+	}
+	return q
+}
+
+// Cifrador de columnas del tenant del contexto
+func (r *repository[Table, ID]) cipher(ctx context.Context) (*FieldCipher, error) {
+	tenantID, ok := ctx.Value(r.tenant.TenantKey).(uuid.UUID)
+	if !ok {
+		return nil, fmt.Errorf("no tenant found in context")
+	}
+	config, err := r.tenant.GetTenantConfig(tenantID)
 	if err != nil {
-		return CheckDBErrorType(err)
+		return nil, err
+	}
+	if config.FieldCipher == nil {
+		return nil, fmt.Errorf("field encryption key not available for tenant: %s", tenantID)
+	}
+	return config.FieldCipher, nil
+}
+
+// Cifra las columnas marcadas con el tag encrypt antes de guardar
+func (r *repository[Table, ID]) seal(ctx context.Context, items ...*Table) error {
+	if r.encrypted == nil || len(items) == 0 {
+		return nil
+	}
+	cipher, err := r.cipher(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := r.encrypted.seal(cipher, reflect.ValueOf(item).Elem()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// Cifra una copia de los registros para no modificar el slice de quien llama,
+// que al reintentar volvería a cifrar los valores ya cifrados
+func (r *repository[Table, ID]) sealCopy(ctx context.Context, items []Table) ([]Table, error) {
+	if r.encrypted == nil {
+		return items, nil
+	}
+	items = slices.Clone(items)
+	return items, r.seal(ctx, pointers(items)...)
+}
+
+// Descifra las columnas marcadas con el tag encrypt después de leer
+func (r *repository[Table, ID]) open(ctx context.Context, items ...*Table) error {
+	if r.encrypted == nil || len(items) == 0 {
+		return nil
+	}
+	cipher, err := r.cipher(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := r.encrypted.open(cipher, reflect.ValueOf(item).Elem()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pointers[T any](items []T) []*T {
+	result := make([]*T, len(items))
+	for i := range items {
+		result[i] = &items[i]
+	}
+	return result
+}
+
 func NewRepository[Table any, ID any](config *config.Config, log Logger, tenant *TenantConnectionManager) Repository[Table, ID] {
+	encrypted, err := encryptedModelOf(reflect.TypeFor[Table]())
+	if err != nil {
+		panic(err)
+	}
 	return &repository[Table, ID]{
 		config: config,
 		log: log,
 		tenant: tenant,
+		encrypted: encrypted,
 	}
 }
 
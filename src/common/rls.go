@@ -0,0 +1,30 @@
+package common
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Parámetro de sesión que leen las políticas RLS de las tablas compartidas
+const TenantSetting = "app.tenant_id"
+
+// RowLevelTenant retorna el tenant del contexto si usa tablas compartidas
+func (m *TenantConnectionManager) RowLevelTenant(ctx context.Context) (uuid.UUID, bool) {
+	tenantID, ok := ctx.Value(m.TenantKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil, false
+	}
+	config, err := m.GetTenantConfig(tenantID)
+	if err != nil || !config.RowLevelSecurity {
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+// SetTenant fija app.tenant_id hasta el final de la transacción, equivalente a SET LOCAL
+func SetTenant(ctx context.Context, tx bun.IDB, tenantID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config(?, ?, true)", TenantSetting, tenantID.String())
+	return err
+}
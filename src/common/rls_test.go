@@ -0,0 +1,36 @@
+package common
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTenantConnectionManager_RowLevelTenant(t *testing.T) {
+	m := newTestManager(10)
+	var opened atomic.Int32
+	shared, dedicated := uuid.New(), uuid.New()
+	if err := m.RegisterTenant(&TenantConfig{TenantID: shared, ConnectionString: "postgres://u:p@localhost:5432/db", RowLevelSecurity: true}, countingConnect(&opened)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterTenant(&TenantConfig{TenantID: dedicated, ConnectionString: "postgres://u:p@localhost:5432/db"}, countingConnect(&opened)); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, ok := m.RowLevelTenant(context.WithValue(context.Background(), m.TenantKey, shared)); !ok || id != shared {
+		t.Errorf("shared tenant should use RLS, got %s %v", id, ok)
+	}
+	// Tenants con base de datos propia, sin registrar o sin tenant en el contexto no fijan app.tenant_id
+	for name, ctx := range map[string]context.Context{
+		"dedicated":  context.WithValue(context.Background(), m.TenantKey, dedicated),
+		"unknown":    context.WithValue(context.Background(), m.TenantKey, uuid.New()),
+		"no tenant":  context.Background(),
+		"wrong type": context.WithValue(context.Background(), m.TenantKey, shared.String()),
+	} {
+		if id, ok := m.RowLevelTenant(ctx); ok || id != uuid.Nil {
+			t.Errorf("%s: unexpected RLS tenant %s", name, id)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Prefijo de las claves que habilitan los módulos, sin la clave el módulo
+// está habilitado
+const ModuleSettingPrefix = "modules."
+
+// SettingsStore resuelve la configuración de cada tenant
+type SettingsStore interface {
+	// Settings retorna los valores JSON del tenant con los valores por defecto aplicados
+	Settings(ctx context.Context, tenantID uuid.UUID) (map[string]json.RawMessage, error)
+}
+
+// SetSettings registra de dónde se lee la configuración de los tenants
+func (m *TenantConnectionManager) SetSettings(settings SettingsStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings = settings
+}
+
+// Setting decodifica en dest el valor de key para el tenant del contexto.
+// Retorna false si no existe o no es del tipo de dest.
+func (m *TenantConnectionManager) Setting(ctx context.Context, key string, dest any) bool {
+	tenantID, ok := ctx.Value(m.TenantKey).(uuid.UUID)
+	m.mu.Lock()
+	settings := m.settings
+	m.mu.Unlock()
+	if !ok || settings == nil {
+		return false
+	}
+	values, err := settings.Settings(ctx, tenantID)
+	if err != nil {
+		NewLogger().Warn(ctx, "Error getting tenant settings", "tenant_id", tenantID, "error", err)
+		return false
+	}
+	raw, ok := values[key]
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(raw, dest) == nil
+}
+
+// SettingString retorna el texto de key, fallback si no existe
+func (m *TenantConnectionManager) SettingString(ctx context.Context, key string, fallback string) string {
+	value := fallback
+	if !m.Setting(ctx, key, &value) {
+		return fallback
+	}
+	return value
+}
+
+// IsEnabled indica si el feature flag está activo para el tenant del contexto
+func (m *TenantConnectionManager) IsEnabled(ctx context.Context, flag string) bool {
+	var enabled bool
+	return m.Setting(ctx, flag, &enabled) && enabled
+}
+
+// RequireModule rechaza las peticiones de los tenants que tienen el módulo deshabilitado
+func (m *TenantConnectionManager) RequireModule(module string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		enabled := true
+		if m.Setting(Context(c), ModuleSettingPrefix+module, &enabled) && !enabled {
+			return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("Module %s is not enabled for this tenant", module))
+		}
+		return c.Next()
+	}
+}
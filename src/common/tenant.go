@@ -2,18 +2,57 @@ package common
 
 import (
 	"api-test/src/config"
+	"container/list"
 	"context"
+	"database/sql"
 	"fmt"
+	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
 
 type TenantConfig struct {
-	TenantID         uuid.UUID
-	Name             string
+	TenantID uuid.UUID
+	Name     string
+	// Identificador legible con el que se resuelve por subdominio o ruta
+	Slug             string
 	ConnectionString string
+	Suspended        bool
+	Provisioning     bool
+	// Cifrador de las columnas con el tag encrypt, nil si el tenant no tiene clave de datos
+	FieldCipher *FieldCipher
+	// Última rotación de la contraseña con la que se abrió la conexión
+	CredentialsRotatedAt time.Time
+	// Tamaño del pool del tenant, los valores en cero usan la configuración global
+	Pool PoolSettings
+	// Tablas compartidas con otros tenants: cada transacción fija app.tenant_id
+	// y las políticas RLS filtran las filas del tenant
+	RowLevelSecurity bool
+	// host:port de las réplicas de lectura, usan las mismas credenciales
+	Replicas []string
+	// Plan del tenant, define su límite de peticiones
+	Plan string
+	// Tenant al que pertenece la configuración de una réplica
+	primary uuid.UUID
+}
+
+type PoolSettings struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// Estado de un pool abierto
+type PoolStats struct {
+	TenantID uuid.UUID
+	Name     string
+	Settings PoolSettings
+	LastUsed time.Time
+	Stats    sql.DBStats
 }
 
 type DSNConfig struct {
@@ -23,58 +62,141 @@ type DSNConfig struct {
 	Password string
 	Database string
 	SSLMode  string
+	// Schema del tenant en la base de datos compartida, se fija en el search_path
+	Schema string
+}
+
+// Función que abre la conexión de un tenant
+type ConnectFunc func(tenantID uuid.UUID, dsn string) (*bun.DB, error)
+
+// Conexión abierta de un tenant, elem es su posición en la lista LRU
+type tenantPool struct {
+	tenantID uuid.UUID
+	db       *bun.DB
+	settings PoolSettings
+	lastUsed time.Time
+	elem     *list.Element
 }
 
+// Conexión que se está abriendo, las demás peticiones esperan su resultado
+type openingPool struct {
+	done chan struct{}
+	db   *bun.DB
+	err  error
+}
+
+// TenantConnectionManager registra la configuración de los tenants y abre sus
+// conexiones en el primer uso. Las conexiones inactivas por más de
+// TENANT_POOLS_IDLE_TTL se cierran y, al superar TENANT_POOLS_MAX_OPEN, se
+// cierra la usada hace más tiempo. La base de datos admin no se cierra nunca.
+// La suma de conexiones de los pools abiertos no supera TENANT_POOLS_CONNECTION_BUDGET.
 type TenantConnectionManager struct {
-	mu        sync.RWMutex
-	config    *config.Config
-	bunDBs    map[uuid.UUID]*bun.DB
-	configs   map[uuid.UUID]*TenantConfig
+	mu       sync.Mutex
+	config   *config.Config
+	configs  map[uuid.UUID]*TenantConfig
+	connects map[uuid.UUID]ConnectFunc
+	pools    map[uuid.UUID]*tenantPool
+	opening  map[uuid.UUID]*openingPool
+	health   map[uuid.UUID]*tenantHealth
+	// Réplicas de cada tenant y la siguiente a usar
+	replicas    map[uuid.UUID][]uuid.UUID
+	nextReplica atomic.Uint64
+	lru         *list.List
+	// Límites de los planes, nil sin límites
+	quotas QuotaEnforcer
+	// Configuración de los tenants, nil sin configuración
+	settings SettingsStore
+	// Caché de lecturas de los repositorios, nil sin caché
+	cache     Cache
+	sharedID  uuid.UUID
+	pooledID  uuid.UUID
 	TenantKey string
 	UserIDKey string
 }
 
 func NewTenantConnectionManager(config *config.Config) *TenantConnectionManager {
 	return &TenantConnectionManager{
-		bunDBs:    make(map[uuid.UUID]*bun.DB),
 		configs:   make(map[uuid.UUID]*TenantConfig),
+		connects:  make(map[uuid.UUID]ConnectFunc),
+		pools:     make(map[uuid.UUID]*tenantPool),
+		opening:   make(map[uuid.UUID]*openingPool),
+		health:    make(map[uuid.UUID]*tenantHealth),
+		replicas:  make(map[uuid.UUID][]uuid.UUID),
+		lru:       list.New(),
+		sharedID:  uuid.NewSHA1(config.TenantID, []byte("shared")),
+		pooledID:  uuid.NewSHA1(config.TenantID, []byte("pooled")),
 		config:    config,
 		TenantKey: TenantKey,
 		UserIDKey: UserIDKey,
 	}
 }
 
-func (m *TenantConnectionManager) RegisterTenant(config *TenantConfig, connectFunc func(tenantID uuid.UUID, dsn string) (*bun.DB, error)) error {
-	if _, err := m.GetDB(config.TenantID); err == nil {
+// RegisterTenant registra la configuración del tenant sin abrir su conexión.
+// Si el tenant ya está registrado se mantiene la configuración actual.
+func (m *TenantConnectionManager) RegisterTenant(config *TenantConfig, connectFunc ConnectFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.configs[config.TenantID]; exists {
 		return nil
 	}
+	m.configs[config.TenantID] = config
+	m.connects[config.TenantID] = connectFunc
+	m.setReplicas(config, connectFunc)
+	return nil
+}
 
+// SwapTenant reemplaza la configuración del tenant. Si su conexión está
+// abierta se abre una nueva y la anterior se cierra después de
+// TENANT_POOLS_DRAIN para que las peticiones que ya la obtuvieron terminen.
+func (m *TenantConnectionManager) SwapTenant(config *TenantConfig, connectFunc ConnectFunc) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	_, open := m.pools[config.TenantID]
+	m.mu.Unlock()
+
+	var db *bun.DB
+	if open {
+		var err error
+		if db, err = connectFunc(config.TenantID, config.ConnectionString); err != nil {
+			return err
+		}
+	}
 
+	m.mu.Lock()
 	m.configs[config.TenantID] = config
-	_, exists := m.bunDBs[config.TenantID]
-	if exists {
-		return nil
+	m.connects[config.TenantID] = connectFunc
+	old := m.detach(config.TenantID)
+	// Con la nueva configuración el estado anterior ya no aplica
+	delete(m.health, config.TenantID)
+	if db != nil {
+		m.attach(config.TenantID, db, m.poolSettings(config))
 	}
+	// Las réplicas se abren de nuevo en el próximo uso
+	stale := m.setReplicas(config, connectFunc)
+	m.mu.Unlock()
 
-	db, err := connectFunc(config.TenantID, config.ConnectionString)
-	if err != nil {
-		return err
+	m.closeLater(old)
+	for _, db := range stale {
+		m.closeLater(db)
 	}
-	m.bunDBs[config.TenantID] = db
 	return nil
 }
 
 // dsn format
+// Con Schema el driver fija el search_path al abrir cada conexión, el schema
+// public queda después para las extensiones compartidas.
 func (m *TenantConnectionManager) DSN(params DSNConfig) string {
-	return fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=%s", params.User, params.Password, params.Host, params.Port, params.Database, params.SSLMode)
+	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=%s", params.User, params.Password, params.Host, params.Port, params.Database, params.SSLMode)
+	if params.Schema != "" {
+		dsn += "&search_path=" + url.QueryEscape(params.Schema+",public")
+	}
+	return dsn
 }
 
 func (m *TenantConnectionManager) GetTenantConfig(tenantID uuid.UUID) (*TenantConfig, error) {
-	m.mu.RLock()
+	m.mu.Lock()
 	config, exists := m.configs[tenantID]
-	m.mu.RUnlock()
+	m.mu.Unlock()
 
 	if exists {
 		return config, nil
@@ -83,22 +205,381 @@ func (m *TenantConnectionManager) GetTenantConfig(tenantID uuid.UUID) (*TenantCo
 	return nil, fmt.Errorf("no configuration found for tenant: %s", tenantID)
 }
 
+// UpdateConfig modifica la configuración registrada del tenant sin cerrar su conexión
+func (m *TenantConnectionManager) UpdateConfig(tenantID uuid.UUID, update func(config *TenantConfig)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	config, exists := m.configs[tenantID]
+	if !exists {
+		return fmt.Errorf("no configuration found for tenant: %s", tenantID)
+	}
+
+	// Copia para no modificar la configuración entregada a otros lectores
+	updated := *config
+	update(&updated)
+	m.configs[tenantID] = &updated
+	return nil
+}
+
+// TenantIDs retorna los tenants registrados sin incluir las conexiones admin
+// ni las réplicas
+func (m *TenantConnectionManager) TenantIDs() []uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]uuid.UUID, 0, len(m.configs))
+	for id, config := range m.configs {
+		if !m.internal(id) && config.primary == uuid.Nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// TenantBySlug retorna el tenant registrado con el slug
+func (m *TenantConnectionManager) TenantBySlug(slug string) (uuid.UUID, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, config := range m.configs {
+		if config.Slug == slug && config.primary == uuid.Nil && !m.internal(id) {
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// GetDB retorna la conexión del tenant, abriéndola si es el primer uso.
+// Peticiones concurrentes sobre un tenant sin conexión esperan la misma apertura.
+// Con el circuit breaker abierto falla de inmediato sin tocar la base de datos.
 func (m *TenantConnectionManager) GetDB(tenantID uuid.UUID) (*bun.DB, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	return m.getDB(tenantID, false)
+}
 
-	db, exists := m.bunDBs[tenantID]
-	if exists {
+// Con probe se ignora el circuit breaker para probar si el tenant se recuperó
+func (m *TenantConnectionManager) getDB(tenantID uuid.UUID, probe bool) (*bun.DB, error) {
+	for {
+		m.mu.Lock()
+		if err := m.breakerError(tenantID); err != nil && !probe {
+			m.mu.Unlock()
+			return nil, err
+		}
+		if pool, exists := m.pools[tenantID]; exists {
+			pool.lastUsed = time.Now()
+			if pool.elem != nil {
+				m.lru.MoveToFront(pool.elem)
+			}
+			m.mu.Unlock()
+			return pool.db, nil
+		}
+		config, exists := m.configs[tenantID]
+		if !exists {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("no db found for tenant: %s", tenantID)
+		}
+		if opening, exists := m.opening[tenantID]; exists {
+			m.mu.Unlock()
+			<-opening.done
+			if opening.err != nil {
+				return nil, opening.err
+			}
+			continue
+		}
+		opening := &openingPool{done: make(chan struct{})}
+		m.opening[tenantID] = opening
+		connect := m.connects[tenantID]
+		m.mu.Unlock()
+
+		db, err := connect(tenantID, config.ConnectionString)
+
+		m.mu.Lock()
+		delete(m.opening, tenantID)
+		m.record(tenantID, err)
+		current, exists := m.configs[tenantID]
+		var evicted []*bun.DB
+		retry := false
+		switch {
+		case err != nil:
+		case !exists:
+			err = fmt.Errorf("no db found for tenant: %s", tenantID)
+		case current.ConnectionString != config.ConnectionString:
+			// La configuración cambió mientras se abría, se abre de nuevo
+			retry = true
+		default:
+			m.attach(tenantID, db, m.poolSettings(current))
+			evicted = m.evictOverflow()
+			if used, budget := m.budgetUsage(); budget > 0 && used > budget {
+				m.detach(tenantID)
+				err = ServiceUnavailableError("connection budget exhausted, try again later")
+			}
+		}
+		opening.err = err
+		close(opening.done)
+		m.mu.Unlock()
+
+		for _, old := range evicted {
+			m.closeLater(old)
+		}
+		if err != nil || retry {
+			if db != nil {
+				db.Close()
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
 		return db, nil
 	}
+}
+
+// EvictIdle cierra las conexiones sin uso por más de TENANT_POOLS_IDLE_TTL
+func (m *TenantConnectionManager) EvictIdle() {
+	ttl := time.Duration(m.config.Pools.IdleTTL) * time.Second
+	if ttl <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	var evicted []*bun.DB
+	for elem := m.lru.Back(); elem != nil; {
+		pool := elem.Value.(*tenantPool)
+		elem = elem.Prev()
+		if time.Since(pool.lastUsed) < ttl {
+			break
+		}
+		if pool.db.Stats().InUse > 0 {
+			continue
+		}
+		evicted = append(evicted, m.detach(pool.tenantID))
+	}
+	m.mu.Unlock()
 
-	return nil, fmt.Errorf("no db found for tenant: %s", tenantID)
+	for _, db := range evicted {
+		db.Close()
+	}
+}
+
+// StartEviction ejecuta EvictIdle periódicamente hasta cancelar el contexto
+func (m *TenantConnectionManager) StartEviction(ctx context.Context) {
+	interval := time.Duration(m.config.Pools.EvictInterval) * time.Second
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.EvictIdle()
+		}
+	}
+}
+
+// Agrega la conexión abierta, la base de datos admin queda fuera de la lista LRU.
+// Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) attach(tenantID uuid.UUID, db *bun.DB, settings PoolSettings) {
+	applyPoolSettings(db, settings)
+	pool := &tenantPool{tenantID: tenantID, db: db, settings: settings, lastUsed: time.Now()}
+	if tenantID != m.config.TenantID {
+		pool.elem = m.lru.PushFront(pool)
+	}
+	m.pools[tenantID] = pool
+}
+
+// Quita la conexión del tenant y la retorna para cerrarla, nil si no estaba
+// abierta. Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) detach(tenantID uuid.UUID) *bun.DB {
+	pool, exists := m.pools[tenantID]
+	if !exists {
+		return nil
+	}
+	if pool.elem != nil {
+		m.lru.Remove(pool.elem)
+	}
+	delete(m.pools, tenantID)
+	return pool.db
+}
+
+// Quita las conexiones usadas hace más tiempo al superar TENANT_POOLS_MAX_OPEN
+// o el presupuesto de conexiones. Las que tienen consultas en curso se
+// mantienen. Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) evictOverflow() []*bun.DB {
+	overflow := func() bool {
+		used, budget := m.budgetUsage()
+		maxOpen := m.config.Pools.MaxOpen
+		return (maxOpen > 0 && m.lru.Len() > maxOpen) || (budget > 0 && used > budget)
+	}
+	var evicted []*bun.DB
+	// La más reciente es la que se acaba de abrir y no se considera
+	for elem := m.lru.Back(); elem != m.lru.Front() && overflow(); {
+		pool := elem.Value.(*tenantPool)
+		elem = elem.Prev()
+		if pool.db.Stats().InUse > 0 {
+			continue
+		}
+		evicted = append(evicted, m.detach(pool.tenantID))
+	}
+	return evicted
+}
+
+// Configuración del pool del tenant combinada con la global, las réplicas
+// usan la del tenant
+func (m *TenantConnectionManager) poolSettings(config *TenantConfig) PoolSettings {
+	if primary, exists := m.configs[config.primary]; exists {
+		config = primary
+	}
+	settings := PoolSettings{
+		MaxOpenConns:    m.config.Pools.MaxOpenConns,
+		MaxIdleConns:    m.config.Pools.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(m.config.Pools.ConnMaxLifetime) * time.Second,
+	}
+	if m.internal(config.TenantID) {
+		settings.MaxOpenConns = m.config.Pools.AdminMaxOpenConns
+		settings.MaxIdleConns = m.config.Pools.AdminMaxIdleConns
+	}
+	if config.Pool.MaxOpenConns > 0 {
+		settings.MaxOpenConns = config.Pool.MaxOpenConns
+	}
+	if config.Pool.MaxIdleConns > 0 {
+		settings.MaxIdleConns = config.Pool.MaxIdleConns
+	}
+	if config.Pool.ConnMaxLifetime > 0 {
+		settings.ConnMaxLifetime = config.Pool.ConnMaxLifetime
+	}
+	settings.MaxIdleConns = min(settings.MaxIdleConns, settings.MaxOpenConns)
+	return settings
+}
+
+func applyPoolSettings(db *bun.DB, settings PoolSettings) {
+	db.SetMaxOpenConns(settings.MaxOpenConns)
+	db.SetMaxIdleConns(settings.MaxIdleConns)
+	db.SetConnMaxLifetime(settings.ConnMaxLifetime)
+}
+
+// Conexiones máximas de los pools abiertos y el presupuesto configurado.
+// Debe llamarse con el lock tomado.
+func (m *TenantConnectionManager) budgetUsage() (used, budget int) {
+	for _, pool := range m.pools {
+		used += pool.settings.MaxOpenConns
+	}
+	return used, m.config.Pools.ConnectionBudget
+}
+
+// UpdatePool cambia el tamaño del pool del tenant, aplicándolo de inmediato
+// si está abierto. Retorna error si supera el presupuesto de conexiones.
+func (m *TenantConnectionManager) UpdatePool(tenantID uuid.UUID, pool PoolSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	config, exists := m.configs[tenantID]
+	if !exists {
+		return fmt.Errorf("no configuration found for tenant: %s", tenantID)
+	}
+	updated := *config
+	updated.Pool = pool
+	settings := m.poolSettings(&updated)
+
+	var open []*tenantPool
+	for _, id := range append([]uuid.UUID{tenantID}, m.replicas[tenantID]...) {
+		if pool, exists := m.pools[id]; exists {
+			open = append(open, pool)
+		}
+	}
+	used, budget := m.budgetUsage()
+	for _, pool := range open {
+		used += settings.MaxOpenConns - pool.settings.MaxOpenConns
+	}
+	if len(open) > 0 && budget > 0 && used > budget {
+		return ServiceUnavailableError("connection budget exhausted")
+	}
+	for _, pool := range open {
+		applyPoolSettings(pool.db, settings)
+		pool.settings = settings
+	}
+	m.configs[tenantID] = &updated
+	return nil
+}
+
+// PoolStats retorna el estado de los pools abiertos y el uso del presupuesto
+func (m *TenantConnectionManager) PoolStats() (stats []PoolStats, used, budget int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for tenantID, pool := range m.pools {
+		stat := PoolStats{TenantID: tenantID, Settings: pool.settings, LastUsed: pool.lastUsed, Stats: pool.db.Stats()}
+		if config, exists := m.configs[tenantID]; exists {
+			stat.Name = config.Name
+		}
+		stats = append(stats, stat)
+	}
+	used, budget = m.budgetUsage()
+	return stats, used, budget
+}
+
+// Cierra la conexión después de TENANT_POOLS_DRAIN
+func (m *TenantConnectionManager) closeLater(db *bun.DB) {
+	if db == nil {
+		return
+	}
+	drain := time.Duration(m.config.Pools.Drain) * time.Second
+	time.AfterFunc(drain, func() { db.Close() })
 }
 
 func (m *TenantConnectionManager) GetKosviTenantDB() (*bun.DB, error) {
 	return m.GetDB(m.config.TenantID)
 }
 
+// ConnectAdmin abre una conexión con el usuario admin a otra base de datos del
+// mismo servidor, fuera de los pools. Quien la abre debe cerrarla.
+func (m *TenantConnectionManager) ConnectAdmin(database string) (*bun.DB, error) {
+	m.mu.Lock()
+	config, connect := m.configs[m.config.TenantID], m.connects[m.config.TenantID]
+	m.mu.Unlock()
+	if config == nil {
+		return nil, fmt.Errorf("no configuration found for tenant: %s", m.config.TenantID)
+	}
+
+	dsn, err := url.Parse(config.ConnectionString)
+	if err != nil {
+		return nil, err
+	}
+	dsn.Path = "/" + database
+	return connect(m.config.TenantID, dsn.String())
+}
+
+// SharedDBID identifica la conexión con usuario admin a la base de datos
+// compartida donde se crean los schemas de los tenants
+func (m *TenantConnectionManager) SharedDBID() uuid.UUID {
+	return m.sharedID
+}
+
+func (m *TenantConnectionManager) GetSharedDB() (*bun.DB, error) {
+	return m.GetDB(m.sharedID)
+}
+
+// PooledDBID identifica la conexión con usuario admin al schema de las tablas
+// compartidas, usada para migrarlas
+func (m *TenantConnectionManager) PooledDBID() uuid.UUID {
+	return m.pooledID
+}
+
+func (m *TenantConnectionManager) GetPooledDB() (*bun.DB, error) {
+	return m.GetDB(m.pooledID)
+}
+
+// Conexiones con usuario admin que no pertenecen a un tenant
+func (m *TenantConnectionManager) internal(id uuid.UUID) bool {
+	return id == m.config.TenantID || id == m.sharedID || id == m.pooledID
+}
+
+// GetDBContext retorna la conexión del tenant del contexto. Para los tenants con
+// schema propio cada conexión del pool ya tiene su search_path, por lo que los
+// repositorios no cambian.
 func (m *TenantConnectionManager) GetDBContext(ctx context.Context) (*bun.DB, error) {
 	tenantID, ok := ctx.Value(m.TenantKey).(uuid.UUID)
 	if !ok {
@@ -111,15 +592,18 @@ func (m *TenantConnectionManager) RemoveTenant(tenantID uuid.UUID) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	db, exists := m.bunDBs[tenantID]
-	if exists {
+	for _, db := range m.removeReplicas(tenantID) {
+		db.Close()
+	}
+	if db := m.detach(tenantID); db != nil {
 		if err := db.Close(); err != nil {
 			return err
 		}
-		delete(m.bunDBs, tenantID)
 	}
 
 	delete(m.configs, tenantID)
+	delete(m.connects, tenantID)
+	delete(m.health, tenantID)
 	return nil
 }
 
@@ -127,10 +611,8 @@ func (m *TenantConnectionManager) CloseAll() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for _, db := range m.bunDBs {
-		db.Close()
+	for tenantID := range m.pools {
+		m.detach(tenantID).Close()
 	}
-
-	m.bunDBs = make(map[uuid.UUID]*bun.DB)
 	return nil
 }
@@ -0,0 +1,281 @@
+package common
+
+import (
+	"api-test/src/config"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// Abre un pool sin conectarse al servidor y cuenta las aperturas
+func countingConnect(opened *atomic.Int32) ConnectFunc {
+	return func(tenantID uuid.UUID, dsn string) (*bun.DB, error) {
+		opened.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New()), nil
+	}
+}
+
+func newTestManager(maxOpen int) *TenantConnectionManager {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.Pools.MaxOpen = maxOpen
+	conf.Pools.IdleTTL = 300
+	return NewTenantConnectionManager(conf)
+}
+
+func TestTenantConnectionManager_ConcurrentFirstOpen(t *testing.T) {
+	m := newTestManager(10)
+	var opened atomic.Int32
+	tenantID := uuid.New()
+	if err := m.RegisterTenant(&TenantConfig{TenantID: tenantID, ConnectionString: "postgres://u:p@localhost:5432/db"}, countingConnect(&opened)); err != nil {
+		t.Fatal(err)
+	}
+	if opened.Load() != 0 {
+		t.Fatal("register should not open the pool")
+	}
+
+	var wg sync.WaitGroup
+	dbs := make([]*bun.DB, 20)
+	for i := range dbs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db, err := m.GetDB(tenantID)
+			if err != nil {
+				t.Error(err)
+			}
+			dbs[i] = db
+		}()
+	}
+	wg.Wait()
+
+	if opened.Load() != 1 {
+		t.Errorf("expected a single open, got %d", opened.Load())
+	}
+	for _, db := range dbs {
+		if db != dbs[0] {
+			t.Fatal("expected every caller to get the same pool")
+		}
+	}
+}
+
+func TestTenantConnectionManager_EvictLRU(t *testing.T) {
+	m := newTestManager(2)
+	var opened atomic.Int32
+	ids := []uuid.UUID{m.config.TenantID, uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		_ = m.RegisterTenant(&TenantConfig{TenantID: id, ConnectionString: "postgres://u:p@localhost:5432/db"}, countingConnect(&opened))
+		if _, err := m.GetDB(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// El admin no cuenta ni se desaloja, el primer tenant es el menos usado
+	if _, open := m.pools[ids[0]]; !open {
+		t.Error("admin pool should stay open")
+	}
+	if _, open := m.pools[ids[1]]; open {
+		t.Error("least recently used pool should be evicted")
+	}
+	if m.lru.Len() != 2 {
+		t.Errorf("expected 2 tenant pools, got %d", m.lru.Len())
+	}
+
+	// Un pool desalojado se abre de nuevo en el siguiente uso
+	if _, err := m.GetDB(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if opened.Load() != 5 {
+		t.Errorf("expected 5 opens, got %d", opened.Load())
+	}
+}
+
+func TestTenantConnectionManager_ConnectionBudget(t *testing.T) {
+	m := newTestManager(0)
+	m.config.Pools.MaxOpenConns = 10
+	m.config.Pools.ConnectionBudget = 15
+	var opened atomic.Int32
+	first, second := uuid.New(), uuid.New()
+	_ = m.RegisterTenant(&TenantConfig{TenantID: first, ConnectionString: "postgres://u:p@localhost:5432/a"}, countingConnect(&opened))
+	_ = m.RegisterTenant(&TenantConfig{TenantID: second, ConnectionString: "postgres://u:p@localhost:5432/b", Pool: PoolSettings{MaxOpenConns: 5}}, countingConnect(&opened))
+
+	if _, err := m.GetDB(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetDB(second); err != nil {
+		t.Fatal(err)
+	}
+	if _, used, _ := m.PoolStats(); used != 15 {
+		t.Errorf("expected 15 connections used, got %d", used)
+	}
+
+	// Crecer por encima del presupuesto no está permitido
+	if err := m.UpdatePool(second, PoolSettings{MaxOpenConns: 6}); err == nil {
+		t.Error("expected budget error")
+	}
+}
+
+func TestTenantConnectionManager_CircuitBreaker(t *testing.T) {
+	m := newTestManager(0)
+	m.config.Health.FailureThreshold = 2
+	m.config.Health.OpenTimeout = 0
+	var attempts atomic.Int32
+	failing := true
+	tenantID := uuid.New()
+	_ = m.RegisterTenant(&TenantConfig{TenantID: tenantID, ConnectionString: "postgres://u:p@localhost:5432/db"}, func(id uuid.UUID, dsn string) (*bun.DB, error) {
+		attempts.Add(1)
+		if failing {
+			return nil, errors.New("connection refused")
+		}
+		return countingConnect(new(atomic.Int32))(id, dsn)
+	})
+
+	for range 2 {
+		if _, err := m.GetDB(tenantID); err == nil {
+			t.Fatal("expected connection error")
+		}
+	}
+	// Con el breaker abierto no se intenta conectar
+	if _, err := m.GetDB(tenantID); StatusCode(err, 0) != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("expected 2 connection attempts, got %d", attempts.Load())
+	}
+
+	// La prueba en segundo plano cierra el breaker al recuperarse
+	failing = false
+	m.CheckHealth(context.Background())
+	if _, err := m.GetDB(tenantID); err != nil {
+		t.Fatal(err)
+	}
+	if health := m.Health(); len(health) != 1 || health[0].State != HealthHealthy {
+		t.Errorf("expected healthy tenant, got %+v", health)
+	}
+}
+
+func TestTenantConnectionManager_ReadReplicas(t *testing.T) {
+	m := newTestManager(0)
+	m.config.Health.FailureThreshold = 1
+	m.config.Health.OpenTimeout = 60
+	m.config.Replicas.AllowedHosts = []string{"replica:5432"}
+	tenantID := uuid.New()
+	_ = m.RegisterTenant(&TenantConfig{
+		TenantID:         tenantID,
+		ConnectionString: "postgres://u:p@primary:5432/db?sslmode=disable",
+		Replicas:         []string{"replica:5432"},
+	}, func(id uuid.UUID, dsn string) (*bun.DB, error) {
+		if dsn == "postgres://u:p@replica:5432/db?sslmode=disable" {
+			return nil, errors.New("connection refused")
+		}
+		return countingConnect(new(atomic.Int32))(id, dsn)
+	})
+	if ids := m.TenantIDs(); len(ids) != 1 || ids[0] != tenantID {
+		t.Fatalf("replicas should not be listed as tenants, got %v", ids)
+	}
+
+	primary, err := m.GetDB(tenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// La réplica falla y abre su breaker, las lecturas van a la principal
+	for range 2 {
+		db, err := m.GetReadDB(tenantID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if db != primary {
+			t.Fatal("expected fallback to the primary")
+		}
+	}
+
+	// Al quitar las réplicas se borra su estado
+	if err := m.UpdateReplicas(tenantID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if health := m.Health(); len(health) != 1 || health[0].ReplicaOf != uuid.Nil {
+		t.Errorf("expected only the primary, got %+v", health)
+	}
+}
+
+func TestTenantConnectionManager_SchemaDSN(t *testing.T) {
+	m := newTestManager(10)
+	params := DSNConfig{Host: "localhost", Port: 5432, User: "u", Password: "p", Database: "kosvi_shared", SSLMode: "disable"}
+	if dsn := m.DSN(params); strings.Contains(dsn, "search_path") {
+		t.Errorf("database tenants should not set search_path: %s", dsn)
+	}
+
+	params.Schema = "db_tienda_1a2b3c4d"
+	parsed, err := url.Parse(m.DSN(params))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := parsed.Query().Get("search_path"); path != "db_tienda_1a2b3c4d,public" {
+		t.Errorf("unexpected search_path %q", path)
+	}
+}
+
+func TestTenantConnectionManager_SharedDatabaseIsInternal(t *testing.T) {
+	m := newTestManager(10)
+	m.config.Pools.MaxOpenConns = 10
+	m.config.Pools.AdminMaxOpenConns = 20
+	tenantID := uuid.New()
+	for _, id := range []uuid.UUID{m.config.TenantID, m.SharedDBID(), tenantID} {
+		if err := m.RegisterTenant(&TenantConfig{TenantID: id, ConnectionString: "postgres://u:p@localhost:5432/db"}, countingConnect(new(atomic.Int32))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// La conexión admin a la base de datos compartida no es un tenant
+	if ids := m.TenantIDs(); len(ids) != 1 || ids[0] != tenantID {
+		t.Fatalf("expected only the tenant, got %v", ids)
+	}
+	if settings := m.poolSettings(m.configs[m.SharedDBID()]); settings.MaxOpenConns != 20 {
+		t.Errorf("shared database should use the admin pool size, got %d", settings.MaxOpenConns)
+	}
+	if settings := m.poolSettings(m.configs[tenantID]); settings.MaxOpenConns != 10 {
+		t.Errorf("tenant should use the tenant pool size, got %d", settings.MaxOpenConns)
+	}
+}
+
+func TestTenantConnectionManager_ReplicasAllowlist(t *testing.T) {
+	m := newTestManager(0)
+	m.config.Replicas.AllowedHosts = []string{"replica-1:5432"}
+	tenantID := uuid.New()
+	var dsns []string
+	_ = m.RegisterTenant(&TenantConfig{
+		TenantID:         tenantID,
+		ConnectionString: "postgres://u:p@primary:5432/db?sslmode=disable",
+		Replicas:         []string{"REPLICA-1:5432", "attacker.example.com:5432"},
+	}, func(id uuid.UUID, dsn string) (*bun.DB, error) {
+		dsns = append(dsns, dsn)
+		return countingConnect(new(atomic.Int32))(id, dsn)
+	})
+
+	// Solo se conecta a la réplica permitida, el otro host no recibe las credenciales
+	for range 3 {
+		if _, err := m.GetReadDB(tenantID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dsn := range dsns {
+		if strings.Contains(dsn, "attacker") {
+			t.Fatalf("credentials sent to a host outside the allowlist: %s", dsn)
+		}
+	}
+	if health := m.Health(); len(health) != 2 {
+		t.Errorf("expected the primary and one replica, got %+v", health)
+	}
+}
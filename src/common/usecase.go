@@ -3,8 +3,10 @@ package common
 import (
 	"api-test/src/config"
 	"context"
+	"reflect"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 )
 
 type UseCase[CreateDTO, ResponseDTO, UpdateDTO, ID any] interface {
@@ -38,11 +40,17 @@ type usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID any] struct {
 	createToTable func(CreateDTO) Table
 	updateToTable func(UpdateDTO) Table
 	toResponseDTO func(Table) ResponseDTO
+	// Nombre de la tabla, es el recurso que limitan los planes
+	resource string
 }
 
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) Create(ctx context.Context, dto CreateDTO) (*ResponseDTO, error) {
+	if err := u.tenant.Consume(ctx, u.resource, 1); err != nil {
+		return nil, err
+	}
 	table, err := u.repo.Create(ctx, u.createToTable(dto))
 	if err != nil {
+		u.tenant.Release(ctx, u.resource, 1)
 		return nil, err
 	}
 
@@ -68,8 +76,14 @@ func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) Update(ctx conte
 	return &result, nil
 }
 	
+// Solo se devuelven las filas eliminadas, los ids inexistentes no liberan cuota
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) Delete(ctx context.Context, id ID) error {
-	return u.repo.Delete(ctx, id)
+	deleted, err := u.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.tenant.Release(ctx, u.resource, deleted)
+	return nil
 }
 	
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) Search(ctx context.Context, filters *QueryParams) ([]ResponseDTO, error) {
@@ -89,8 +103,12 @@ func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) CreateMany(ctx c
 	for i, dto := range dtos {
 		tables[i] = u.createToTable(dto)
 	}
+	if err := u.tenant.Consume(ctx, u.resource, int64(len(tables))); err != nil {
+		return nil, err
+	}
 	tablesResult, err := u.repo.CreateMany(ctx, tables)
 	if err != nil {
+		u.tenant.Release(ctx, u.resource, int64(len(tables)))
 		return nil, err
 	}
 	result := make([]ResponseDTO, len(tablesResult))
@@ -117,16 +135,28 @@ func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) UpdateMany(ctx c
 }
 
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) DeleteMany(ctx context.Context, ids []ID) error {
-	return u.repo.DeleteMany(ctx, ids)
+	deleted, err := u.repo.DeleteMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+	u.tenant.Release(ctx, u.resource, deleted)
+	return nil
 }
 
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error {
 	return u.repo.WithTransaction(ctx, fn)
 }
 
+// En las transacciones el uso se descuenta aunque el commit falle después, se
+// corrige al recalcular el uso del tenant. Los borrados devuelven la cuota
+// después del commit.
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) CreateTx(ctx context.Context, tx bun.Tx, dto CreateDTO) (*ResponseDTO, error) {
+	if err := u.tenant.Consume(ctx, u.resource, 1); err != nil {
+		return nil, err
+	}
 	table, err := u.repo.CreateTx(ctx, tx, u.createToTable(dto))
 	if err != nil {
+		u.tenant.Release(ctx, u.resource, 1)
 		return nil, err
 	}
 	result := u.toResponseDTO(*table)
@@ -143,7 +173,12 @@ func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) UpdateTx(ctx con
 }
 	
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) DeleteTx(ctx context.Context, tx bun.Tx, id ID) error {
-	return u.repo.DeleteTx(ctx, tx, id)
+	deleted, err := u.repo.DeleteTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	u.tenant.ReleaseAfterCommit(ctx, u.resource, deleted)
+	return nil
 }
 	
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) CreateManyTx(ctx context.Context, tx bun.Tx, dtos []CreateDTO) ([]ResponseDTO, error) {
@@ -151,8 +186,12 @@ func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) CreateManyTx(ctx
 	for i, dto := range dtos {
 		tables[i] = u.createToTable(dto)
 	}
+	if err := u.tenant.Consume(ctx, u.resource, int64(len(tables))); err != nil {
+		return nil, err
+	}
 	tablesResult, err := u.repo.CreateManyTx(ctx, tx, tables)
 	if err != nil {
+		u.tenant.Release(ctx, u.resource, int64(len(tables)))
 		return nil, err
 	}
 	result := make([]ResponseDTO, len(tablesResult))
@@ -179,7 +218,12 @@ func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) UpdateManyTx(ctx
 }
 	
 func (u *usecase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID]) DeleteManyTx(ctx context.Context, tx bun.Tx, ids []ID) error {
-	return u.repo.DeleteManyTx(ctx, tx, ids)
+	deleted, err := u.repo.DeleteManyTx(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+	u.tenant.ReleaseAfterCommit(ctx, u.resource, deleted)
+	return nil
 }
 
 func NewUseCase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID any](config *config.Config, log Logger, tenant *TenantConnectionManager, repo Repository[Table, ID], createToTable func(CreateDTO) Table, updateToTable func(UpdateDTO) Table, toResponseDTO func(Table) ResponseDTO) UseCase[CreateDTO, ResponseDTO, UpdateDTO, ID] {
@@ -191,5 +235,6 @@ func NewUseCase[CreateDTO, ResponseDTO, UpdateDTO, Table, ID any](config *config
 		createToTable: createToTable,
 		updateToTable: updateToTable,
 		toResponseDTO: toResponseDTO,
+		resource: pgdialect.New().Tables().Get(reflect.TypeFor[Table]()).Name,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,123 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Cuotas que registran lo devuelto por recurso
+type releaseQuotas struct {
+	released map[string]int64
+}
+
+func (q *releaseQuotas) Consume(ctx context.Context, tenantID uuid.UUID, resource string, n int64) error {
+	return nil
+}
+
+func (q *releaseQuotas) Release(ctx context.Context, tenantID uuid.UUID, resource string, n int64) {
+	q.released[resource] += n
+}
+
+// Repositorio que borra solo los ids existentes, los demás métodos no se usan
+type deleteRepository struct {
+	Repository[cachedTestModel, int64]
+	items map[int64]bool
+}
+
+func (r *deleteRepository) delete(ids []int64) int64 {
+	var deleted int64
+	for _, id := range ids {
+		if r.items[id] {
+			delete(r.items, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *deleteRepository) Delete(ctx context.Context, id int64) (int64, error) {
+	return r.delete([]int64{id}), nil
+}
+
+func (r *deleteRepository) DeleteMany(ctx context.Context, ids []int64) (int64, error) {
+	return r.delete(ids), nil
+}
+
+func (r *deleteRepository) DeleteTx(ctx context.Context, tx bun.Tx, id int64) (int64, error) {
+	return r.delete([]int64{id}), nil
+}
+
+func (r *deleteRepository) DeleteManyTx(ctx context.Context, tx bun.Tx, ids []int64) (int64, error) {
+	return r.delete(ids), nil
+}
+
+func newDeleteUseCase(ids ...int64) (UseCase[cachedTestModel, cachedTestModel, cachedTestModel, int64], *releaseQuotas, context.Context) {
+	m := newTestManager(10)
+	quotas := &releaseQuotas{released: map[string]int64{}}
+	m.SetQuotas(quotas)
+	repo := &deleteRepository{items: map[int64]bool{}}
+	for _, id := range ids {
+		repo.items[id] = true
+	}
+	same := func(item cachedTestModel) cachedTestModel { return item }
+	uc := NewUseCase(nil, NewLogger(), m, Repository[cachedTestModel, int64](repo), same, same, same)
+	return uc, quotas, context.WithValue(context.Background(), m.TenantKey, uuid.New())
+}
+
+func TestUseCase_DeleteReleasesDeletedRows(t *testing.T) {
+	uc, quotas, ctx := newDeleteUseCase(1, 2, 3)
+
+	// Un id inexistente no libera cuota
+	if err := uc.Delete(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if quotas.released["productos"] != 1 {
+		t.Fatalf("expected 1 released, got %v", quotas.released)
+	}
+
+	if err := uc.DeleteMany(ctx, []int64{1, 2, 3, 4}); err != nil {
+		t.Fatal(err)
+	}
+	if quotas.released["productos"] != 3 {
+		t.Fatalf("expected 3 released, got %v", quotas.released)
+	}
+}
+
+func TestUseCase_DeleteTxReleasesAfterCommit(t *testing.T) {
+	uc, quotas, ctx := newDeleteUseCase(1, 2, 3, 4)
+	m := uc.(*usecase[cachedTestModel, cachedTestModel, cachedTestModel, cachedTestModel, int64]).tenant
+
+	txCtx, commit := m.withPendingReleases(ctx)
+	if err := uc.DeleteTx(txCtx, bun.Tx{}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.DeleteManyTx(txCtx, bun.Tx{}, []int64{1, 2, 5}); err != nil {
+		t.Fatal(err)
+	}
+	if len(quotas.released) != 0 {
+		t.Fatalf("released before commit: %v", quotas.released)
+	}
+	commit()
+	if quotas.released["productos"] != 2 {
+		t.Fatalf("expected 2 released, got %v", quotas.released)
+	}
+
+	// Sin commit, como en un rollback, no se devuelve nada
+	txCtx, _ = m.withPendingReleases(ctx)
+	if err := uc.DeleteTx(txCtx, bun.Tx{}, 3); err != nil {
+		t.Fatal(err)
+	}
+	// Fuera de WithTransaction no se sabe si hubo commit
+	if err := uc.DeleteManyTx(ctx, bun.Tx{}, []int64{4}); err != nil {
+		t.Fatal(err)
+	}
+	if quotas.released["productos"] != 2 {
+		t.Fatalf("released without commit: %v", quotas.released)
+	}
+}
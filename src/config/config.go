@@ -12,8 +12,25 @@ type Config struct {
 	DBConfig
 	Environment
 	JWT
+	TenantLifecycle
+	Migrations
+	Pools
+	Health
+	TenantResolution
+	Quotas
+	Settings
+	RateLimit
+	Proxy
+	Redis
+	Cache
+	Operators
+	Replicas
 	TenantID            uuid.UUID `env:"KOSVI_TENANT_ID,notEmpty,required"`
-	MasterEncryptionKey string    `env:"MASTER_ENCRYPTION_KEY,notEmpty,required"`
+	MasterEncryptionKey string    `env:"MASTER_ENCRYPTION_KEY"`
+	// Claves para envelope encryption (id:base64,id:base64) y el id de la clave activa
+	EncryptionKeys      string `env:"ENCRYPTION_KEYS"`
+	EncryptionActiveKey string `env:"ENCRYPTION_ACTIVE_KEY"`
+	KMS
 }
 
 func (c *Config) IsDev() bool {
@@ -26,6 +43,158 @@ type JWT struct {
 	ECPublicKeyBase64  string `env:"JWT_EC_PUBLIC_KEY_BASE64,notEmpty,required"`
 }
 
+type TenantLifecycle struct {
+	DeletionTokenTTL int `env:"TENANT_DELETION_TOKEN_TTL" envDefault:"600"`
+	PurgeInterval    int `env:"TENANT_PURGE_INTERVAL" envDefault:"300"`
+	// Intentos de aprovisionamiento antes de revertir el tenant
+	ProvisioningMaxAttempts int `env:"TENANT_PROVISIONING_MAX_ATTEMPTS" envDefault:"3"`
+	JobsResumeInterval      int `env:"TENANT_JOBS_RESUME_INTERVAL" envDefault:"60"`
+	// Rotación de contraseñas: antigüedad máxima en horas (0 la deshabilita),
+	// cada cuánto se revisa y cada cuánto se sincronizan los tenants modificados
+	// por otra instancia, respaldo de los eventos LISTEN/NOTIFY
+	CredentialsMaxAge        int `env:"TENANT_CREDENTIALS_MAX_AGE" envDefault:"0"`
+	CredentialsCheckInterval int `env:"TENANT_CREDENTIALS_CHECK_INTERVAL" envDefault:"3600"`
+	CredentialsSyncInterval  int `env:"TENANT_CREDENTIALS_SYNC_INTERVAL" envDefault:"60"`
+	// Segundos antes de reconectar el listener de eventos de tenants
+	EventsRetryInterval int `env:"TENANT_EVENTS_RETRY_INTERVAL" envDefault:"5"`
+	// Aislamiento de los tenants nuevos (database, schema o shared), la base de
+	// datos donde se crean los schemas y el schema de las tablas compartidas
+	DefaultIsolation string `env:"TENANT_DEFAULT_ISOLATION" envDefault:"database"`
+	SharedDBName     string `env:"TENANT_SHARED_DB_NAME" envDefault:"kosvi_shared"`
+	PooledSchema     string `env:"TENANT_POOLED_SCHEMA" envDefault:"pooled"`
+	// Directorio de los archivos de exportación e importación y tamaño máximo
+	// en MB de un archivo subido para importar
+	ExportsDir    string `env:"TENANT_EXPORTS_DIR" envDefault:"exports"`
+	ImportMaxSize int    `env:"TENANT_IMPORT_MAX_SIZE" envDefault:"100"`
+	// Tenant que se clona para los tenants nuevos, vacío los aprovisiona con
+	// migraciones y seeds
+	TemplateTenantID uuid.UUID `env:"TENANT_TEMPLATE_ID"`
+	// Columnas con datos personales (tabla.columna) que se anonimizan al clonar
+	PIIColumns []string `env:"TENANT_PII_COLUMNS" envDefault:"clientes.nombre,clientes.documento,clientes.documento_bidx,clientes.email,clientes.email_bidx,clientes.direccion" envSeparator:","`
+}
+
+// Conexiones de los tenants: se abren en el primer uso y se cierran al
+// superar el máximo de pools abiertos o el tiempo sin uso (segundos)
+type Pools struct {
+	MaxOpen       int `env:"TENANT_POOLS_MAX_OPEN" envDefault:"50"`
+	IdleTTL       int `env:"TENANT_POOLS_IDLE_TTL" envDefault:"300"`
+	EvictInterval int `env:"TENANT_POOLS_EVICT_INTERVAL" envDefault:"60"`
+	// Segundos antes de cerrar un pool reemplazado o desalojado
+	Drain int `env:"TENANT_POOLS_DRAIN" envDefault:"30"`
+	// Tamaño por defecto de cada pool, los tenants pueden sobrescribirlo
+	MaxOpenConns    int `env:"TENANT_POOL_MAX_OPEN_CONNS" envDefault:"10"`
+	MaxIdleConns    int `env:"TENANT_POOL_MAX_IDLE_CONNS" envDefault:"5"`
+	ConnMaxLifetime int `env:"TENANT_POOL_CONN_MAX_LIFETIME" envDefault:"300"`
+	// Pool de la base de datos admin
+	AdminMaxOpenConns int `env:"ADMIN_POOL_MAX_OPEN_CONNS" envDefault:"10"`
+	AdminMaxIdleConns int `env:"ADMIN_POOL_MAX_IDLE_CONNS" envDefault:"5"`
+	// Máximo de conexiones entre todos los pools abiertos, 0 sin límite
+	ConnectionBudget int `env:"TENANT_POOLS_CONNECTION_BUDGET" envDefault:"0"`
+}
+
+// Revisión de las conexiones de los tenants (segundos). Después de
+// FailureThreshold fallos seguidos las peticiones del tenant fallan de
+// inmediato y se vuelve a probar la conexión cada OpenTimeout.
+type Health struct {
+	Interval         int `env:"TENANT_HEALTH_INTERVAL" envDefault:"15"`
+	Timeout          int `env:"TENANT_HEALTH_TIMEOUT" envDefault:"2"`
+	FailureThreshold int `env:"TENANT_HEALTH_FAILURE_THRESHOLD" envDefault:"3"`
+	OpenTimeout      int `env:"TENANT_HEALTH_OPEN_TIMEOUT" envDefault:"30"`
+}
+
+// Resolución del tenant de cada petición: resolvers en el orden en que se
+// evalúan (header, subdomain, path, claim) y dominio de los subdominios de los
+// tenants, por ejemplo api.example.com para acme.api.example.com
+type TenantResolution struct {
+	Resolvers  []string `env:"TENANT_RESOLVERS" envDefault:"header" envSeparator:","`
+	BaseDomain string   `env:"TENANT_BASE_DOMAIN"`
+}
+
+// Plan de los tenants nuevos (vacío sin límites) y segundos que se guarda en
+// caché el plan de cada tenant
+type Quotas struct {
+	DefaultPlan string `env:"TENANT_DEFAULT_PLAN"`
+	CacheTTL    int    `env:"QUOTA_CACHE_TTL" envDefault:"60"`
+}
+
+// Configuración por defecto de los tenants (clave:valor, el valor en JSON o
+// como texto) y segundos que se guarda en caché la de cada tenant
+type Settings struct {
+	Defaults map[string]string `env:"TENANT_SETTINGS_DEFAULTS" envDefault:"currency:COP,locale:es-CO,tax_mode:included"`
+	CacheTTL int               `env:"TENANT_SETTINGS_CACHE_TTL" envDefault:"60"`
+}
+
+// Límites de peticiones por ventana (segundos). Cada petición se identifica
+// con el primer valor disponible de KeyBy (user, tenant, ip). El
+// límite general depende del plan del tenant y los grupos de rutas (prefijo:
+// peticiones) tienen además su propio límite.
+type RateLimit struct {
+	Enabled bool           `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
+	Store   string         `env:"RATE_LIMIT_STORE" envDefault:"memory"`
+	Prefix  string         `env:"RATE_LIMIT_PREFIX" envDefault:"ratelimit:"`
+	KeyBy   []string       `env:"RATE_LIMIT_KEY_BY" envDefault:"user,tenant,ip" envSeparator:","`
+	Window  int            `env:"RATE_LIMIT_WINDOW" envDefault:"60"`
+	Default int            `env:"RATE_LIMIT_DEFAULT" envDefault:"120"`
+	Plans   map[string]int `env:"RATE_LIMIT_PLANS" envDefault:"free:60,pro:600,enterprise:3000"`
+	Groups  map[string]int `env:"RATE_LIMIT_GROUPS" envDefault:"/api/v1/login:10,/api/v1/register:5,/api/v1/refresh:20"`
+	// Límite por IP antes de la autenticación, también cuenta las peticiones
+	// rechazadas con 401. Deshabilitado con 0: detrás de un proxy todas las
+	// peticiones llegan con su IP si no se configura Proxy.
+	IPLimit  int `env:"RATE_LIMIT_IP" envDefault:"0"`
+	IPWindow int `env:"RATE_LIMIT_IP_WINDOW" envDefault:"30"`
+}
+
+// Cabecera en la que el proxy envía la IP del cliente (X-Real-IP) y las IPs o
+// rangos de los proxies de los que se acepta. Sin Header o desde otra IP se
+// usa la de la conexión, el cliente no puede elegir su IP.
+type Proxy struct {
+	Header         string   `env:"PROXY_HEADER"`
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+}
+
+// Conexión a Redis, se abre solo si la caché o los límites de peticiones la usan
+type Redis struct {
+	URL         string `env:"REDIS_URL" envDefault:"redis://localhost:6379/0"`
+	PoolSize    int    `env:"REDIS_POOL_SIZE" envDefault:"0"`
+	DialTimeout int    `env:"REDIS_DIAL_TIMEOUT" envDefault:"5"`
+}
+
+// Caché de las lecturas de los repositorios: none, memory o redis, y segundos
+// que se guarda cada lectura
+type Cache struct {
+	Store  string `env:"CACHE_STORE" envDefault:"none"`
+	Prefix string `env:"CACHE_PREFIX" envDefault:"cache:"`
+	TTL    int    `env:"CACHE_TTL" envDefault:"300"`
+}
+
+// Usuarios que administran la plataforma y todos sus tenants
+type Operators struct {
+	UserIDs []uuid.UUID `env:"OPERATOR_USER_IDS" envSeparator:","`
+}
+
+// Hosts (host:port) permitidos como réplicas de lectura. Las réplicas reciben
+// las credenciales de la base de datos principal del tenant.
+type Replicas struct {
+	AllowedHosts []string `env:"TENANT_REPLICA_HOSTS" envSeparator:","`
+}
+
+// Valores por defecto al migrar todos los tenants
+type Migrations struct {
+	Concurrency   int `env:"MIGRATIONS_CONCURRENCY" envDefault:"4"`
+	TenantTimeout int `env:"MIGRATIONS_TENANT_TIMEOUT" envDefault:"300"`
+}
+
+// Proveedor de las claves de cifrado: env, file o vault
+type KMS struct {
+	Provider          string `env:"KMS_PROVIDER" envDefault:"env"`
+	KeyFile           string `env:"KMS_KEY_FILE"`
+	VaultAddress      string `env:"VAULT_ADDR"`
+	VaultToken        string `env:"VAULT_TOKEN"`
+	VaultNamespace    string `env:"VAULT_NAMESPACE"`
+	VaultTransitMount string `env:"VAULT_TRANSIT_MOUNT" envDefault:"transit"`
+	VaultTransitKey   string `env:"VAULT_TRANSIT_KEY"`
+}
+
 type Environment struct {
 	Name string `env:"ENV" envDefault:"development"`
 }
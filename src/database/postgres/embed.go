@@ -5,8 +5,17 @@ import "embed"
 //go:embed migrations/admin/*.sql
 var Admins embed.FS
 
-//go:embed migrations/tenants/*.sql
+// Incluye las migraciones de las tablas compartidas (RLS), que se aplican
+// después de las de tenants solo en el schema del pool
+//
+//go:embed migrations/tenants/*.sql migrations/shared/*.sql
 var Tenants embed.FS
 
 //go:embed migrations/common/*.sql
 var Common embed.FS
+
+// Datos iniciales de los tenants, base para todos los ambientes y
+// una carpeta adicional por ambiente (ej. development)
+//
+//go:embed seeds
+var Seeds embed.FS
@@ -0,0 +1,67 @@
+// Package gomigrations registra migraciones escritas en Go que se ejecutan
+// junto a las migraciones SQL embebidas, respetando el orden de versiones.
+package gomigrations
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Carpetas de migraciones a las que puede pertenecer una migración Go
+const (
+	ScopeAdmin   = "admin"
+	ScopeTenants = "tenants"
+	ScopeCommon  = "common"
+)
+
+type MigrationFunc func(ctx context.Context, tx *sql.Tx) error
+
+type migration struct {
+	version int64
+	source  string
+	up      MigrationFunc
+	down    MigrationFunc
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string][]migration{}
+)
+
+// Register agrega una migración Go al scope indicado, normalmente desde un init().
+// La versión no debe repetirse con las migraciones SQL de la misma carpeta.
+func Register(scope string, version int64, source string, up, down MigrationFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, m := range registry[scope] {
+		if m.version == version {
+			panic(fmt.Sprintf("gomigrations: duplicate version %d in scope %s", version, scope))
+		}
+	}
+	registry[scope] = append(registry[scope], migration{version: version, source: source, up: up, down: down})
+}
+
+// For retorna las migraciones del scope ordenadas por versión. Cada llamada
+// crea nuevas instancias para que los providers no compartan estado.
+func For(scope string) []*goose.Migration {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	migrations := make([]*goose.Migration, 0, len(registry[scope]))
+	for _, m := range registry[scope] {
+		gm := goose.NewGoMigration(m.version, &goose.GoFunc{RunTx: m.up}, &goose.GoFunc{RunTx: m.down})
+		gm.Source = m.source
+		migrations = append(migrations, gm)
+	}
+	slices.SortFunc(migrations, func(a, b *goose.Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations
+}
@@ -0,0 +1,41 @@
+package gomigrations
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func noop(ctx context.Context, tx *sql.Tx) error { return nil }
+
+func TestFor(t *testing.T) {
+	const scope = "test_for"
+	Register(scope, 3, "003_c.go", noop, noop)
+	Register(scope, 1, "001_a.go", noop, noop)
+
+	migrations := For(scope)
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Version != 3 {
+		t.Fatalf("migrations not sorted by version: %+v", migrations)
+	}
+	if migrations[0].Source != "001_a.go" {
+		t.Errorf("unexpected source %q", migrations[0].Source)
+	}
+	// Cada provider recibe sus propias instancias
+	if For(scope)[0] == migrations[0] {
+		t.Error("For should return new instances")
+	}
+	if len(For("test_empty")) != 0 {
+		t.Error("unknown scope should have no migrations")
+	}
+}
+
+func TestRegister_DuplicateVersion(t *testing.T) {
+	const scope = "test_duplicate"
+	Register(scope, 1, "001_a.go", noop, noop)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic registering a duplicate version")
+		}
+	}()
+	Register(scope, 1, "001_b.go", noop, noop)
+}
@@ -0,0 +1,55 @@
+package gomigrations
+
+import (
+	"context"
+	"database/sql"
+	"math"
+)
+
+func init() {
+	Register(ScopeTenants, 20261019120100, "20261019120100_precio_final.go", upPrecioFinal, downPrecioFinal)
+}
+
+// Calcula el precio final de los productos existentes aplicando su impuesto
+func upPrecioFinal(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE productos ADD COLUMN IF NOT EXISTS precio_final DECIMAL NULL"); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT p.id, p.precio, COALESCE(i.tasa, 0)
+		FROM productos p LEFT JOIN impuestos i ON i.id = p.impuesto_id
+		WHERE p.precio_final IS NULL`)
+	if err != nil {
+		return err
+	}
+	type precio struct {
+		id    int64
+		final float64
+	}
+	var precios []precio
+	for rows.Next() {
+		var id int64
+		var valor, tasa float64
+		if err := rows.Scan(&id, &valor, &tasa); err != nil {
+			rows.Close()
+			return err
+		}
+		precios = append(precios, precio{id: id, final: math.Round(valor*(1+tasa/100)*100) / 100})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range precios {
+		if _, err := tx.ExecContext(ctx, "UPDATE productos SET precio_final = $1 WHERE id = $2", p.final, p.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downPrecioFinal(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE productos DROP COLUMN IF EXISTS precio_final")
+	return err
+}
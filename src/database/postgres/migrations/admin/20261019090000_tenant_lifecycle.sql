@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS suspended_at timestamptz NULL;
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS delete_after timestamptz NULL;
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS deletion_token bytea NULL;
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS deletion_token_expires_at timestamptz NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS deletion_token_expires_at;
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS deletion_token;
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS delete_after;
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS suspended_at;
+-- +goose StatementEnd
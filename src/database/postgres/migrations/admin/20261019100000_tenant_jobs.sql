@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS status varchar NOT NULL DEFAULT 'ready';
+
+CREATE TABLE IF NOT EXISTS tenants.jobs (
+  id uuid PRIMARY KEY,
+  kind varchar NOT NULL,
+  tenant_id uuid NOT NULL,
+  user_id uuid NULL,
+  status varchar NOT NULL,
+  step varchar NOT NULL,
+  attempts int NOT NULL DEFAULT 0,
+  last_error text NULL,
+  payload jsonb NULL,
+  result jsonb NULL,
+  claimed_until timestamptz NULL,
+  created_at timestamptz DEFAULT now(),
+  updated_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON tenants.jobs (status);
+CREATE INDEX IF NOT EXISTS idx_jobs_tenant ON tenants.jobs (tenant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tenants.jobs;
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tenants.audit_log (
+  id uuid PRIMARY KEY,
+  action varchar NOT NULL,
+  tenant_id uuid NULL,
+  user_id uuid NULL,
+  reason text NOT NULL,
+  details jsonb NULL,
+  created_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_tenant ON tenants.audit_log (tenant_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tenants.audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS data_key bytea NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS data_key;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants ADD COLUMN IF NOT EXISTS credentials_rotated_at timestamptz NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS credentials_rotated_at;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tamaño del pool por tenant, NULL usa la configuración global
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS pool_max_open_conns INT NULL,
+    ADD COLUMN IF NOT EXISTS pool_max_idle_conns INT NULL,
+    ADD COLUMN IF NOT EXISTS pool_conn_max_lifetime INT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants
+    DROP COLUMN IF EXISTS pool_conn_max_lifetime,
+    DROP COLUMN IF EXISTS pool_max_idle_conns,
+    DROP COLUMN IF EXISTS pool_max_open_conns;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Aislamiento del tenant: base de datos propia o un schema en la base de datos compartida
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS isolation VARCHAR NOT NULL DEFAULT 'database',
+    ADD COLUMN IF NOT EXISTS db_schema VARCHAR NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants
+    DROP COLUMN IF EXISTS db_schema,
+    DROP COLUMN IF EXISTS isolation;
+-- +goose StatementEnd
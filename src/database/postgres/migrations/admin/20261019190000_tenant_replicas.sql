@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Réplicas de lectura del tenant (host:port), usan las credenciales de la principal
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS db_replicas VARCHAR[] NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants
+    DROP COLUMN IF EXISTS db_replicas;
+-- +goose StatementEnd
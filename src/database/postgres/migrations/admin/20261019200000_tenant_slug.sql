@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Identificador legible del tenant para resolverlo por subdominio o ruta
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS slug VARCHAR(63) NULL;
+
+-- Los tenants existentes usan su nombre más el inicio del id para no repetirse
+UPDATE tenants.tenants t
+SET slug = CASE WHEN s.base ~ '^[a-z]' THEN s.base ELSE 't-' || s.base END || '-' || left(t.id::text, 8)
+FROM (
+    SELECT id, left(trim(both '-' from lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))), 40) AS base
+    FROM tenants.tenants
+) s
+WHERE s.id = t.id AND t.slug IS NULL;
+
+ALTER TABLE tenants.tenants
+    ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS tenants_slug_key ON tenants.tenants (slug);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS tenants.tenants_slug_key;
+ALTER TABLE tenants.tenants
+    DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tenant del que se deriva la clave de las columnas cifradas, los clones usan
+-- la del origen para poder leer sus datos. Vacío usa el id del tenant.
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS field_key_id uuid NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants
+    DROP COLUMN IF EXISTS field_key_id;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Límites por recurso: tablas del tenant (total de filas) y api_calls (por
+-- mes). Un recurso sin límite no se limita.
+CREATE TABLE IF NOT EXISTS tenants.plans (
+  id varchar PRIMARY KEY,
+  name varchar NOT NULL,
+  limits jsonb NOT NULL DEFAULT '{}',
+  created_at timestamptz DEFAULT now()
+);
+
+-- Uso de cada recurso, period es el mes (YYYY-MM) o total
+CREATE TABLE IF NOT EXISTS tenants.usage (
+  tenant_id uuid NOT NULL REFERENCES tenants.tenants (id) ON DELETE CASCADE,
+  resource varchar NOT NULL,
+  period varchar NOT NULL,
+  used bigint NOT NULL DEFAULT 0,
+  updated_at timestamptz DEFAULT now(),
+  PRIMARY KEY (tenant_id, resource, period)
+);
+
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS plan_id varchar NULL REFERENCES tenants.plans (id);
+
+INSERT INTO tenants.plans (id, name, limits) VALUES
+  ('free', 'Free', '{"productos": 100, "clientes": 100, "carrito_compra": 500, "api_calls": 10000}'),
+  ('pro', 'Pro', '{"productos": 10000, "clientes": 10000, "carrito_compra": 100000, "api_calls": 1000000}'),
+  ('enterprise', 'Enterprise', '{}')
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants DROP COLUMN IF EXISTS plan_id;
+DROP TABLE IF EXISTS tenants.usage;
+DROP TABLE IF EXISTS tenants.plans;
+-- +goose StatementEnd
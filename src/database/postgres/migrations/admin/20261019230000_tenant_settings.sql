@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Configuración y feature flags de cada tenant, las claves sin valor usan
+-- TENANT_SETTINGS_DEFAULTS
+CREATE TABLE IF NOT EXISTS tenants.settings (
+  tenant_id uuid NOT NULL REFERENCES tenants.tenants (id) ON DELETE CASCADE,
+  key varchar NOT NULL,
+  value jsonb NOT NULL,
+  updated_at timestamptz DEFAULT now(),
+  PRIMARY KEY (tenant_id, key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tenants.settings;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Instancia que tiene el lease, solo ella puede renovarlo o liberarlo
+ALTER TABLE tenants.jobs ADD COLUMN IF NOT EXISTS claimed_by uuid NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.jobs DROP COLUMN IF EXISTS claimed_by;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Máximo de usuarios asociados a cada tenant (tenants.user_tenants)
+UPDATE tenants.plans SET limits = limits || '{"users": 3}' WHERE id = 'free';
+UPDATE tenants.plans SET limits = limits || '{"users": 50}' WHERE id = 'pro';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE tenants.plans SET limits = limits - 'users';
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Usuario que creó el tenant, los tenants existentes toman su primer miembro
+ALTER TABLE tenants.tenants
+    ADD COLUMN IF NOT EXISTS user_id uuid NULL REFERENCES tenants.users_directory (id) ON DELETE SET NULL;
+
+UPDATE tenants.tenants t
+SET user_id = (
+    SELECT ut.user_id FROM tenants.user_tenants ut
+    WHERE ut.tenant_id = t.id
+    ORDER BY ut.created_at, ut.id
+    LIMIT 1
+)
+WHERE t.user_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants.tenants
+    DROP COLUMN IF EXISTS user_id;
+-- +goose StatementEnd
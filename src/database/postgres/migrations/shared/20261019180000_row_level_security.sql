@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tablas compartidas entre tenants: cada fila pertenece al tenant fijado en
+-- app.tenant_id y las políticas RLS ocultan el resto. Sin app.tenant_id la
+-- consulta no retorna filas y los inserts fallan. Los usuarios de los tenants
+-- acceden a través del rol kosvi_pooled, que no es dueño de las tablas.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['clientes', 'productos', 'carrito_compra', 'categorias', 'impuestos', 'seed_history'] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id UUID NULL', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id SET DEFAULT current_setting(%L, true)::uuid', t, 'app.tenant_id');
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id SET NOT NULL', t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (tenant_id)', t || '_tenant_id_idx', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I USING (tenant_id = current_setting(%L, true)::uuid) WITH CHECK (tenant_id = current_setting(%L, true)::uuid)', t, 'app.tenant_id', 'app.tenant_id');
+        EXECUTE format('GRANT SELECT, INSERT, UPDATE, DELETE ON %I TO kosvi_pooled', t);
+    END LOOP;
+    EXECUTE format('GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA %I TO kosvi_pooled', current_schema());
+END $$;
+
+-- Los valores únicos lo son por tenant
+ALTER TABLE categorias DROP CONSTRAINT IF EXISTS categorias_nombre_key;
+ALTER TABLE categorias ADD CONSTRAINT categorias_tenant_nombre_key UNIQUE (tenant_id, nombre);
+ALTER TABLE impuestos DROP CONSTRAINT IF EXISTS impuestos_nombre_key;
+ALTER TABLE impuestos ADD CONSTRAINT impuestos_tenant_nombre_key UNIQUE (tenant_id, nombre);
+ALTER TABLE seed_history DROP CONSTRAINT IF EXISTS seed_history_pkey;
+ALTER TABLE seed_history ADD PRIMARY KEY (tenant_id, name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE seed_history DROP CONSTRAINT IF EXISTS seed_history_pkey;
+ALTER TABLE seed_history ADD PRIMARY KEY (name);
+ALTER TABLE impuestos DROP CONSTRAINT IF EXISTS impuestos_tenant_nombre_key;
+ALTER TABLE impuestos ADD CONSTRAINT impuestos_nombre_key UNIQUE (nombre);
+ALTER TABLE categorias DROP CONSTRAINT IF EXISTS categorias_tenant_nombre_key;
+ALTER TABLE categorias ADD CONSTRAINT categorias_nombre_key UNIQUE (nombre);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['clientes', 'productos', 'carrito_compra', 'categorias', 'impuestos', 'seed_history'] LOOP
+        EXECUTE format('REVOKE ALL ON %I FROM kosvi_pooled', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS tenant_id', t);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
@@ -1,9 +1,9 @@
 -- +goose Up
 -- +goose StatementBegin
-ALTER TABLE public.productos ADD created_at timestamptz DEFAULT now() NULL;
+ALTER TABLE productos ADD created_at timestamptz DEFAULT now() NULL;
 -- +goose StatementEnd
 
 -- +goose Down
 -- +goose StatementBegin
-ALTER TABLE public.productos DROP COLUMN created_at;
+ALTER TABLE productos DROP COLUMN created_at;
 -- +goose StatementEnd
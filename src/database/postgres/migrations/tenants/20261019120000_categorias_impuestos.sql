@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS categorias (
+    id SERIAL PRIMARY KEY,
+    nombre VARCHAR NOT NULL UNIQUE
+);
+CREATE TABLE IF NOT EXISTS impuestos (
+    id SERIAL PRIMARY KEY,
+    nombre VARCHAR NOT NULL UNIQUE,
+    tasa DECIMAL NOT NULL DEFAULT 0
+);
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS categoria_id INT NULL REFERENCES categorias(id);
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS impuesto_id INT NULL REFERENCES impuestos(id);
+
+-- Registro de los seeds aplicados en la base de datos del tenant
+CREATE TABLE IF NOT EXISTS seed_history (
+    name VARCHAR PRIMARY KEY,
+    checksum VARCHAR NOT NULL,
+    applied_at TIMESTAMPTZ DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS seed_history;
+ALTER TABLE productos DROP COLUMN IF EXISTS impuesto_id;
+ALTER TABLE productos DROP COLUMN IF EXISTS categoria_id;
+DROP TABLE IF EXISTS impuestos;
+DROP TABLE IF EXISTS categorias;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Columnas cifradas por la aplicación (tag encrypt) y sus blind index
+ALTER TABLE clientes
+    ADD COLUMN IF NOT EXISTS documento TEXT NULL,
+    ADD COLUMN IF NOT EXISTS documento_bidx VARCHAR(64) NULL,
+    ADD COLUMN IF NOT EXISTS email TEXT NULL,
+    ADD COLUMN IF NOT EXISTS email_bidx VARCHAR(64) NULL,
+    ADD COLUMN IF NOT EXISTS direccion TEXT NULL;
+CREATE INDEX IF NOT EXISTS clientes_documento_bidx_idx ON clientes (documento_bidx);
+CREATE INDEX IF NOT EXISTS clientes_email_bidx_idx ON clientes (email_bidx);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS clientes_email_bidx_idx;
+DROP INDEX IF EXISTS clientes_documento_bidx_idx;
+ALTER TABLE clientes
+    DROP COLUMN IF EXISTS direccion,
+    DROP COLUMN IF EXISTS email_bidx,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS documento_bidx,
+    DROP COLUMN IF EXISTS documento;
+-- +goose StatementEnd
@@ -6,7 +6,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -25,12 +24,9 @@ type postgres struct {
 }
 
 func (p *postgres) Connect(tenantID uuid.UUID, dsn string) (*bun.DB, error) {
+	// El tamaño del pool lo define el TenantConnectionManager
 	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
 
-	sqldb.SetMaxOpenConns(10)
-	sqldb.SetMaxIdleConns(5)
-	sqldb.SetConnMaxLifetime(5 * time.Minute)
-
 	bunDB := bun.NewDB(sqldb, pgdialect.New())
 	bunDB.AddQueryHook(newTenantQueryHook(tenantID, p.log))
 	if p.conf.IsDev() {
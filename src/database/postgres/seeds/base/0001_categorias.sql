@@ -0,0 +1,6 @@
+INSERT INTO categorias (nombre) VALUES
+    ('General'),
+    ('Alimentos'),
+    ('Bebidas'),
+    ('Servicios')
+ON CONFLICT DO NOTHING;
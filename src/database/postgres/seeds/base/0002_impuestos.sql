@@ -0,0 +1,5 @@
+INSERT INTO impuestos (nombre, tasa) VALUES
+    ('IVA 19%', 19),
+    ('IVA 5%', 5),
+    ('Exento', 0)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+INSERT INTO clientes (nombre)
+SELECT nombre FROM (VALUES ('Cliente Demo 1'), ('Cliente Demo 2'), ('Cliente Demo 3')) AS demo (nombre)
+WHERE NOT EXISTS (SELECT 1 FROM clientes WHERE clientes.nombre = demo.nombre);
@@ -0,0 +1,10 @@
+INSERT INTO productos (nombre, precio, precio_final, categoria_id, impuesto_id)
+SELECT demo.nombre, demo.precio, round(demo.precio * (1 + i.tasa / 100), 2), c.id, i.id
+FROM (VALUES
+    ('Café 500g', 18000, 'Alimentos', 'IVA 5%'),
+    ('Agua 600ml', 2500, 'Bebidas', 'IVA 19%'),
+    ('Domicilio', 5000, 'Servicios', 'Exento')
+) AS demo (nombre, precio, categoria, impuesto)
+JOIN categorias c ON c.nombre = demo.categoria
+JOIN impuestos i ON i.nombre = demo.impuesto
+WHERE NOT EXISTS (SELECT 1 FROM productos WHERE productos.nombre = demo.nombre);
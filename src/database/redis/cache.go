@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"api-test/src/common"
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Elimina las claves de la etiqueta y la etiqueta en una sola operación, para
+// no perder claves agregadas mientras se invalida
+var invalidateTag = goredis.NewScript(`
+local keys = redis.call('SMEMBERS', KEYS[1])
+for i = 1, #keys, 500 do
+	redis.call('DEL', unpack(keys, i, math.min(i + 499, #keys)))
+end
+redis.call('DEL', KEYS[1])
+return #keys
+`)
+
+// Caché en Redis, cada etiqueta es un set con sus claves. El set expira con
+// la clave que más dura (EXPIRE NX/GT, Redis 7).
+type cache struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+func NewCache(client goredis.UniversalClient, prefix string) common.Cache {
+	return &cache{client: client, prefix: prefix}
+}
+
+// Get implements common.Cache.
+func (c *cache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, common.ErrCacheMiss
+	}
+	return value, err
+}
+
+// Set implements common.Cache.
+func (c *cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, c.prefix+key, value, ttl)
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		pipe.SAdd(ctx, tagKey, c.prefix+key)
+		if ttl > 0 {
+			pipe.ExpireNX(ctx, tagKey, ttl)
+			pipe.ExpireGT(ctx, tagKey, ttl)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Delete implements common.Cache.
+func (c *cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+// TTL implements common.Cache.
+func (c *cache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := c.client.PTTL(ctx, c.prefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// go-redis retorna -2 si la clave no existe y -1 si no expira
+	switch ttl {
+	case -2:
+		return 0, common.ErrCacheMiss
+	case -1:
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// InvalidateTags implements common.Cache.
+func (c *cache) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		if err := invalidateTag.Run(ctx, c.client, []string{c.tagKey(tag)}).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cache) tagKey(tag string) string {
+	return c.prefix + "tag:" + tag
+}
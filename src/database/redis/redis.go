@@ -1 +1,66 @@
 package redis
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Redis administra la conexión compartida por la caché y los límites de peticiones
+type Redis interface {
+	Client() goredis.UniversalClient
+	// Cache retorna la caché con las claves bajo CACHE_PREFIX
+	Cache() common.Cache
+	Close() error
+}
+
+type manager struct {
+	log    common.Logger
+	conf   *config.Config
+	client *goredis.Client
+}
+
+// Client implements Redis.
+func (m *manager) Client() goredis.UniversalClient {
+	return m.client
+}
+
+// Cache implements Redis.
+func (m *manager) Cache() common.Cache {
+	return NewCache(m.client, m.conf.Cache.Prefix)
+}
+
+// Close implements Redis.
+func (m *manager) Close() error {
+	return m.client.Close()
+}
+
+// NewRedis abre la conexión de REDIS_URL y verifica que responda
+func NewRedis(log common.Logger, conf *config.Config) (Redis, error) {
+	options, err := goredis.ParseURL(conf.Redis.URL)
+	if err != nil {
+		return nil, fmt.Errorf("redis: invalid REDIS_URL: %w", err)
+	}
+	if conf.Redis.PoolSize > 0 {
+		options.PoolSize = conf.Redis.PoolSize
+	}
+	options.DialTimeout = time.Duration(conf.Redis.DialTimeout) * time.Second
+	client := goredis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.DialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Error(ctx, "Failed to ping redis", "addr", options.Addr, "error", err)
+		client.Close()
+		return nil, err
+	}
+	return &manager{
+		log:    log,
+		conf:   conf,
+		client: client,
+	}, nil
+}
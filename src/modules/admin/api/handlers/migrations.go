@@ -3,6 +3,7 @@ package handlers
 import (
 	"api-test/src/common"
 	"api-test/src/config"
+	"api-test/src/modules/admin/domain"
 	"api-test/src/modules/admin/usecase"
 
 	"github.com/gofiber/fiber/v2"
@@ -59,6 +60,225 @@ func (m *MigrationsHandler) RunTenantMigrations(c *fiber.Ctx) error {
 	})
 }
 
+func (m *MigrationsHandler) RunFleetMigrations(c *fiber.Ctx) error {
+	// Decode
+	dto := domain.DTOFleetMigration{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&dto); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+				Status:  "error",
+				Code:    fiber.StatusBadRequest,
+				Message: "Invalid request body",
+				Errors:  []common.APIError{{Message: err.Error()}},
+			})
+		}
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	report, err := m.uc.RunFleetMigrations(common.Context(c), dto)
+	if err != nil {
+		status := common.StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    status,
+			Message: "Error running tenant migrations",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	if report.Failed > 0 {
+		return c.Status(fiber.StatusMultiStatus).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusMultiStatus,
+			Message: "Some tenant migrations failed",
+			Data:    report,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant migrations run successfully",
+		Data:    report,
+	})
+}
+
+func (m *MigrationsHandler) AdminStatus(c *fiber.Ctx) error {
+	// Use case
+	status, err := m.uc.GetAdminMigrationStatus(common.Context(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusInternalServerError,
+			Message: "Error getting migration status",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Success",
+		Data:    status,
+	})
+}
+
+func (m *MigrationsHandler) TenantStatus(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	status, err := m.uc.GetTenantMigrationStatus(common.Context(c), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusInternalServerError,
+			Message: "Error getting migration status",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Success",
+		Data:    status,
+	})
+}
+
+// FleetStatus con ?strict=true responde 409 si algún tenant no está al día,
+// útil para bloquear un despliegue
+func (m *MigrationsHandler) FleetStatus(c *fiber.Ctx) error {
+	// Use case
+	status, err := m.uc.GetFleetMigrationStatus(common.Context(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusInternalServerError,
+			Message: "Error getting migration status",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	if c.QueryBool("strict") && !status.AllCurrent() {
+		return c.Status(fiber.StatusConflict).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusConflict,
+			Message: "Not all tenants are up to date",
+			Data:    status,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Success",
+		Data:    status,
+	})
+}
+
+func (m *MigrationsHandler) UpTo(c *fiber.Ctx) error {
+	return m.migrateTo(c, domain.MigrationDirectionUp)
+}
+
+func (m *MigrationsHandler) DownTo(c *fiber.Ctx) error {
+	return m.migrateTo(c, domain.MigrationDirectionDown)
+}
+
+func (m *MigrationsHandler) migrateTo(c *fiber.Ctx, direction string) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOMigrationTarget{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	plan, err := m.uc.MigrateTenantTo(common.Context(c), id, direction, dto)
+	if err != nil {
+		status := common.StatusCode(err, fiber.StatusInternalServerError)
+		return c.Status(status).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    status,
+			Message: "Error running tenant migrations",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	message := "Tenant migrations run successfully"
+	if dto.DryRun {
+		message = "Dry run, no migrations were executed"
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: message,
+		Data:    plan,
+	})
+}
+
+func (m *MigrationsHandler) RunSeeds(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	applied, err := m.uc.RunSeeds(common.Context(c), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusInternalServerError,
+			Message: "Error running tenant seeds",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant seeds run successfully",
+		Data:    applied,
+	})
+}
+
 func NewMigrationsHandler(log common.Logger, uc usecase.TenantMigrations, config config.Config) *MigrationsHandler {
 	return &MigrationsHandler{
 		log: log,
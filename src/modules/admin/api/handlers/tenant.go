@@ -4,8 +4,12 @@ import (
 	"api-test/src/common"
 	"api-test/src/modules/admin/domain"
 	"api-test/src/modules/admin/usecase"
+	"fmt"
+	"io"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type TenantHandler struct {
@@ -40,7 +44,7 @@ func (t *TenantHandler) Create(c *fiber.Ctx) error {
 	}
 
 	// Use case
-	tenant, err := t.uc.CreateTenant(common.Context(c), dto)
+	job, err := t.uc.CreateTenant(common.Context(c), dto)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(common.Response[any]{
 			Status:  "error",
@@ -53,11 +57,94 @@ func (t *TenantHandler) Create(c *fiber.Ctx) error {
 			},
 		})
 	}
-	return c.Status(fiber.StatusCreated).JSON(common.Response[any]{
+	job.StatusURL = fmt.Sprintf("%s/jobs/%s", c.Path(), job.ID)
+	c.Location(job.StatusURL)
+	return c.Status(fiber.StatusAccepted).JSON(common.Response[any]{
 		Status:  "success",
-		Code:    fiber.StatusCreated,
-		Message: "Tenant created successfully",
-		Data:    tenant,
+		Code:    fiber.StatusAccepted,
+		Message: "Tenant provisioning started",
+		Data:    job,
+	})
+}
+
+// GetJob implements TenantHandler.
+func (t *TenantHandler) GetJob(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	job, err := t.uc.GetJob(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error getting job", err)
+	}
+	job.StatusURL = c.Path()
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Success",
+		Data:    job,
+	})
+}
+
+// RetryJob implements TenantHandler.
+func (t *TenantHandler) RetryJob(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	job, err := t.uc.RetryJob(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error retrying job", err)
+	}
+	job.StatusURL = strings.TrimSuffix(c.Path(), "/retry")
+	c.Location(job.StatusURL)
+	return c.Status(fiber.StatusAccepted).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusAccepted,
+		Message: "Job retry started",
+		Data:    job,
+	})
+}
+
+// CancelJob implements TenantHandler.
+func (t *TenantHandler) CancelJob(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	job, err := t.uc.CancelJob(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error cancelling job", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Job cancelled",
+		Data:    job,
 	})
 }
 
@@ -85,6 +172,658 @@ func (t *TenantHandler) List(c *fiber.Ctx) error {
 	})
 }
 
+// Update implements TenantHandler.
+func (t *TenantHandler) Update(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOUpdateTenant{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.UpdateTenant(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error updating tenant", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant updated successfully",
+		Data:    tenant,
+	})
+}
+
+// GetPool implements TenantHandler.
+func (t *TenantHandler) GetPool(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	pool, err := t.uc.GetTenantPool(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error getting tenant pool", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant pool retrieved successfully",
+		Data:    pool,
+	})
+}
+
+// UpdatePool implements TenantHandler.
+func (t *TenantHandler) UpdatePool(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOPoolSettings{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	pool, err := t.uc.UpdateTenantPool(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error updating tenant pool", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant pool updated successfully",
+		Data:    pool,
+	})
+}
+
+// UpdateReplicas implements TenantHandler.
+func (t *TenantHandler) UpdateReplicas(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOReplicas{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.UpdateTenantReplicas(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error updating tenant replicas", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant replicas updated successfully",
+		Data:    tenant,
+	})
+}
+
+// Clone implements TenantHandler.
+func (t *TenantHandler) Clone(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOCloneTenant{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	job, err := t.uc.CloneTenant(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error cloning tenant", err)
+	}
+	job.StatusURL = t.jobURL(c, job.ID)
+	c.Location(job.StatusURL)
+	return c.Status(fiber.StatusAccepted).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusAccepted,
+		Message: "Tenant clone started",
+		Data:    job,
+	})
+}
+
+// Export implements TenantHandler.
+func (t *TenantHandler) Export(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	job, err := t.uc.ExportTenant(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error exporting tenant", err)
+	}
+	job.StatusURL = t.jobURL(c, job.ID)
+	c.Location(job.StatusURL)
+	return c.Status(fiber.StatusAccepted).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusAccepted,
+		Message: "Tenant export started",
+		Data:    job,
+	})
+}
+
+// Import implements TenantHandler.
+// Recibe el archivo en el campo archive de un multipart o el id de una exportación en JSON.
+func (t *TenantHandler) Import(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	var archive io.Reader
+	dto := domain.DTOImportTenant{}
+	if header, err := c.FormFile("archive"); err == nil {
+		file, err := header.Open()
+		if err != nil {
+			return t.errorResponse(c, "Error reading archive", err)
+		}
+		defer file.Close()
+		archive = file
+	} else {
+		if err := c.BodyParser(&dto); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+				Status:  "error",
+				Code:    fiber.StatusBadRequest,
+				Message: "Invalid request body",
+				Errors:  []common.APIError{{Message: err.Error()}},
+			})
+		}
+		// Validate
+		if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+				Status:  "error",
+				Code:    fiber.StatusBadRequest,
+				Message: "Validation error",
+				Errors:  validationErrors,
+			})
+		}
+	}
+
+	// Use case
+	job, err := t.uc.ImportTenant(common.Context(c), id, archive, dto.ExportJobID)
+	if err != nil {
+		return t.errorResponse(c, "Error importing tenant", err)
+	}
+	job.StatusURL = t.jobURL(c, job.ID)
+	c.Location(job.StatusURL)
+	return c.Status(fiber.StatusAccepted).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusAccepted,
+		Message: "Tenant import started",
+		Data:    job,
+	})
+}
+
+// Download implements TenantHandler.
+func (t *TenantHandler) Download(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	archive, err := t.uc.GetJobArchive(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error downloading export", err)
+	}
+	return c.Download(archive, fmt.Sprintf("export-%s.zip", id))
+}
+
+// Health implements TenantHandler.
+func (t *TenantHandler) Health(c *fiber.Ctx) error {
+	// Use case
+	report, err := t.uc.GetTenantsHealth(common.Context(c))
+	if err != nil {
+		return t.errorResponse(c, "Error getting tenants health", err)
+	}
+	if c.QueryBool("strict") && !report.AllAvailable() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusServiceUnavailable,
+			Message: "Some tenant databases are unavailable",
+			Data:    report,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Success",
+		Data:    report,
+	})
+}
+
+// RotateCredentials implements TenantHandler.
+func (t *TenantHandler) RotateCredentials(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.RotateTenantCredentials(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error rotating tenant credentials", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant credentials rotated successfully",
+		Data:    tenant,
+	})
+}
+
+// Suspend implements TenantHandler.
+func (t *TenantHandler) Suspend(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.SuspendTenant(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error suspending tenant", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant suspended successfully",
+		Data:    tenant,
+	})
+}
+
+// Reactivate implements TenantHandler.
+func (t *TenantHandler) Reactivate(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.ReactivateTenant(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error reactivating tenant", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant reactivated successfully",
+		Data:    tenant,
+	})
+}
+
+// RequestDeletion implements TenantHandler.
+func (t *TenantHandler) RequestDeletion(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	request, err := t.uc.RequestTenantDeletion(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error requesting tenant deletion", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Confirm the deletion with the confirmation token",
+		Data:    request,
+	})
+}
+
+// Delete implements TenantHandler.
+func (t *TenantHandler) Delete(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTODeleteTenant{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.DeleteTenant(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error deleting tenant", err)
+	}
+	if dto.GracePeriodHours > 0 {
+		return c.Status(fiber.StatusAccepted).JSON(common.Response[any]{
+			Status:  "success",
+			Code:    fiber.StatusAccepted,
+			Message: "Tenant suspended and scheduled for deletion",
+			Data:    tenant,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant deleted successfully",
+		Data:    tenant,
+	})
+}
+
+// Plans implements TenantHandler.
+func (t *TenantHandler) Plans(c *fiber.Ctx) error {
+	// Use case
+	plans, err := t.uc.ListPlans(common.Context(c))
+	if err != nil {
+		return t.errorResponse(c, "Error getting plans", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Plans retrieved successfully",
+		Data:    plans,
+	})
+}
+
+// UpdatePlan implements TenantHandler.
+func (t *TenantHandler) UpdatePlan(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOAssignPlan{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	tenant, err := t.uc.UpdateTenantPlan(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error updating tenant plan", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant plan updated successfully",
+		Data:    tenant,
+	})
+}
+
+// Usage implements TenantHandler.
+func (t *TenantHandler) Usage(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	usage, err := t.uc.GetTenantUsage(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error getting tenant usage", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant usage retrieved successfully",
+		Data:    usage,
+	})
+}
+
+// Settings implements TenantHandler.
+func (t *TenantHandler) Settings(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+
+	// Use case
+	settings, err := t.uc.GetTenantSettings(common.Context(c), id)
+	if err != nil {
+		return t.errorResponse(c, "Error getting tenant settings", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant settings retrieved successfully",
+		Data:    settings,
+	})
+}
+
+// UpdateSettings implements TenantHandler.
+func (t *TenantHandler) UpdateSettings(c *fiber.Ctx) error {
+	// Decode
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid ID format",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	dto := domain.DTOUpdateSettings{}
+	if err := c.BodyParser(&dto); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body",
+			Errors:  []common.APIError{{Message: err.Error()}},
+		})
+	}
+	// Validate
+	if validationErrors := common.Validate(dto); len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(common.Response[any]{
+			Status:  "error",
+			Code:    fiber.StatusBadRequest,
+			Message: "Validation error",
+			Errors:  validationErrors,
+		})
+	}
+
+	// Use case
+	settings, err := t.uc.UpdateTenantSettings(common.Context(c), id, dto)
+	if err != nil {
+		return t.errorResponse(c, "Error updating tenant settings", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(common.Response[any]{
+		Status:  "success",
+		Code:    fiber.StatusOK,
+		Message: "Tenant settings updated successfully",
+		Data:    settings,
+	})
+}
+
+func (t *TenantHandler) errorResponse(c *fiber.Ctx, message string, err error) error {
+	status := common.StatusCode(err, fiber.StatusInternalServerError)
+	return c.Status(status).JSON(common.Response[any]{
+		Status:  "error",
+		Code:    status,
+		Message: message,
+		Errors:  []common.APIError{{Message: err.Error()}},
+	})
+}
+
+// URL del job a partir de la ruta /tenants/:id/...
+func (t *TenantHandler) jobURL(c *fiber.Ctx, id uuid.UUID) string {
+	prefix, _, _ := strings.Cut(c.Path(), "/tenants/")
+	return fmt.Sprintf("%s/tenants/jobs/%s", prefix, id)
+}
+
 func NewTenantHandler(log common.Logger, uc usecase.Tenant) *TenantHandler {
 	return &TenantHandler{
 		log: log,
@@ -19,6 +19,7 @@ type adminRoutes struct {
 	ucTenantMigration usecase.TenantMigrations
 	ucAuth         usecase.Auth
 	config         *config.Config
+	tenant         *common.TenantConnectionManager
 	app            fiber.Router
 	tenantHandlers *handlers.TenantHandler
 	authHandlers   *handlers.AuthHandler
@@ -36,13 +37,44 @@ func (t *adminRoutes) RegisterRoutes() {
 	// Tenant
 	t.app.Get("/tenants", t.tenantHandlers.List)
 	t.app.Post("/tenants", t.tenantHandlers.Create)
+	t.app.Get("/tenants/plans", t.tenantHandlers.Plans)
+	t.app.Get("/tenants/jobs/:id", t.tenantHandlers.GetJob)
+	t.app.Post("/tenants/jobs/:id/retry", t.tenantHandlers.RetryJob)
+	t.app.Get("/tenants/jobs/:id/download", t.tenantHandlers.Download)
+	t.app.Delete("/tenants/jobs/:id", t.tenantHandlers.CancelJob)
+	t.app.Put("/tenants/:id", t.tenantHandlers.Update)
+	t.app.Post("/tenants/:id/suspend", t.tenantHandlers.Suspend)
+	t.app.Post("/tenants/:id/reactivate", t.tenantHandlers.Reactivate)
+	t.app.Post("/tenants/:id/credentials/rotate", t.tenantHandlers.RotateCredentials)
+	t.app.Get("/tenants/:id/pool", t.tenantHandlers.GetPool)
+	t.app.Put("/tenants/:id/pool", t.tenantHandlers.UpdatePool)
+	t.app.Put("/tenants/:id/replicas", t.tenantHandlers.UpdateReplicas)
+	t.app.Put("/tenants/:id/plan", t.tenantHandlers.UpdatePlan)
+	t.app.Get("/tenants/:id/usage", t.tenantHandlers.Usage)
+	t.app.Get("/tenants/:id/settings", t.tenantHandlers.Settings)
+	t.app.Put("/tenants/:id/settings", t.tenantHandlers.UpdateSettings)
+	t.app.Post("/tenants/:id/clone", t.tenantHandlers.Clone)
+	t.app.Post("/tenants/:id/export", t.tenantHandlers.Export)
+	t.app.Post("/tenants/:id/import", t.tenantHandlers.Import)
+	t.app.Post("/tenants/:id/deletion", t.tenantHandlers.RequestDeletion)
+	t.app.Delete("/tenants/:id", t.tenantHandlers.Delete)
+
+	// Health
+	t.app.Get("/health/tenants", t.tenantHandlers.Health)
 
 	// Migrations
 	t.app.Post("/migrations/admin", t.migrationsHandlers.RunAdminMigrations)
 	t.app.Post("/migrations/tenant", t.migrationsHandlers.RunTenantMigrations)
+	t.app.Post("/migrations/tenants", t.tenant.OperatorOnly(), t.migrationsHandlers.RunFleetMigrations)
+	t.app.Post("/migrations/tenants/:id/up-to", t.tenant.OperatorOnly(), t.migrationsHandlers.UpTo)
+	t.app.Post("/migrations/tenants/:id/down-to", t.tenant.OperatorOnly(), t.migrationsHandlers.DownTo)
+	t.app.Post("/migrations/tenants/:id/seeds", t.tenant.OperatorOnly(), t.migrationsHandlers.RunSeeds)
+	t.app.Get("/migrations/status/admin", t.tenant.OperatorOnly(), t.migrationsHandlers.AdminStatus)
+	t.app.Get("/migrations/status/tenants", t.tenant.OperatorOnly(), t.migrationsHandlers.FleetStatus)
+	t.app.Get("/migrations/status/tenants/:id", t.tenant.TenantOrOperator("id"), t.migrationsHandlers.TenantStatus)
 }
 
-func NewAdminRoutes(log common.Logger, app fiber.Router, ucTenant usecase.Tenant, ucTenantMigration usecase.TenantMigrations, ucAuth usecase.Auth, config *config.Config) AdminRoutes {
+func NewAdminRoutes(log common.Logger, app fiber.Router, ucTenant usecase.Tenant, ucTenantMigration usecase.TenantMigrations, ucAuth usecase.Auth, config *config.Config, tenant *common.TenantConnectionManager) AdminRoutes {
 	
 	return &adminRoutes{
 		log:            log,
@@ -50,6 +82,7 @@ func NewAdminRoutes(log common.Logger, app fiber.Router, ucTenant usecase.Tenant
 		ucTenantMigration: ucTenantMigration,
 		ucAuth:         ucAuth,
 		config:         config,
+		tenant:         tenant,
 		app:            app,
 		tenantHandlers: handlers.NewTenantHandler(log, ucTenant),
 		authHandlers:   handlers.NewAuthHandler(log, *config, ucAuth),
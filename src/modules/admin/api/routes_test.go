@@ -0,0 +1,56 @@
+package api
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"api-test/src/modules/admin/api/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Las rutas de operador rechazan a los usuarios de tenants antes de llegar al handler
+func Test_adminRoutes_OperatorOnly(t *testing.T) {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.Operators.UserIDs = []uuid.UUID{uuid.New()}
+	manager := common.NewTenantConnectionManager(conf)
+	tenantID := uuid.New()
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(manager.UserIDKey, uuid.New())
+		c.Locals(manager.TenantKey, tenantID)
+		return c.Next()
+	})
+	routes := &adminRoutes{
+		app:                app,
+		config:             conf,
+		tenant:             manager,
+		tenantHandlers:     &handlers.TenantHandler{},
+		authHandlers:       &handlers.AuthHandler{},
+		migrationsHandlers: &handlers.MigrationsHandler{},
+	}
+	routes.RegisterRoutes()
+
+	other := uuid.New().String()
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/migrations/tenants"},
+		{http.MethodPost, "/migrations/tenants/" + other + "/up-to"},
+		{http.MethodPost, "/migrations/tenants/" + other + "/down-to"},
+		{http.MethodPost, "/migrations/tenants/" + other + "/seeds"},
+		{http.MethodGet, "/migrations/status/admin"},
+		{http.MethodGet, "/migrations/status/tenants"},
+		{http.MethodGet, "/migrations/status/tenants/" + other},
+	} {
+		resp, err := app.Test(httptest.NewRequest(route.method, route.path, nil))
+		if err != nil {
+			t.Fatalf("%s %s: %v", route.method, route.path, err)
+		}
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s %s: expected 403, got %d", route.method, route.path, resp.StatusCode)
+		}
+	}
+}
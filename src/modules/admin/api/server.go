@@ -7,6 +7,7 @@ import (
 	"api-test/src/modules/admin/repository/implements"
 	"api-test/src/modules/admin/usecase"
 	"context"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -28,6 +29,49 @@ func (t *AdminAPI) RegisterAllTenants(ctx context.Context) error {
 	return t.ucTenant.RegisterAllTenants(ctx)
 }
 
+// StartJobs inicia los procesos periódicos del módulo admin
+func (t *AdminAPI) StartJobs(ctx context.Context) {
+	go func() {
+		// Reanudar los aprovisionamientos interrumpidos por un reinicio
+		if err := t.ucTenant.ResumeJobs(ctx); err != nil {
+			t.log.Error(ctx, "Error resuming jobs", "error", err)
+		}
+		t.every(ctx, "resume jobs", time.Duration(t.config.TenantLifecycle.JobsResumeInterval)*time.Second, t.ucTenant.ResumeJobs)
+	}()
+	go t.every(ctx, "purge tenants", time.Duration(t.config.TenantLifecycle.PurgeInterval)*time.Second, t.ucTenant.PurgeTenants)
+	go func() {
+		if err := t.ucTenant.ListenTenantEvents(ctx); err != nil {
+			t.log.Error(ctx, "Error listening tenant events", "error", err)
+		}
+	}()
+	go t.every(ctx, "sync tenants", time.Duration(t.config.TenantLifecycle.CredentialsSyncInterval)*time.Second, t.ucTenant.SyncTenants)
+	if maxAge := time.Duration(t.config.TenantLifecycle.CredentialsMaxAge) * time.Hour; maxAge > 0 {
+		go t.every(ctx, "rotate credentials", time.Duration(t.config.TenantLifecycle.CredentialsCheckInterval)*time.Second, func(ctx context.Context) error {
+			_, err := t.ucTenant.RotateAllCredentials(ctx, maxAge)
+			return err
+		})
+	}
+}
+
+func (t *AdminAPI) every(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				t.log.Error(ctx, "Error running job", "job", name, "error", err)
+			}
+		}
+	}
+}
+
 func NewAdminAPI(
 	log common.Logger,
 	app fiber.Router,
@@ -37,15 +81,23 @@ func NewAdminAPI(
 	psql postgres.Database) *AdminAPI {
 
 	repoTenant := implements.NewTenantRepository(log, tenant)
-	ucTenant := usecase.NewTenant(log, repoTenant, migrations, config, tenant, psql)
+	repoJob := implements.NewJobRepository(log, tenant)
+	repoEvents := implements.NewTenantEventRepository(log, tenant)
+	repoData := implements.NewTenantDataRepository(log, tenant)
+	repoPlans := implements.NewPlanRepository(log, tenant)
+	repoSettings := implements.NewSettingsRepository(log, tenant)
+	ucTenant := usecase.NewTenant(log, repoTenant, repoJob, repoEvents, repoData, repoPlans, repoSettings, migrations, config, tenant, psql)
+	tenant.SetQuotas(ucTenant)
+	tenant.SetSettings(ucTenant)
 	repoUserDirectory := implements.NewUserRepository(log, tenant)
 	ucAuth := usecase.NewAuth(log, config, tenant, repoUserDirectory)
 
 	return &AdminAPI{
 		log:    log,
 		app:    app,
+		config: config,
 		ucTenant: ucTenant,
-		routes: NewAdminRoutes(log, app, ucTenant, migrations, ucAuth, config),
+		routes: NewAdminRoutes(log, app, ucTenant, migrations, ucAuth, config, tenant),
 		tenant: tenant,
 	}
 }
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	AuditMigrationUpTo   = "migrations.up_to"
+	AuditMigrationDownTo = "migrations.down_to"
+)
+
+type TableAuditLog struct {
+	bun.BaseModel `bun:"table:tenants.audit_log"`
+
+	ID           uuid.UUID       `bun:"id,pk"`
+	Action       string          `bun:"action,notnull"`
+	TenantID     uuid.UUID       `bun:"tenant_id,nullzero"`
+	UserID       uuid.UUID       `bun:"user_id,nullzero"`
+	Reason       string          `bun:"reason,notnull"`
+	Details      json.RawMessage `bun:"details,type:jsonb,nullzero"`
+	CreationDate time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+}
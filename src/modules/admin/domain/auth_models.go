@@ -15,6 +15,8 @@ const (
 type Claims struct {
 	UserID  uuid.UUID   `json:"user_id"`
 	Tenants []uuid.UUID `json:"tenants,omitempty"`
-	Type    TokenType   `json:"type"`
+	// Tenant por defecto del usuario, lo usa el resolver por claim
+	DefaultTenant uuid.UUID `json:"default_tenant,omitzero"`
+	Type          TokenType `json:"type"`
 	jwt.RegisteredClaims
 }
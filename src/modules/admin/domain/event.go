@@ -0,0 +1,22 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Canal de Postgres donde se publican los cambios de los tenants
+const TenantEventsChannel = "tenant_events"
+
+const (
+	TenantEventCreated     = "created"
+	TenantEventUpdated     = "updated"
+	TenantEventSuspended   = "suspended"
+	TenantEventReactivated = "reactivated"
+	TenantEventDeleted     = "deleted"
+)
+
+// TenantEvent avisa a las demás instancias que deben recargar el tenant
+type TenantEvent struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Event    string    `json:"event"`
+	// Instancia que hizo el cambio, ignora sus propios eventos
+	Origin uuid.UUID `json:"origin"`
+}
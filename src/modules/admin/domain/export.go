@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Versión del formato de los archivos de exportación. La versión 2 agrega la
+// huella de la clave de columnas cifradas.
+const ExportFormatVersion = 2
+
+// Archivo con el manifiesto dentro del zip de exportación
+const ExportManifestFile = "manifest.json"
+
+// Tabla exportada, sus filas van en File como NDJSON
+type ExportTable struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    int      `json:"rows"`
+	File    string   `json:"file"`
+}
+
+// Manifiesto de la exportación, las tablas van en el orden en que se importan.
+// Las columnas cifradas se exportan tal como están guardadas, FieldKey es la
+// huella de su clave y queda vacía si el tenant no tiene clave de columnas.
+type ExportManifest struct {
+	FormatVersion    int           `json:"format_version"`
+	TenantID         uuid.UUID     `json:"tenant_id"`
+	TenantName       string        `json:"tenant_name"`
+	Isolation        string        `json:"isolation"`
+	MigrationVersion int64         `json:"migration_version"`
+	CreatedAt        time.Time     `json:"created_at"`
+	Tables           []ExportTable `json:"tables"`
+	FieldKey         string        `json:"field_key,omitempty"`
+}
+
+// Payload del job de importación
+type ImportPayload struct {
+	Archive string `json:"archive"`
+}
+
+// Importa el archivo de una exportación anterior en lugar de uno subido
+type DTOImportTenant struct {
+	ExportJobID uuid.UUID `json:"export_job_id" validate:"required"`
+}
+
+type DTOExportResult struct {
+	Archive  string         `json:"archive"`
+	Size     int64          `json:"size"`
+	Manifest ExportManifest `json:"manifest"`
+}
+
+type DTOImportResult struct {
+	MigrationVersion int64 `json:"migration_version"`
+	Tables           int   `json:"tables"`
+	Rows             int   `json:"rows"`
+}
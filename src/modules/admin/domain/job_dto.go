@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DTOJob struct {
+	ID           uuid.UUID       `json:"id"`
+	Kind         string          `json:"kind"`
+	TenantID     uuid.UUID       `json:"tenant_id"`
+	Status       string          `json:"status"`
+	Step         string          `json:"step"`
+	Attempts     int             `json:"attempts"`
+	LastError    string          `json:"last_error,omitempty"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	StatusURL    string          `json:"status_url,omitempty"`
+	CreationDate time.Time       `json:"creation_date"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	JobKindProvision = "provision"
+	JobKindExport    = "export"
+	JobKindImport    = "import"
+	JobKindClone     = "clone"
+)
+
+// Estados de los jobs, los de aprovisionamiento y clonación siguen la máquina
+// de estados pending -> database_created -> migrated -> seeded -> ready / failed,
+// las exportaciones pending -> ready y las importaciones pending -> migrated -> ready
+const (
+	JobStatusPending         = "pending"
+	JobStatusDatabaseCreated = "database_created"
+	JobStatusMigrated        = "migrated"
+	JobStatusSeeded          = "seeded"
+	JobStatusReady           = "ready"
+	JobStatusFailed          = "failed"
+	JobStatusCancelled       = "cancelled"
+)
+
+type TableJob struct {
+	bun.BaseModel `bun:"table:tenants.jobs"`
+
+	ID           uuid.UUID       `bun:"id,pk"`
+	Kind         string          `bun:"kind,notnull"`
+	TenantID     uuid.UUID       `bun:"tenant_id,notnull"`
+	UserID       uuid.UUID       `bun:"user_id,nullzero"`
+	Status       string          `bun:"status,notnull"`
+	Step         string          `bun:"step,notnull"`
+	Attempts     int             `bun:"attempts,notnull"`
+	LastError    string          `bun:"last_error,nullzero"`
+	Payload      json.RawMessage `bun:"payload,type:jsonb,nullzero"`
+	Result       json.RawMessage `bun:"result,type:jsonb,nullzero"`
+	ClaimedUntil time.Time       `bun:"claimed_until,nullzero"`
+	ClaimedBy    uuid.UUID       `bun:"claimed_by,nullzero"`
+	CreationDate time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time       `bun:"updated_at,nullzero,default:current_timestamp"`
+}
+
+// IsFinished indica si el job llegó a un estado terminal
+func (table *TableJob) IsFinished() bool {
+	return table.Status == JobStatusReady || table.Status == JobStatusCancelled
+}
+
+// CreatesTenant indica si el job crea el tenant, al fallar o cancelarse se revierte
+func (table *TableJob) CreatesTenant() bool {
+	return table.Kind == JobKindProvision || table.Kind == JobKindClone
+}
+
+func (table *TableJob) ToDTO() DTOJob {
+	return DTOJob{
+		ID:           table.ID,
+		Kind:         table.Kind,
+		TenantID:     table.TenantID,
+		Status:       table.Status,
+		Step:         table.Step,
+		Attempts:     table.Attempts,
+		LastError:    table.LastError,
+		Result:       table.Result,
+		CreationDate: table.CreationDate,
+		UpdatedAt:    table.UpdatedAt,
+	}
+}
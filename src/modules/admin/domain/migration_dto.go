@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resultado de migrar un tenant dentro de una ejecución masiva
+const (
+	MigrationResultSucceeded = "succeeded"
+	MigrationResultFailed    = "failed"
+	MigrationResultSkipped   = "skipped"
+)
+
+type DTOFleetMigration struct {
+	TenantIDs       []uuid.UUID `json:"tenant_ids"`
+	Concurrency     int         `json:"concurrency" validate:"min=0,max=64"`
+	TimeoutSeconds  int         `json:"timeout_seconds" validate:"min=0"`
+	ContinueOnError bool        `json:"continue_on_error"`
+}
+
+type DTOTenantMigrationResult struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Name     string    `json:"name,omitempty"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration"`
+}
+
+type DTOFleetMigrationReport struct {
+	Total     int                        `json:"total"`
+	Succeeded int                        `json:"succeeded"`
+	Failed    int                        `json:"failed"`
+	Skipped   int                        `json:"skipped"`
+	Duration  string                     `json:"duration"`
+	Results   []DTOTenantMigrationResult `json:"results"`
+}
+
+type DTOMigration struct {
+	Version   int64     `json:"version"`
+	Source    string    `json:"source,omitempty"`
+	State     string    `json:"state"`
+	AppliedAt time.Time `json:"applied_at,omitzero"`
+}
+
+// Estado de migraciones de una base de datos (admin o tenant).
+// Dirty indica versiones aplicadas que no existen en el código y
+// OutOfOrder migraciones pendientes anteriores a la última aplicada.
+type DTOMigrationStatus struct {
+	TenantID       uuid.UUID      `json:"tenant_id"`
+	Name           string         `json:"name,omitempty"`
+	CurrentVersion int64          `json:"current_version"`
+	LatestVersion  int64          `json:"latest_version"`
+	UpToDate       bool           `json:"up_to_date"`
+	Dirty          bool           `json:"dirty"`
+	Unknown        []int64        `json:"unknown,omitempty"`
+	OutOfOrder     []int64        `json:"out_of_order,omitempty"`
+	Applied        []DTOMigration `json:"applied,omitempty"`
+	Pending        []DTOMigration `json:"pending,omitempty"`
+	Error          string         `json:"error,omitempty"`
+}
+
+type DTOMigrationVersionGroup struct {
+	Version int64       `json:"version"`
+	Tenants []uuid.UUID `json:"tenants"`
+}
+
+type DTOFleetMigrationStatus struct {
+	LatestVersion int64                      `json:"latest_version"`
+	Total         int                        `json:"total"`
+	UpToDate      int                        `json:"up_to_date"`
+	Behind        int                        `json:"behind"`
+	Dirty         int                        `json:"dirty"`
+	Failed        int                        `json:"failed"`
+	Versions      []DTOMigrationVersionGroup `json:"versions"`
+	Tenants       []DTOMigrationStatus       `json:"tenants"`
+}
+
+// AllCurrent indica si todos los tenants están en la última versión sin inconsistencias
+func (dto *DTOFleetMigrationStatus) AllCurrent() bool {
+	return dto.UpToDate == dto.Total
+}
+
+const (
+	MigrationDirectionUp   = "up"
+	MigrationDirectionDown = "down"
+)
+
+type DTOMigrationTarget struct {
+	Version            int64  `json:"version" validate:"min=0"`
+	Reason             string `json:"reason" validate:"required,min=5"`
+	DryRun             bool   `json:"dry_run"`
+	ConfirmDestructive bool   `json:"confirm_destructive"`
+}
+
+type DTOPlannedMigration struct {
+	Version     int64    `json:"version"`
+	Source      string   `json:"source"`
+	Destructive bool     `json:"destructive"`
+	Statements  []string `json:"statements,omitempty"`
+}
+
+type DTOMigrationPlan struct {
+	TenantID    uuid.UUID             `json:"tenant_id"`
+	Direction   string                `json:"direction"`
+	FromVersion int64                 `json:"from_version"`
+	ToVersion   int64                 `json:"to_version"`
+	DryRun      bool                  `json:"dry_run"`
+	Destructive bool                  `json:"destructive"`
+	Migrations  []DTOPlannedMigration `json:"migrations"`
+}
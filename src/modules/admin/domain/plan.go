@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Período de los recursos que limitan el total de filas, api_calls se
+// cuenta por mes (YYYY-MM)
+const UsagePeriodTotal = "total"
+
+type TablePlan struct {
+	bun.BaseModel `bun:"table:tenants.plans"`
+
+	ID   string `bun:"id,pk"`
+	Name string `bun:"name,notnull"`
+	// Límite por recurso, un recurso sin límite no se limita
+	Limits       map[string]int64 `bun:"limits,type:jsonb,notnull"`
+	CreationDate time.Time        `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+func (table *TablePlan) ToDTO() DTOPlan {
+	return DTOPlan{
+		ID:     table.ID,
+		Name:   table.Name,
+		Limits: table.Limits,
+	}
+}
+
+type TableUsage struct {
+	bun.BaseModel `bun:"table:tenants.usage,alias:usage"`
+
+	TenantID  uuid.UUID `bun:"tenant_id,pk"`
+	Resource  string    `bun:"resource,pk"`
+	Period    string    `bun:"period,pk"`
+	Used      int64     `bun:"used,notnull"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,default:current_timestamp"`
+}
+
+type DTOPlan struct {
+	ID     string           `json:"id"`
+	Name   string           `json:"name"`
+	Limits map[string]int64 `json:"limits"`
+}
+
+// Plan del tenant, vacío quita los límites
+type DTOAssignPlan struct {
+	PlanID string `json:"plan_id"`
+}
+
+type DTOResourceUsage struct {
+	Resource string `json:"resource"`
+	Period   string `json:"period"`
+	Used     int64  `json:"used"`
+	// Sin límite se omite
+	Limit int64 `json:"limit,omitempty"`
+}
+
+type DTOUsage struct {
+	TenantID  uuid.UUID          `json:"tenant_id"`
+	Plan      *DTOPlan           `json:"plan,omitempty"`
+	Resources []DTOResourceUsage `json:"resources"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Tipos de los valores de la configuración, el valor de una clave con valor
+// por defecto debe ser del mismo tipo
+const (
+	SettingTypeString = "string"
+	SettingTypeBool   = "bool"
+	SettingTypeNumber = "number"
+	SettingTypeObject = "object"
+	SettingTypeArray  = "array"
+)
+
+type TableSetting struct {
+	bun.BaseModel `bun:"table:tenants.settings,alias:setting"`
+
+	TenantID  uuid.UUID       `bun:"tenant_id,pk"`
+	Key       string          `bun:"key,pk"`
+	Value     json.RawMessage `bun:"value,type:jsonb,notnull"`
+	UpdatedAt time.Time       `bun:"updated_at,nullzero,default:current_timestamp"`
+}
+
+type DTOSetting struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+	Type  string          `json:"type"`
+	// Indica que el tenant usa el valor por defecto
+	Default   bool      `json:"default"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// Valores por clave, null vuelve al valor por defecto
+type DTOUpdateSettings struct {
+	Settings map[string]json.RawMessage `json:"settings" validate:"required,min=1"`
+}
@@ -7,20 +7,39 @@ import (
 )
 
 type DTOTenant struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	UserID       uuid.UUID `json:"user_id"`
-	DBName       string    `json:"db_name"`
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Vacío lo genera a partir del nombre
+	Slug     string    `json:"slug,omitempty" validate:"omitempty,dns_rfc1035_label"`
+	UserID   uuid.UUID `json:"user_id"`
+	DBName   string    `json:"db_name"`
+	DBSchema string    `json:"db_schema,omitempty"`
+	// database, schema o shared, vacío usa TENANT_DEFAULT_ISOLATION
+	Isolation    string    `json:"isolation,omitempty" validate:"omitempty,oneof=database schema shared"`
+	Replicas     []string  `json:"replicas,omitempty"`
+	Plan         string    `json:"plan,omitempty"`
 	IsActive     bool      `json:"is_active"`
+	Status       string    `json:"status,omitempty"`
 	CreationDate time.Time `json:"creation_date"`
+	SuspendedAt  time.Time `json:"suspended_at,omitzero"`
+	DeleteAfter  time.Time `json:"delete_after,omitzero"`
 }
 
 func (dto *DTOTenant) FromTable(table TableTenant) {
 	dto.ID = table.ID
 	dto.Name = table.Name
+	dto.Slug = table.Slug
+	dto.UserID = table.UserID
 	dto.DBName = table.DBName
+	dto.DBSchema = table.DBSchema
+	dto.Isolation = table.Isolation
+	dto.Replicas = table.DBReplicas
+	dto.Plan = table.PlanID
 	dto.IsActive = table.IsActive
+	dto.Status = table.Status
 	dto.CreationDate = table.CreationDate
+	dto.SuspendedAt = table.SuspendedAt
+	dto.DeleteAfter = table.DeleteAfter
 }
 
 func (dto *DTOTenant) ToTable() TableTenant {
@@ -33,6 +52,45 @@ func (dto *DTOTenant) ToTable() TableTenant {
 	}
 }
 
+type DTOUpdateTenant struct {
+	Name string `json:"name" validate:"required"`
+	// Vacío mantiene el actual
+	Slug string `json:"slug,omitempty" validate:"omitempty,dns_rfc1035_label"`
+}
+
+// Réplicas de lectura del tenant, vacío las quita
+type DTOReplicas struct {
+	Replicas []string `json:"replicas" validate:"dive,hostname_port"`
+}
+
+// Copia de un tenant existente, sin aislamiento usa el del origen
+type DTOCloneTenant struct {
+	Name      string `json:"name" validate:"required"`
+	Slug      string `json:"slug,omitempty" validate:"omitempty,dns_rfc1035_label"`
+	Isolation string `json:"isolation,omitempty" validate:"omitempty,oneof=database schema"`
+	// Reemplaza las columnas de TENANT_PII_COLUMNS en la copia
+	Anonymize bool `json:"anonymize"`
+}
+
+// Payload del job de clonación, Template indica que la base de datos se creó
+// con CREATE DATABASE ... TEMPLATE y ya tiene los datos
+type ClonePayload struct {
+	SourceID  uuid.UUID `json:"source_id"`
+	Anonymize bool      `json:"anonymize"`
+	Template  bool      `json:"template,omitempty"`
+}
+
+type DTODeletionRequest struct {
+	TenantID          uuid.UUID `json:"tenant_id"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type DTODeleteTenant struct {
+	ConfirmationToken string `json:"confirmation_token" validate:"required"`
+	GracePeriodHours  int    `json:"grace_period_hours" validate:"min=0,max=720"`
+}
+
 type DTOUserDirectory struct {
 	ID           uuid.UUID       `json:"id"`
 	Email        string          `json:"email"`
@@ -126,3 +184,75 @@ type DTOAuth struct {
 	RefreshToken string    `json:"refresh_token" validate:"required"`
 	ExpiresIn    time.Time `json:"expires_in"`
 }
+
+type DTOTenantFailure struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Name     string    `json:"name,omitempty"`
+	Error    string    `json:"error"`
+}
+
+type DTOKeyRotationReport struct {
+	ActiveKey string             `json:"active_key"`
+	Total     int                `json:"total"`
+	Rotated   int                `json:"rotated"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+	Failures  []DTOTenantFailure `json:"failures,omitempty"`
+}
+
+type DTOCredentialRotationReport struct {
+	Total    int                `json:"total"`
+	Rotated  int                `json:"rotated"`
+	Skipped  int                `json:"skipped"`
+	Failed   int                `json:"failed"`
+	Failures []DTOTenantFailure `json:"failures,omitempty"`
+}
+
+// Tamaño del pool del tenant, los valores en cero usan la configuración global
+type DTOPoolSettings struct {
+	MaxOpenConns    int `json:"max_open_conns" validate:"min=0"`
+	MaxIdleConns    int `json:"max_idle_conns" validate:"min=0"`
+	ConnMaxLifetime int `json:"conn_max_lifetime_seconds" validate:"min=0"`
+}
+
+type DTOPoolStats struct {
+	TenantID          uuid.UUID       `json:"tenant_id"`
+	Open              bool            `json:"open"`
+	Overrides         DTOPoolSettings `json:"overrides"`
+	Settings          DTOPoolSettings `json:"settings,omitzero"`
+	LastUsed          time.Time       `json:"last_used,omitzero"`
+	OpenConnections   int             `json:"open_connections"`
+	InUse             int             `json:"in_use"`
+	Idle              int             `json:"idle"`
+	WaitCount         int64           `json:"wait_count"`
+	WaitDurationMs    int64           `json:"wait_duration_ms"`
+	MaxIdleClosed     int64           `json:"max_idle_closed"`
+	MaxLifetimeClosed int64           `json:"max_lifetime_closed"`
+	BudgetUsed        int             `json:"budget_used"`
+	Budget            int             `json:"budget"`
+}
+
+type DTOTenantHealth struct {
+	TenantID  uuid.UUID `json:"tenant_id"`
+	ReplicaOf uuid.UUID `json:"replica_of,omitzero"`
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	Failures  int       `json:"failures"`
+	LastCheck time.Time `json:"last_check,omitzero"`
+	LastError string    `json:"last_error,omitempty"`
+	OpenedAt  time.Time `json:"opened_at,omitzero"`
+}
+
+type DTOHealthReport struct {
+	Total    int               `json:"total"`
+	Healthy  int               `json:"healthy"`
+	Degraded int               `json:"degraded"`
+	Open     int               `json:"open"`
+	Unknown  int               `json:"unknown"`
+	Tenants  []DTOTenantHealth `json:"tenants"`
+}
+
+// AllAvailable indica que ningún tenant tiene el circuit breaker abierto
+func (r *DTOHealthReport) AllAvailable() bool {
+	return r.Open == 0
+}
@@ -7,23 +7,74 @@ import (
 	"github.com/uptrace/bun"
 )
 
+const (
+	TenantStatusProvisioning = "provisioning"
+	TenantStatusReady        = "ready"
+	TenantStatusFailed       = "failed"
+)
+
+// Aislamiento de los datos del tenant
+const (
+	// Base de datos propia
+	TenantIsolationDatabase = "database"
+	// Schema propio en la base de datos compartida
+	TenantIsolationSchema = "schema"
+	// Tablas compartidas con otros tenants, filtradas con Row-Level Security
+	TenantIsolationShared = "shared"
+)
+
 type TableTenant struct {
 	bun.BaseModel `bun:"table:tenants.tenants"`
 
 	ID                uuid.UUID `bun:"id,pk"`
 	Name              string    `bun:"name,notnull"`
+	// Usuario que creó el tenant, el único miembro que puede suspenderlo,
+	// eliminarlo o rotar sus credenciales
+	UserID            uuid.UUID `bun:"user_id,nullzero"`
+	// Identificador legible y único, usado en subdominios y rutas
+	Slug              string    `bun:"slug,notnull"`
 	DBName            string    `bun:"db_name,notnull"`
+	// Schema del tenant en la base de datos compartida, vacío si tiene base de datos propia
+	DBSchema          string    `bun:"db_schema,nullzero"`
+	Isolation         string    `bun:"isolation,notnull,default:'database'"`
 	DBHost            string    `bun:"db_host,notnull"`
 	DBPort            int64     `bun:"db_port,notnull"`
+	// Réplicas de lectura (host:port) con las mismas credenciales
+	DBReplicas        []string  `bun:"db_replicas,array"`
 	DBUser            string    `bun:"db_user,notnull"`
 	DBPassword        []byte    `bun:"db_password,notnull"`
 	IsActive          bool      `bun:"is_active,notnull,default:true"`
+	Status            string    `bun:"status,notnull,default:'ready'"`
 	IV                []byte    `bun:"iv,notnull"`
+	DataKey           []byte    `bun:"data_key"`
+	// Tenant del que se deriva la clave de las columnas cifradas, vacío usa ID
+	FieldKeyID        uuid.UUID `bun:"field_key_id,nullzero"`
+	// Plan con los límites de uso, vacío sin límites
+	PlanID            string    `bun:"plan_id,nullzero"`
 	Version           string    `bun:"version,notnull"`
 	CreationDate      time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt         time.Time `bun:"updated_at,nullzero,default:current_timestamp"`
+	SuspendedAt       time.Time `bun:"suspended_at,nullzero"`
+	DeleteAfter       time.Time `bun:"delete_after,nullzero"`
+	DeletionToken     []byte    `bun:"deletion_token"`
+	DeletionExpiresAt time.Time `bun:"deletion_token_expires_at,nullzero"`
+	// Última rotación de la contraseña del usuario de base de datos
+	CredentialsRotatedAt time.Time `bun:"credentials_rotated_at,nullzero"`
+	// Tamaño del pool, cero usa la configuración global
+	PoolMaxOpenConns    int `bun:"pool_max_open_conns,nullzero"`
+	PoolMaxIdleConns    int `bun:"pool_max_idle_conns,nullzero"`
+	PoolConnMaxLifetime int `bun:"pool_conn_max_lifetime,nullzero"`
 	PasswordPlaintext string    `bun:"-"`
 }
 
+func (table *TableTenant) PoolSettings() DTOPoolSettings {
+	return DTOPoolSettings{
+		MaxOpenConns:    table.PoolMaxOpenConns,
+		MaxIdleConns:    table.PoolMaxIdleConns,
+		ConnMaxLifetime: table.PoolConnMaxLifetime,
+	}
+}
+
 func (table *TableTenant) FromDTO(dto DTOTenant) {
 	table.ID = dto.ID
 	table.Name = dto.Name
@@ -36,9 +87,18 @@ func (table *TableTenant) ToDTO() DTOTenant {
 	return DTOTenant{
 		ID:           table.ID,
 		Name:         table.Name,
+		Slug:         table.Slug,
+		UserID:       table.UserID,
 		DBName:       table.DBName,
+		DBSchema:     table.DBSchema,
+		Isolation:    table.Isolation,
+		Replicas:     table.DBReplicas,
+		Plan:         table.PlanID,
 		IsActive:     table.IsActive,
+		Status:       table.Status,
 		CreationDate: table.CreationDate,
+		SuspendedAt:  table.SuspendedAt,
+		DeleteAfter:  table.DeleteAfter,
 	}
 }
 
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+)
+
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, log domain.TableAuditLog) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+type TenantDataRepository interface {
+	// Export escribe las filas de cada tabla del tenant como NDJSON en el
+	// writer que retorna newTable, todas en la misma transacción
+	Export(ctx context.Context, tenantID uuid.UUID, newTable func(table domain.ExportTable) (io.Writer, error)) ([]domain.ExportTable, error)
+	// Import reemplaza los datos de las tablas con las filas NDJSON que
+	// retorna open y retorna la cantidad de filas importadas
+	Import(ctx context.Context, tenantID uuid.UUID, tables []domain.ExportTable, open func(table domain.ExportTable) (io.ReadCloser, error)) (int, error)
+	// Anonymize reemplaza las columnas (tabla.columna) con NULL o, si no
+	// aceptan nulos, con un texto derivado del valor. Retorna las filas modificadas.
+	Anonymize(ctx context.Context, tenantID uuid.UUID, columns []string) (int, error)
+	// CountRows retorna la cantidad de filas de las tablas del tenant que existen
+	CountRows(ctx context.Context, tenantID uuid.UUID, tables []string) (map[string]int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+	"time"
+)
+
+type TenantEventRepository interface {
+	PublishTenantEvent(ctx context.Context, event domain.TenantEvent) error
+	// ListenTenantEvents bloquea hasta que se cancela el contexto o se pierde
+	// la conexión, timeout limita cuánto se espera cada notificación
+	ListenTenantEvents(ctx context.Context, timeout time.Duration, handle func(domain.TenantEvent)) error
+}
@@ -0,0 +1,36 @@
+package implements
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+)
+
+type auditRepository struct {
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+}
+
+// CreateAuditLog implements repository.AuditRepository.
+func (a *auditRepository) CreateAuditLog(ctx context.Context, log domain.TableAuditLog) error {
+	db, err := a.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.NewInsert().Model(&log).Exec(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+func NewAuditRepository(log common.Logger, tenant *common.TenantConnectionManager) repository.AuditRepository {
+	return &auditRepository{
+		log:    log,
+		tenant: tenant,
+	}
+}
+
+var _ repository.AuditRepository = (*auditRepository)(nil)
@@ -0,0 +1,420 @@
+package implements
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Tabla de versiones de goose, la crean las migraciones
+const migrationsTable = "goose_db_version"
+
+// Filas por sentencia al importar y tamaño máximo de una fila NDJSON
+const (
+	importBatchSize = 500
+	maxRowSize      = 64 << 20
+)
+
+type tenantDataRepository struct {
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+}
+
+// Export implements repository.TenantDataRepository.
+// Usa una transacción repeatable read para que todas las tablas sean del mismo instante.
+func (t *tenantDataRepository) Export(ctx context.Context, tenantID uuid.UUID, newTable func(table domain.ExportTable) (io.Writer, error)) ([]domain.ExportTable, error) {
+	var exported []domain.ExportTable
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := t.inTx(ctx, tenantID, opts, func(ctx context.Context, tx bun.Tx, rowLevel bool) error {
+		tables, err := t.tables(ctx, tx, rowLevel)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			w, err := newTable(table)
+			if err != nil {
+				return err
+			}
+			if table.Rows, err = t.exportTable(ctx, tx, table, w); err != nil {
+				return fmt.Errorf("table %s: %w", table.Name, err)
+			}
+			exported = append(exported, table)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return exported, nil
+}
+
+func (t *tenantDataRepository) exportTable(ctx context.Context, tx bun.Tx, table domain.ExportTable, w io.Writer) (int, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT row_to_json(r)::text FROM (SELECT ? FROM ?) AS r",
+		bun.In(idents(table.Columns)), bun.Ident(table.Name))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return count, err
+		}
+		if _, err := w.Write(append(row, '\n')); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// Import implements repository.TenantDataRepository.
+// Todo se importa en una transacción, si algo falla el tenant queda como estaba.
+func (t *tenantDataRepository) Import(ctx context.Context, tenantID uuid.UUID, tables []domain.ExportTable, open func(table domain.ExportTable) (io.ReadCloser, error)) (int, error) {
+	total := 0
+	err := t.inTx(ctx, tenantID, nil, func(ctx context.Context, tx bun.Tx, rowLevel bool) error {
+		if rowLevel {
+			return common.ConflictError("tenants with shared tables cannot be imported")
+		}
+		if len(tables) == 0 {
+			return nil
+		}
+		// Las tablas vienen del archivo, solo se aceptan las del schema del tenant
+		existing, err := t.tables(ctx, tx, rowLevel)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			i := slices.IndexFunc(existing, func(e domain.ExportTable) bool { return e.Name == table.Name })
+			if i < 0 {
+				return common.BadRequestError(fmt.Sprintf("unknown table: %s", table.Name))
+			}
+			for _, column := range table.Columns {
+				if !slices.Contains(existing[i].Columns, column) {
+					return common.BadRequestError(fmt.Sprintf("unknown column: %s.%s", table.Name, column))
+				}
+			}
+		}
+
+		// Los datos del archivo reemplazan los del tenant, incluidos los seeds
+		names := make([]string, len(tables))
+		for i, table := range tables {
+			names[i] = table.Name
+		}
+		if _, err := tx.ExecContext(ctx, "TRUNCATE ? RESTART IDENTITY CASCADE", bun.In(idents(names))); err != nil {
+			return err
+		}
+
+		for _, table := range tables {
+			r, err := open(table)
+			if err != nil {
+				return err
+			}
+			count, err := t.importTable(ctx, tx, table, r)
+			r.Close()
+			if err != nil {
+				return fmt.Errorf("table %s: %w", table.Name, err)
+			}
+			total += count
+		}
+		return t.resetSequences(ctx, tx)
+	})
+	if err != nil {
+		return 0, common.CheckDBErrorType(err)
+	}
+	return total, nil
+}
+
+// Anonymize implements repository.TenantDataRepository.
+// Las columnas que no existen en el tenant se ignoran.
+func (t *tenantDataRepository) Anonymize(ctx context.Context, tenantID uuid.UUID, columns []string) (int, error) {
+	type column struct {
+		Table     string `bun:"table_name"`
+		Column    string `bun:"column_name"`
+		DataType  string `bun:"data_type"`
+		Nullable  string `bun:"is_nullable"`
+		MaxLength int    `bun:"character_maximum_length"`
+	}
+
+	total := 0
+	err := t.inTx(ctx, tenantID, nil, func(ctx context.Context, tx bun.Tx, rowLevel bool) error {
+		var existing []column
+		err := tx.NewSelect().TableExpr("information_schema.columns").
+			Column("table_name", "column_name", "data_type", "is_nullable").
+			ColumnExpr("COALESCE(character_maximum_length, 0) AS character_maximum_length").
+			Where("table_schema = current_schema()").
+			Scan(ctx, &existing)
+		if err != nil {
+			return err
+		}
+
+		// Una sentencia por tabla con todas sus columnas
+		var tables []string
+		updates := map[string]*bun.UpdateQuery{}
+		for _, name := range columns {
+			table, col, _ := strings.Cut(name, ".")
+			i := slices.IndexFunc(existing, func(c column) bool { return c.Table == table && c.Column == col })
+			if i < 0 {
+				continue
+			}
+			c := existing[i]
+			if c.Nullable != "YES" && c.DataType != "text" && c.DataType != "character varying" {
+				t.log.Warn(ctx, "Column cannot be anonymized", "tenant_id", tenantID, "column", name, "type", c.DataType)
+				continue
+			}
+			if updates[table] == nil {
+				updates[table] = tx.NewUpdate().Table(table).Where("TRUE")
+				tables = append(tables, table)
+			}
+			if c.Nullable == "YES" {
+				updates[table].Set("? = NULL", bun.Ident(col))
+			} else {
+				length := c.MaxLength
+				if length == 0 {
+					length = 37
+				}
+				updates[table].Set("? = left('anon-' || md5(?::text), ?)", bun.Ident(col), bun.Ident(col), length)
+			}
+		}
+		for _, table := range tables {
+			result, err := updates[table].Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", table, err)
+			}
+			rows, _ := result.RowsAffected()
+			total += int(rows)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, common.CheckDBErrorType(err)
+	}
+	return total, nil
+}
+
+// CountRows implements repository.TenantDataRepository.
+// Con tablas compartidas RLS cuenta solo las filas del tenant.
+func (t *tenantDataRepository) CountRows(ctx context.Context, tenantID uuid.UUID, tables []string) (map[string]int64, error) {
+	counts := map[string]int64{}
+	opts := &sql.TxOptions{ReadOnly: true}
+	err := t.inTx(ctx, tenantID, opts, func(ctx context.Context, tx bun.Tx, rowLevel bool) error {
+		var existing []string
+		err := tx.NewSelect().TableExpr("information_schema.tables").
+			Column("table_name").
+			Where("table_schema = current_schema()").
+			Where("table_name IN (?)", bun.In(tables)).
+			Scan(ctx, &existing)
+		if err != nil {
+			return err
+		}
+		for _, table := range existing {
+			count, err := tx.NewSelect().Table(table).Count(ctx)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", table, err)
+			}
+			counts[table] = int64(count)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return counts, nil
+}
+
+// Inserta las filas en lotes, jsonb_populate_recordset convierte cada valor al
+// tipo de su columna
+func (t *tenantDataRepository) importTable(ctx context.Context, tx bun.Tx, table domain.ExportTable, r io.Reader) (int, error) {
+	columns := bun.In(idents(table.Columns))
+	insert := func(batch []byte) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO ? (?) OVERRIDING SYSTEM VALUE SELECT ? FROM jsonb_populate_recordset(NULL::?, ?::jsonb)",
+			bun.Ident(table.Name), columns, columns, bun.Ident(table.Name), string(batch))
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRowSize)
+	var batch bytes.Buffer
+	count, pending := 0, 0
+	for scanner.Scan() {
+		row := bytes.TrimSpace(scanner.Bytes())
+		if len(row) == 0 {
+			continue
+		}
+		if pending == 0 {
+			batch.WriteByte('[')
+		} else {
+			batch.WriteByte(',')
+		}
+		batch.Write(row)
+		pending++
+		count++
+		if pending == importBatchSize {
+			batch.WriteByte(']')
+			if err := insert(batch.Bytes()); err != nil {
+				return count, err
+			}
+			batch.Reset()
+			pending = 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+	if pending > 0 {
+		batch.WriteByte(']')
+		if err := insert(batch.Bytes()); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// Deja las secuencias después del mayor id importado
+func (t *tenantDataRepository) resetSequences(ctx context.Context, tx bun.Tx) error {
+	type sequence struct {
+		Table    string `bun:"table_name"`
+		Column   string `bun:"column_name"`
+		Sequence string `bun:"sequence"`
+	}
+	var sequences []sequence
+	err := tx.NewSelect().TableExpr("information_schema.columns").
+		Column("table_name", "column_name").
+		ColumnExpr("pg_get_serial_sequence(quote_ident(table_name), column_name) AS sequence").
+		Where("table_schema = current_schema()").
+		Where("pg_get_serial_sequence(quote_ident(table_name), column_name) IS NOT NULL").
+		Scan(ctx, &sequences)
+	if err != nil {
+		return err
+	}
+	for _, s := range sequences {
+		_, err := tx.ExecContext(ctx, "SELECT setval(?, COALESCE((SELECT max(?) FROM ?), 0) + 1, false)",
+			s.Sequence, bun.Ident(s.Column), bun.Ident(s.Table))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tablas del schema del tenant con sus columnas, cada tabla después de las
+// que referencia para poder insertarlas en ese orden
+func (t *tenantDataRepository) tables(ctx context.Context, tx bun.Tx, rowLevel bool) ([]domain.ExportTable, error) {
+	type column struct {
+		Table  string `bun:"table_name"`
+		Column string `bun:"column_name"`
+	}
+	type reference struct {
+		Table      string `bun:"table_name"`
+		Referenced string `bun:"referenced_table"`
+	}
+
+	var columns []column
+	err := tx.NewSelect().TableExpr("information_schema.columns AS c").
+		Join("JOIN information_schema.tables AS t ON t.table_schema = c.table_schema AND t.table_name = c.table_name").
+		ColumnExpr("c.table_name, c.column_name").
+		Where("c.table_schema = current_schema()").
+		Where("t.table_type = 'BASE TABLE'").
+		Where("c.is_generated = 'NEVER'").
+		Where("c.table_name <> ?", migrationsTable).
+		OrderExpr("c.table_name, c.ordinal_position").
+		Scan(ctx, &columns)
+	if err != nil {
+		return nil, err
+	}
+	var references []reference
+	err = tx.NewSelect().TableExpr("pg_constraint AS c").
+		ColumnExpr("c.conrelid::regclass::text AS table_name").
+		ColumnExpr("c.confrelid::regclass::text AS referenced_table").
+		Where("c.contype = 'f'").
+		Where("c.connamespace = current_schema()::regnamespace").
+		Scan(ctx, &references)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []domain.ExportTable
+	for _, c := range columns {
+		// Con tablas compartidas tenant_id lo fija la política al importar
+		if rowLevel && c.Column == "tenant_id" {
+			continue
+		}
+		if len(tables) == 0 || tables[len(tables)-1].Name != c.Table {
+			tables = append(tables, domain.ExportTable{Name: c.Table})
+		}
+		tables[len(tables)-1].Columns = append(tables[len(tables)-1].Columns, c.Column)
+	}
+
+	// Una tabla se agrega cuando ya están todas las que referencia
+	var ordered []domain.ExportTable
+	added := map[string]bool{}
+	for len(ordered) < len(tables) {
+		progress := false
+		for _, table := range tables {
+			pending := slices.ContainsFunc(references, func(r reference) bool {
+				return r.Table == table.Name && r.Referenced != table.Name && !added[r.Referenced]
+			})
+			if added[table.Name] || pending {
+				continue
+			}
+			ordered = append(ordered, table)
+			added[table.Name] = true
+			progress = true
+		}
+		if !progress {
+			return nil, fmt.Errorf("circular references between tenant tables")
+		}
+	}
+	return ordered, nil
+}
+
+// Ejecuta fn en una transacción con la conexión del tenant. Con tablas
+// compartidas fija app.tenant_id para que RLS filtre sus filas.
+func (t *tenantDataRepository) inTx(ctx context.Context, tenantID uuid.UUID, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx, rowLevel bool) error) error {
+	config, err := t.tenant.GetTenantConfig(tenantID)
+	if err != nil {
+		return err
+	}
+	db, err := t.tenant.GetDB(tenantID)
+	if err != nil {
+		return err
+	}
+	return db.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+		if config.RowLevelSecurity {
+			if err := common.SetTenant(ctx, tx, tenantID); err != nil {
+				return err
+			}
+		}
+		return fn(ctx, tx, config.RowLevelSecurity)
+	})
+}
+
+func idents(names []string) []bun.Ident {
+	result := make([]bun.Ident, len(names))
+	for i, name := range names {
+		result[i] = bun.Ident(name)
+	}
+	return result
+}
+
+func NewTenantDataRepository(log common.Logger, tenant *common.TenantConnectionManager) repository.TenantDataRepository {
+	return &tenantDataRepository{
+		log:    log,
+		tenant: tenant,
+	}
+}
+
+var _ repository.TenantDataRepository = (*tenantDataRepository)(nil)
@@ -0,0 +1,79 @@
+package implements
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+type tenantEventRepository struct {
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+}
+
+// PublishTenantEvent implements repository.TenantEventRepository.
+func (t *tenantEventRepository) PublishTenantEvent(ctx context.Context, event domain.TenantEvent) error {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := pgdriver.Notify(ctx, db, domain.TenantEventsChannel, string(payload)); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// ListenTenantEvents implements repository.TenantEventRepository.
+func (t *tenantEventRepository) ListenTenantEvents(ctx context.Context, timeout time.Duration, handle func(domain.TenantEvent)) error {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	// El listener usa una conexión propia fuera del pool
+	ln := pgdriver.NewListener(db)
+	defer ln.Close()
+	if err := ln.Listen(ctx, domain.TenantEventsChannel); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		_, payload, err := ln.ReceiveTimeout(ctx, timeout)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		var event domain.TenantEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			t.log.Warn(ctx, "Invalid tenant event", "payload", payload, "error", err)
+			continue
+		}
+		handle(event)
+	}
+	return nil
+}
+
+func NewTenantEventRepository(log common.Logger, tenant *common.TenantConnectionManager) repository.TenantEventRepository {
+	return &tenantEventRepository{
+		log:    log,
+		tenant: tenant,
+	}
+}
+
+var _ repository.TenantEventRepository = (*tenantEventRepository)(nil)
@@ -0,0 +1,163 @@
+package implements
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type jobRepository struct {
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+}
+
+// CreateJob implements repository.JobRepository.
+func (j *jobRepository) CreateJob(ctx context.Context, job domain.TableJob) (*domain.TableJob, error) {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.NewInsert().Model(&job).Exec(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return &job, nil
+}
+
+// GetJobByID implements repository.JobRepository.
+func (j *jobRepository) GetJobByID(ctx context.Context, id uuid.UUID) (*domain.TableJob, error) {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var job domain.TableJob
+	err = db.NewSelect().Model(&job).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return &job, nil
+}
+
+// UpdateJob implements repository.JobRepository.
+func (j *jobRepository) UpdateJob(ctx context.Context, job domain.TableJob, columns ...string) (*domain.TableJob, error) {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	job.UpdatedAt = time.Now()
+	q := db.NewUpdate().Model(&job).Where("id = ?", job.ID)
+	if len(columns) > 0 {
+		q = q.Column(append(columns, "updated_at")...)
+	}
+	_, err = q.Exec(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return &job, nil
+}
+
+// ClaimJob implements repository.JobRepository.
+// Solo una instancia puede ejecutar el job mientras el lease esté vigente.
+func (j *jobRepository) ClaimJob(ctx context.Context, id uuid.UUID, owner uuid.UUID, lease time.Duration) (bool, error) {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := db.NewUpdate().Model((*domain.TableJob)(nil)).
+		Set("claimed_until = ?", time.Now().Add(lease)).
+		Set("claimed_by = ?", owner).
+		Set("attempts = attempts + 1").
+		Set("updated_at = now()").
+		Where("id = ?", id).
+		Where("claimed_until IS NULL OR claimed_until < now()").
+		Where("status NOT IN (?)", bun.In([]string{domain.JobStatusReady, domain.JobStatusCancelled})).
+		Exec(ctx)
+	if err != nil {
+		return false, common.CheckDBErrorType(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// RenewJob implements repository.JobRepository.
+// Extiende el lease solo si sigue siendo de owner, false si otra instancia lo tomó.
+func (j *jobRepository) RenewJob(ctx context.Context, id uuid.UUID, owner uuid.UUID, lease time.Duration) (bool, error) {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := db.NewUpdate().Model((*domain.TableJob)(nil)).
+		Set("claimed_until = ?", time.Now().Add(lease)).
+		Where("id = ?", id).
+		Where("claimed_by = ?", owner).
+		Where("claimed_until >= now()").
+		Exec(ctx)
+	if err != nil {
+		return false, common.CheckDBErrorType(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// ReleaseJob implements repository.JobRepository.
+func (j *jobRepository) ReleaseJob(ctx context.Context, id uuid.UUID, owner uuid.UUID) error {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.NewUpdate().Model((*domain.TableJob)(nil)).
+		Set("claimed_until = NULL").
+		Set("claimed_by = NULL").
+		Where("id = ?", id).
+		Where("claimed_by = ?", owner).
+		Exec(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// GetResumableJobs implements repository.JobRepository.
+func (j *jobRepository) GetResumableJobs(ctx context.Context) ([]domain.TableJob, error) {
+	db, err := j.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []domain.TableJob
+	err = db.NewSelect().Model(&jobs).
+		Where("status NOT IN (?)", bun.In([]string{domain.JobStatusReady, domain.JobStatusCancelled, domain.JobStatusFailed})).
+		Where("claimed_until IS NULL OR claimed_until < now()").
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return jobs, nil
+}
+
+func NewJobRepository(log common.Logger, tenant *common.TenantConnectionManager) repository.JobRepository {
+	return &jobRepository{
+		log:    log,
+		tenant: tenant,
+	}
+}
+
+var _ repository.JobRepository = (*jobRepository)(nil)
@@ -0,0 +1,145 @@
+package implements
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type planRepository struct {
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+}
+
+// GetPlans implements repository.PlanRepository.
+func (p *planRepository) GetPlans(ctx context.Context) ([]domain.TablePlan, error) {
+	db, err := p.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []domain.TablePlan
+	err = db.NewSelect().Model(&plans).Order("id").Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return plans, nil
+}
+
+// GetPlanByID implements repository.PlanRepository.
+func (p *planRepository) GetPlanByID(ctx context.Context, id string) (*domain.TablePlan, error) {
+	db, err := p.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan domain.TablePlan
+	err = db.NewSelect().Model(&plan).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return &plan, nil
+}
+
+// ConsumeUsage implements repository.PlanRepository.
+// La condición del límite va en el upsert para que las peticiones
+// concurrentes no lo superen.
+func (p *planRepository) ConsumeUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, n, limit int64) (bool, error) {
+	if limit > 0 && n > limit {
+		return false, nil
+	}
+	db, err := p.tenant.GetKosviTenantDB()
+	if err != nil {
+		return false, err
+	}
+
+	usage := domain.TableUsage{TenantID: tenantID, Resource: resource, Period: period, Used: n}
+	q := db.NewInsert().Model(&usage).
+		On("CONFLICT (tenant_id, resource, period) DO UPDATE").
+		Set("used = usage.used + EXCLUDED.used").
+		Set("updated_at = now()")
+	if limit > 0 {
+		q = q.Where("usage.used + EXCLUDED.used <= ?", limit)
+	}
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return false, common.CheckDBErrorType(err)
+	}
+	// Sin filas la condición del límite descartó la actualización
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// ReleaseUsage implements repository.PlanRepository.
+func (p *planRepository) ReleaseUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, n int64) error {
+	db, err := p.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.NewUpdate().Model((*domain.TableUsage)(nil)).
+		Set("used = greatest(used - ?, 0)", n).
+		Set("updated_at = now()").
+		Where("tenant_id = ?", tenantID).
+		Where("resource = ?", resource).
+		Where("period = ?", period).
+		Exec(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// SetUsage implements repository.PlanRepository.
+func (p *planRepository) SetUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, used int64) error {
+	db, err := p.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	usage := domain.TableUsage{TenantID: tenantID, Resource: resource, Period: period, Used: used}
+	_, err = db.NewInsert().Model(&usage).
+		On("CONFLICT (tenant_id, resource, period) DO UPDATE").
+		Set("used = EXCLUDED.used").
+		Set("updated_at = now()").
+		Exec(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// GetUsage implements repository.PlanRepository.
+func (p *planRepository) GetUsage(ctx context.Context, tenantID uuid.UUID, periods ...string) ([]domain.TableUsage, error) {
+	db, err := p.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []domain.TableUsage
+	q := db.NewSelect().Model(&usage).Where("tenant_id = ?", tenantID).Order("resource", "period")
+	if len(periods) > 0 {
+		q = q.Where("period IN (?)", bun.In(periods))
+	}
+	err = q.Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return usage, nil
+}
+
+func NewPlanRepository(log common.Logger, tenant *common.TenantConnectionManager) repository.PlanRepository {
+	return &planRepository{
+		log:    log,
+		tenant: tenant,
+	}
+}
+
+var _ repository.PlanRepository = (*planRepository)(nil)
@@ -0,0 +1,75 @@
+package implements
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type settingsRepository struct {
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+}
+
+// GetSettings implements repository.SettingsRepository.
+func (s *settingsRepository) GetSettings(ctx context.Context, tenantID uuid.UUID) ([]domain.TableSetting, error) {
+	db, err := s.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []domain.TableSetting
+	err = db.NewSelect().Model(&settings).Where("tenant_id = ?", tenantID).Order("key").Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings implements repository.SettingsRepository.
+func (s *settingsRepository) UpdateSettings(ctx context.Context, tenantID uuid.UUID, settings []domain.TableSetting, remove []string) error {
+	db, err := s.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if len(settings) > 0 {
+			_, err := tx.NewInsert().Model(&settings).
+				On("CONFLICT (tenant_id, key) DO UPDATE").
+				Set("value = EXCLUDED.value").
+				Set("updated_at = now()").
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		if len(remove) > 0 {
+			_, err := tx.NewDelete().Model((*domain.TableSetting)(nil)).
+				Where("tenant_id = ?", tenantID).
+				Where("key IN (?)", bun.In(remove)).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+func NewSettingsRepository(log common.Logger, tenant *common.TenantConnectionManager) repository.SettingsRepository {
+	return &settingsRepository{
+		log:    log,
+		tenant: tenant,
+	}
+}
+
+var _ repository.SettingsRepository = (*settingsRepository)(nil)
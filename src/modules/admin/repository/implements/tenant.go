@@ -5,6 +5,9 @@ import (
 	"api-test/src/modules/admin/domain"
 	"api-test/src/modules/admin/repository"
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -15,51 +18,145 @@ type tenantRepository struct {
 	tenant *common.TenantConnectionManager
 }
 
-// Manual rollback
-func (t *tenantRepository) rollback(ctx context.Context, db *bun.DB, tenant domain.TableTenant) {
-	// Delete the database
-	dbQuery := "DROP DATABASE IF EXISTS ?"
-	_, _ = db.ExecContext(ctx, dbQuery, bun.Safe(tenant.DBName))
-	// Delete the user
-	userQuery := "DROP USER IF EXISTS ?"
-	_, _ = db.ExecContext(ctx, userQuery, bun.Safe(tenant.DBUser))
-}
-
-// CreateTenant implements repository.TenantRepository.
-func (t *tenantRepository) CreateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error) {
+// CreateTenantDatabase implements repository.TenantRepository.
+// Es idempotente para poder reintentar un aprovisionamiento fallido.
+func (t *tenantRepository) CreateTenantDatabase(ctx context.Context, tenant domain.TableTenant) error {
 	db, err := t.tenant.GetKosviTenantDB()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// 1. Crear el nuevo usuario
-	userQuery := "CREATE USER ? WITH PASSWORD ?"
-	_, err = db.ExecContext(ctx, userQuery, bun.Safe(tenant.DBUser), tenant.PasswordPlaintext)
-	if err != nil {
-		t.rollback(ctx, db, tenant)
-		return nil, common.CheckDBErrorType(err)
+	// 1. Crear el nuevo usuario o actualizar su contraseña si ya existe
+	if err := t.createUser(ctx, db, tenant); err != nil {
+		return err
+	}
+
+	switch tenant.Isolation {
+	case domain.TenantIsolationSchema:
+		return t.createTenantSchema(ctx, db, tenant)
+	case domain.TenantIsolationShared:
+		return t.createPooledTenant(ctx, db, tenant)
 	}
 
 	// 2. Crear la base de datos (fuera de la transacción)
-	dbQuery := "CREATE DATABASE ? WITH OWNER ?"
-	_, err = db.ExecContext(ctx, dbQuery, bun.Safe(tenant.DBName), bun.Safe(tenant.DBUser))
+	dbExists, err := db.NewSelect().Table("pg_database").Where("datname = ?", tenant.DBName).Exists(ctx)
 	if err != nil {
-		t.rollback(ctx, db, tenant)
-		return nil, common.CheckDBErrorType(err)
+		return common.CheckDBErrorType(err)
+	}
+	if !dbExists {
+		dbQuery := "CREATE DATABASE ? WITH OWNER ?"
+		_, err = db.ExecContext(ctx, dbQuery, bun.Ident(tenant.DBName), bun.Ident(tenant.DBUser))
+		if err != nil {
+			return common.CheckDBErrorType(err)
+		}
 	}
 
 	// 3. Otorgar privilegios
 	privilegeQuery := "GRANT ALL PRIVILEGES ON DATABASE ? TO ?"
-	_, err = db.ExecContext(ctx, privilegeQuery, bun.Safe(tenant.DBName), bun.Safe(tenant.DBUser))
+	_, err = db.ExecContext(ctx, privilegeQuery, bun.Ident(tenant.DBName), bun.Ident(tenant.DBUser))
 	if err != nil {
-		t.rollback(ctx, db, tenant)
-		return nil, common.CheckDBErrorType(err)
+		return common.CheckDBErrorType(err)
+	}
+
+	return nil
+}
+
+// CloneTenantDatabase implements repository.TenantRepository.
+// Crea la base de datos del tenant como copia de la del origen con CREATE
+// DATABASE ... TEMPLATE, solo para tenants con base de datos propia en el
+// mismo servidor. Falla si el origen tiene conexiones abiertas.
+func (t *tenantRepository) CloneTenantDatabase(ctx context.Context, tenant domain.TableTenant, source domain.TableTenant) error {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+	if err := t.createUser(ctx, db, tenant); err != nil {
+		return err
+	}
+
+	dbExists, err := db.NewSelect().Table("pg_database").Where("datname = ?", tenant.DBName).Exists(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	if !dbExists {
+		dbQuery := "CREATE DATABASE ? WITH TEMPLATE ? OWNER ?"
+		_, err = db.ExecContext(ctx, dbQuery, bun.Ident(tenant.DBName), bun.Ident(source.DBName), bun.Ident(tenant.DBUser))
+		if err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "GRANT ALL PRIVILEGES ON DATABASE ? TO ?", bun.Ident(tenant.DBName), bun.Ident(tenant.DBUser)); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	// Los objetos copiados siguen siendo del usuario del origen. REASSIGN OWNED
+	// no sirve porque también cambiaría el dueño de la base de datos del origen.
+	clone, err := t.tenant.ConnectAdmin(tenant.DBName)
+	if err != nil {
+		return err
+	}
+	defer clone.Close()
+
+	var relations, routines []string
+	// Las secuencias de columnas serial o identity cambian junto con su tabla y
+	// los objetos de extensiones se mantienen
+	err = clone.NewSelect().TableExpr("pg_class AS c").
+		ColumnExpr("c.oid::regclass::text").
+		Where("c.relowner = (SELECT oid FROM pg_roles WHERE rolname = ?)", source.DBUser).
+		Where("c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S')").
+		Where("NOT EXISTS (SELECT 1 FROM pg_depend AS d WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype IN ('a', 'i', 'e'))").
+		Scan(ctx, &relations)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	err = clone.NewSelect().TableExpr("pg_proc AS p").
+		ColumnExpr("p.oid::regprocedure::text").
+		Where("p.proowner = (SELECT oid FROM pg_roles WHERE rolname = ?)", source.DBUser).
+		Where("NOT EXISTS (SELECT 1 FROM pg_depend AS d WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e')").
+		Scan(ctx, &routines)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	// Los nombres ya vienen con comillas desde regclass y regprocedure
+	for _, relation := range relations {
+		if _, err := clone.ExecContext(ctx, "ALTER TABLE ? OWNER TO ?", bun.Safe(relation), bun.Ident(tenant.DBUser)); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
+	for _, routine := range routines {
+		if _, err := clone.ExecContext(ctx, "ALTER ROUTINE ? OWNER TO ?", bun.Safe(routine), bun.Ident(tenant.DBUser)); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
+	return nil
+}
+
+// Crea el usuario del tenant o actualiza su contraseña si ya existe
+func (t *tenantRepository) createUser(ctx context.Context, db *bun.DB, tenant domain.TableTenant) error {
+	roleExists, err := db.NewSelect().Table("pg_roles").Where("rolname = ?", tenant.DBUser).Exists(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	userQuery := "CREATE USER ? WITH PASSWORD ?"
+	if roleExists {
+		userQuery = "ALTER USER ? WITH PASSWORD ?"
+	}
+	_, err = db.ExecContext(ctx, userQuery, bun.Ident(tenant.DBUser), tenant.PasswordPlaintext)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// CreateTenant implements repository.TenantRepository.
+func (t *tenantRepository) CreateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error) {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
 	}
 
-	// 4. Insertar el nuevo tenant en la base de datos
 	_, err = db.NewInsert().Model(&tenant).Exec(ctx)
 	if err != nil {
-		t.rollback(ctx, db, tenant)
 		return nil, common.CheckDBErrorType(err)
 	}
 
@@ -73,18 +170,314 @@ func (t *tenantRepository) DeleteTenant(ctx context.Context, id uuid.UUID) error
 		return err
 	}
 
-	var model domain.TableTenant
-	_, err = db.NewDelete().Model(&model).Where("id = ?", id).Exec(ctx)
+	// user_tenants y registration se eliminan en cascada
+	_, err = db.NewDelete().Model((*domain.TableTenant)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	return nil
+}
+
+// DropTenantDatabase implements repository.TenantRepository.
+func (t *tenantRepository) DropTenantDatabase(ctx context.Context, tenant domain.TableTenant) error {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	switch tenant.Isolation {
+	case domain.TenantIsolationSchema:
+		return t.dropTenantSchema(ctx, db, tenant)
+	case domain.TenantIsolationShared:
+		return t.dropPooledTenant(ctx, db, tenant)
+	}
+
+	// 1. Cerrar las conexiones activas contra la base de datos del tenant
+	terminateQuery := "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = ? AND pid <> pg_backend_pid()"
+	_, err = db.ExecContext(ctx, terminateQuery, tenant.DBName)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	// 2. Eliminar la base de datos
+	dbQuery := "DROP DATABASE IF EXISTS ?"
+	_, err = db.ExecContext(ctx, dbQuery, bun.Ident(tenant.DBName))
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	// 3. Eliminar el usuario
+	userQuery := "DROP USER IF EXISTS ?"
+	_, err = db.ExecContext(ctx, userQuery, bun.Ident(tenant.DBUser))
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	return nil
+}
+
+// Crea el schema del tenant en la base de datos compartida con su usuario como
+// dueño. El resto de usuarios no tiene acceso a schemas ajenos y solo puede
+// usar public para las extensiones.
+func (t *tenantRepository) createTenantSchema(ctx context.Context, db *bun.DB, tenant domain.TableTenant) error {
+	if err := t.createSharedDatabase(ctx, db, tenant.DBName); err != nil {
+		return err
+	}
+
+	shared, err := t.tenant.GetSharedDB()
+	if err != nil {
+		return err
+	}
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`, nil},
+		{"REVOKE ALL ON DATABASE ? FROM PUBLIC", []interface{}{bun.Ident(tenant.DBName)}},
+		{"REVOKE CREATE ON SCHEMA public FROM PUBLIC", nil},
+		{"CREATE SCHEMA IF NOT EXISTS ? AUTHORIZATION ?", []interface{}{bun.Ident(tenant.DBSchema), bun.Ident(tenant.DBUser)}},
+		{"GRANT CONNECT ON DATABASE ? TO ?", []interface{}{bun.Ident(tenant.DBName), bun.Ident(tenant.DBUser)}},
+		{"GRANT USAGE ON SCHEMA public TO ?", []interface{}{bun.Ident(tenant.DBUser)}},
+	}
+	for _, statement := range statements {
+		if _, err := shared.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
+	return nil
+}
+
+// Rol sin login del que son miembros los usuarios de los tenants con tablas
+// compartidas, las migraciones de migrations/shared le otorgan los permisos
+const pooledRole = "kosvi_pooled"
+
+// Agrega el usuario del tenant al rol de las tablas compartidas. Las tablas
+// son del usuario admin, por lo que las políticas RLS aplican al tenant.
+func (t *tenantRepository) createPooledTenant(ctx context.Context, db *bun.DB, tenant domain.TableTenant) error {
+	if err := t.createSharedDatabase(ctx, db, tenant.DBName); err != nil {
+		return err
+	}
+	roleExists, err := db.NewSelect().Table("pg_roles").Where("rolname = ?", pooledRole).Exists(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	if !roleExists {
+		if _, err := db.ExecContext(ctx, "CREATE ROLE ? NOLOGIN", bun.Ident(pooledRole)); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "GRANT ? TO ?", bun.Ident(pooledRole), bun.Ident(tenant.DBUser)); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	shared, err := t.tenant.GetSharedDB()
+	if err != nil {
+		return err
+	}
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`, nil},
+		{"REVOKE ALL ON DATABASE ? FROM PUBLIC", []interface{}{bun.Ident(tenant.DBName)}},
+		{"REVOKE CREATE ON SCHEMA public FROM PUBLIC", nil},
+		{"CREATE SCHEMA IF NOT EXISTS ?", []interface{}{bun.Ident(tenant.DBSchema)}},
+		{"GRANT CONNECT ON DATABASE ? TO ?", []interface{}{bun.Ident(tenant.DBName), bun.Ident(pooledRole)}},
+		{"GRANT USAGE ON SCHEMA ?, public TO ?", []interface{}{bun.Ident(tenant.DBSchema), bun.Ident(pooledRole)}},
+	}
+	for _, statement := range statements {
+		if _, err := shared.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
+	return nil
+}
+
+// Elimina las filas del tenant de las tablas compartidas y su usuario
+func (t *tenantRepository) dropPooledTenant(ctx context.Context, db *bun.DB, tenant domain.TableTenant) error {
+	terminateQuery := "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = ? AND pid <> pg_backend_pid()"
+	if _, err := db.ExecContext(ctx, terminateQuery, tenant.DBUser); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	dbExists, err := db.NewSelect().Table("pg_database").Where("datname = ?", tenant.DBName).Exists(ctx)
 	if err != nil {
 		return common.CheckDBErrorType(err)
 	}
+	if dbExists {
+		if err := t.deletePooledRows(ctx, tenant); err != nil {
+			return err
+		}
+	}
 
-	// Delete the database
-	t.rollback(ctx, db, model)
+	if _, err := db.ExecContext(ctx, "DROP USER IF EXISTS ?", bun.Ident(tenant.DBUser)); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
 
+// Borra las filas del tenant de las tablas con columna tenant_id, primero las
+// tablas que referencian a otras para respetar las llaves foráneas
+func (t *tenantRepository) deletePooledRows(ctx context.Context, tenant domain.TableTenant) error {
+	pooled, err := t.tenant.GetPooledDB()
+	if err != nil {
+		return err
+	}
+
+	type reference struct {
+		Table      string `bun:"table_name"`
+		Referenced string `bun:"referenced_table"`
+	}
+	err = pooled.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// FORCE ROW LEVEL SECURITY aplica también al dueño de las tablas
+		if err := common.SetTenant(ctx, tx, tenant.ID); err != nil {
+			return err
+		}
+		var tables []string
+		err := tx.NewSelect().Table("information_schema.columns").
+			Column("table_name").
+			Where("table_schema = ?", tenant.DBSchema).
+			Where("column_name = 'tenant_id'").
+			Scan(ctx, &tables)
+		if err != nil {
+			return err
+		}
+		var references []reference
+		err = tx.NewSelect().TableExpr("pg_constraint AS c").
+			ColumnExpr("c.conrelid::regclass::text AS table_name").
+			ColumnExpr("c.confrelid::regclass::text AS referenced_table").
+			Where("c.contype = 'f'").
+			Where("c.connamespace = ?::regnamespace", tenant.DBSchema).
+			Scan(ctx, &references)
+		if err != nil {
+			return err
+		}
+
+		// Una tabla se borra cuando ninguna tabla pendiente la referencia
+		pending := map[string]bool{}
+		for _, table := range tables {
+			pending[table] = true
+		}
+		for len(pending) > 0 {
+			deleted := false
+			for _, table := range tables {
+				referenced := slices.ContainsFunc(references, func(r reference) bool {
+					return r.Referenced == table && r.Table != table && pending[r.Table]
+				})
+				if !pending[table] || referenced {
+					continue
+				}
+				if _, err := tx.NewDelete().Table(table).Where("tenant_id = ?", tenant.ID).Exec(ctx); err != nil {
+					return err
+				}
+				delete(pending, table)
+				deleted = true
+			}
+			if !deleted {
+				return fmt.Errorf("circular references between shared tables: %v", pending)
+			}
+		}
+		return nil
+	})
+	return common.CheckDBErrorType(err)
+}
+
+// Crea la base de datos compartida de los schemas y las tablas compartidas
+func (t *tenantRepository) createSharedDatabase(ctx context.Context, db *bun.DB, name string) error {
+	dbExists, err := db.NewSelect().Table("pg_database").Where("datname = ?", name).Exists(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	if !dbExists {
+		if _, err := db.ExecContext(ctx, "CREATE DATABASE ?", bun.Ident(name)); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+	}
 	return nil
 }
 
+// Elimina el schema del tenant, los privilegios de su usuario en la base de
+// datos compartida y el usuario
+func (t *tenantRepository) dropTenantSchema(ctx context.Context, db *bun.DB, tenant domain.TableTenant) error {
+	terminateQuery := "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = ? AND pid <> pg_backend_pid()"
+	if _, err := db.ExecContext(ctx, terminateQuery, tenant.DBUser); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+
+	dbExists, err := db.NewSelect().Table("pg_database").Where("datname = ?", tenant.DBName).Exists(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	roleExists, err := db.NewSelect().Table("pg_roles").Where("rolname = ?", tenant.DBUser).Exists(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	if dbExists {
+		shared, err := t.tenant.GetSharedDB()
+		if err != nil {
+			return err
+		}
+		if _, err := shared.ExecContext(ctx, "DROP SCHEMA IF EXISTS ? CASCADE", bun.Ident(tenant.DBSchema)); err != nil {
+			return common.CheckDBErrorType(err)
+		}
+		if roleExists {
+			if _, err := shared.ExecContext(ctx, "DROP OWNED BY ?", bun.Ident(tenant.DBUser)); err != nil {
+				return common.CheckDBErrorType(err)
+			}
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, "DROP USER IF EXISTS ?", bun.Ident(tenant.DBUser)); err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// RotateTenantPassword implements repository.TenantRepository.
+// Cambia la contraseña del usuario y guarda la nueva cifrada en la misma
+// transacción. Retorna false sin cambios si la última rotación es posterior a
+// rotatedBefore, por ejemplo porque otra instancia la rotó primero.
+func (t *tenantRepository) RotateTenantPassword(ctx context.Context, tenant domain.TableTenant, rotatedBefore time.Time) (bool, error) {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return false, err
+	}
+
+	rotated := false
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var current domain.TableTenant
+		err := tx.NewSelect().Model(&current).
+			Column("id", "credentials_rotated_at").
+			Where("id = ?", tenant.ID).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		if !current.CredentialsRotatedAt.IsZero() && !current.CredentialsRotatedAt.Before(rotatedBefore) {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, "ALTER ROLE ? WITH PASSWORD ?", bun.Ident(tenant.DBUser), tenant.PasswordPlaintext); err != nil {
+			return err
+		}
+		_, err = tx.NewUpdate().Model(&tenant).
+			Column("db_password", "iv", "data_key", "version", "credentials_rotated_at", "updated_at").
+			Where("id = ?", tenant.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, common.CheckDBErrorType(err)
+	}
+	return rotated, nil
+}
+
 // GetTenantByID implements repository.TenantRepository.
 func (t *tenantRepository) GetTenantByID(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error) {
 	db, err := t.tenant.GetKosviTenantDB()
@@ -93,7 +486,7 @@ func (t *tenantRepository) GetTenantByID(ctx context.Context, id uuid.UUID) (*do
 	}
 
 	var tenant domain.TableTenant
-	err = db.NewSelect().Model(&tenant).Where("tenant_id = ?", id).Scan(ctx)
+	err = db.NewSelect().Model(&tenant).Where("id = ?", id).Scan(ctx)
 	if err != nil {
 		return nil, common.CheckDBErrorType(err)
 	}
@@ -115,14 +508,33 @@ func (t *tenantRepository) GetTenantByName(ctx context.Context, name string) (*d
 	return &tenant, nil
 }
 
+// GetTenantBySlug implements repository.TenantRepository.
+func (t *tenantRepository) GetTenantBySlug(ctx context.Context, slug string) (*domain.TableTenant, error) {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var tenant domain.TableTenant
+	err = db.NewSelect().Model(&tenant).Where("slug = ?", slug).Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return &tenant, nil
+}
+
 // UpdateTenant implements repository.TenantRepository.
-func (t *tenantRepository) UpdateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error) {
+func (t *tenantRepository) UpdateTenant(ctx context.Context, tenant domain.TableTenant, columns ...string) (*domain.TableTenant, error) {
 	db, err := t.tenant.GetKosviTenantDB()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = db.NewUpdate().Model(&tenant).Where("id = ?", tenant.ID).Exec(ctx)
+	q := db.NewUpdate().Model(&tenant).Where("id = ?", tenant.ID)
+	if len(columns) > 0 {
+		q = q.Column(columns...)
+	}
+	_, err = q.Exec(ctx)
 	if err != nil {
 		return nil, common.CheckDBErrorType(err)
 	}
@@ -144,6 +556,21 @@ func (t *tenantRepository) GetAllTenants(ctx context.Context) ([]domain.TableTen
 	return tenants, nil
 }
 
+// GetTenantsPendingDeletion implements repository.TenantRepository.
+func (t *tenantRepository) GetTenantsPendingDeletion(ctx context.Context, before time.Time) ([]domain.TableTenant, error) {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var tenants []domain.TableTenant
+	err = db.NewSelect().Model(&tenants).Where("delete_after IS NOT NULL AND delete_after <= ?", before).Scan(ctx)
+	if err != nil {
+		return nil, common.CheckDBErrorType(err)
+	}
+	return tenants, nil
+}
+
 // CreateUserTenant implements repository.TenantRepository.
 func (t *tenantRepository) CreateUserTenant(ctx context.Context, userTenant domain.TableUserTenant) (*domain.TableUserTenant, error) {
 	db, err := t.tenant.GetKosviTenantDB()
@@ -158,6 +585,34 @@ func (t *tenantRepository) CreateUserTenant(ctx context.Context, userTenant doma
 	return &userTenant, nil
 }
 
+// DeleteUserTenants implements repository.TenantRepository.
+func (t *tenantRepository) DeleteUserTenants(ctx context.Context, tenantID uuid.UUID) error {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.NewDelete().Model((*domain.TableUserTenant)(nil)).Where("tenant_id = ?", tenantID).Exec(ctx)
+	if err != nil {
+		return common.CheckDBErrorType(err)
+	}
+	return nil
+}
+
+// CountUserTenants implements repository.TenantRepository.
+func (t *tenantRepository) CountUserTenants(ctx context.Context, tenantID uuid.UUID) (int64, error) {
+	db, err := t.tenant.GetKosviTenantDB()
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := db.NewSelect().Model((*domain.TableUserTenant)(nil)).Where("tenant_id = ?", tenantID).Count(ctx)
+	if err != nil {
+		return 0, common.CheckDBErrorType(err)
+	}
+	return int64(count), nil
+}
+
 // GetTenantsByUser implements repository.TenantRepository.
 func (t *tenantRepository) GetTenantsByUser(ctx context.Context, userID uuid.UUID) ([]domain.TableUserTenant, error) {
 	db, err := t.tenant.GetKosviTenantDB()
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobRepository interface {
+	CreateJob(ctx context.Context, job domain.TableJob) (*domain.TableJob, error)
+	GetJobByID(ctx context.Context, id uuid.UUID) (*domain.TableJob, error)
+	UpdateJob(ctx context.Context, job domain.TableJob, columns ...string) (*domain.TableJob, error)
+	ClaimJob(ctx context.Context, id uuid.UUID, owner uuid.UUID, lease time.Duration) (bool, error)
+	RenewJob(ctx context.Context, id uuid.UUID, owner uuid.UUID, lease time.Duration) (bool, error)
+	ReleaseJob(ctx context.Context, id uuid.UUID, owner uuid.UUID) error
+	GetResumableJobs(ctx context.Context) ([]domain.TableJob, error)
+}
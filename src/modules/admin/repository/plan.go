@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type PlanRepository interface {
+	GetPlans(ctx context.Context) ([]domain.TablePlan, error)
+	GetPlanByID(ctx context.Context, id string) (*domain.TablePlan, error)
+	// ConsumeUsage suma n al uso del recurso si no supera limit (0 sin
+	// límite). Retorna false sin modificar el uso si lo supera.
+	ConsumeUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, n, limit int64) (bool, error)
+	ReleaseUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, n int64) error
+	SetUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, used int64) error
+	GetUsage(ctx context.Context, tenantID uuid.UUID, periods ...string) ([]domain.TableUsage, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SettingsRepository interface {
+	GetSettings(ctx context.Context, tenantID uuid.UUID) ([]domain.TableSetting, error)
+	// UpdateSettings guarda los valores y elimina las claves de remove en la misma transacción
+	UpdateSettings(ctx context.Context, tenantID uuid.UUID, settings []domain.TableSetting, remove []string) error
+}
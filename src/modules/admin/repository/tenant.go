@@ -3,6 +3,7 @@ package repository
 import (
 	"api-test/src/modules/admin/domain"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,11 +11,19 @@ import (
 type TenantRepository interface {
 	GetTenantByID(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error)
 	GetTenantByName(ctx context.Context, name string) (*domain.TableTenant, error)
+	GetTenantBySlug(ctx context.Context, slug string) (*domain.TableTenant, error)
 	CreateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error)
-	UpdateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error)
+	CreateTenantDatabase(ctx context.Context, tenant domain.TableTenant) error
+	CloneTenantDatabase(ctx context.Context, tenant domain.TableTenant, source domain.TableTenant) error
+	UpdateTenant(ctx context.Context, tenant domain.TableTenant, columns ...string) (*domain.TableTenant, error)
 	DeleteTenant(ctx context.Context, id uuid.UUID) error
+	DropTenantDatabase(ctx context.Context, tenant domain.TableTenant) error
+	RotateTenantPassword(ctx context.Context, tenant domain.TableTenant, rotatedBefore time.Time) (bool, error)
 	GetAllTenants(ctx context.Context) ([]domain.TableTenant, error)
+	GetTenantsPendingDeletion(ctx context.Context, before time.Time) ([]domain.TableTenant, error)
 	CreateUserTenant(ctx context.Context, userTenant domain.TableUserTenant) (*domain.TableUserTenant, error)
+	DeleteUserTenants(ctx context.Context, tenantID uuid.UUID) error
+	CountUserTenants(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	GetTenantsByUser(ctx context.Context, userID uuid.UUID) ([]domain.TableUserTenant, error)
 }
 
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Caché por tenant de los datos que se consultan en cada petición, los
+// eventos de tenants invalidan las entradas modificadas por otra instancia
+type tenantCache[V any] struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]cacheEntry[V]
+}
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newTenantCache[V any]() *tenantCache[V] {
+	return &tenantCache[V]{entries: map[uuid.UUID]cacheEntry[V]{}}
+}
+
+func (c *tenantCache[V]) get(tenantID uuid.UUID) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[tenantID]
+	if !ok || time.Now().After(entry.expires) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *tenantCache[V]) set(tenantID uuid.UUID, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[tenantID] = cacheEntry[V]{value: value, expires: time.Now().Add(ttl)}
+}
+
+func (c *tenantCache[V]) invalidate(tenantID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, tenantID)
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CloneTenant implements Tenant.
+// Crea un tenant nuevo con los datos del origen y credenciales propias. Sin
+// anonimizar, el clon usa la clave de columnas cifradas del origen para poder
+// leer sus datos personales.
+func (t *tenant) CloneTenant(ctx context.Context, id uuid.UUID, dto domain.DTOCloneTenant) (*domain.DTOJob, error) {
+	userID, ok := ctx.Value(t.tenantManager.UserIDKey).(uuid.UUID)
+	if !ok {
+		return nil, common.UnauthorizedError("user not found in context")
+	}
+	source, err := t.getOwnedTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source.Status != domain.TenantStatusReady {
+		return nil, common.ConflictError("tenant is not ready")
+	}
+	isolation := dto.Isolation
+	if isolation == "" {
+		isolation = source.Isolation
+	}
+	// Los datos se importan con sus ids, que pueden chocar con los de otros tenants
+	if isolation == domain.TenantIsolationShared {
+		return nil, common.ConflictError("tenants with shared tables cannot be cloned, choose another isolation")
+	}
+
+	fieldKey := source
+	if dto.Anonymize {
+		fieldKey = nil
+	}
+	newTenant, err := t.createTenantRecord(ctx, userID, domain.DTOTenant{Name: dto.Name, Slug: dto.Slug, Isolation: isolation}, fieldKey)
+	if err != nil {
+		return nil, err
+	}
+	return t.startJob(ctx, uuid.New(), domain.JobKindClone, newTenant.ID, domain.ClonePayload{SourceID: source.ID, Anonymize: dto.Anonymize})
+}
+
+// Tenant plantilla de TENANT_TEMPLATE_ID para los tenants nuevos, nil si no
+// está configurado o no se puede usar
+func (t *tenant) signupTemplate(ctx context.Context, isolation string) *domain.TableTenant {
+	templateID := t.config.TenantLifecycle.TemplateTenantID
+	if templateID == uuid.Nil || isolation == domain.TenantIsolationShared {
+		return nil
+	}
+	template, err := t.repo.GetTenantByID(ctx, templateID)
+	if err != nil || template == nil || template.ID == uuid.Nil || template.Status != domain.TenantStatusReady {
+		t.log.Warn(ctx, "Template tenant not available, provisioning from migrations", "template_id", templateID, "error", err)
+		return nil
+	}
+	return template
+}
+
+// Sigue los pasos del aprovisionamiento, pero los datos vienen del origen. Con
+// CREATE DATABASE ... TEMPLATE la base de datos ya tiene las migraciones y los
+// datos, si no es posible se migra y se copian los datos con una exportación.
+func (t *tenant) cloneStep(ctx context.Context, job *domain.TableJob) (string, error) {
+	var payload domain.ClonePayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", err
+	}
+	table, err := t.repo.GetTenantByID(ctx, job.TenantID)
+	if err != nil {
+		return "", err
+	}
+	if table == nil || table.ID == uuid.Nil {
+		return "", fmt.Errorf("tenant %s not found", job.TenantID)
+	}
+	source, err := t.repo.GetTenantByID(ctx, payload.SourceID)
+	if err != nil {
+		return "", err
+	}
+	if source == nil || source.ID == uuid.Nil {
+		return "", fmt.Errorf("source tenant %s not found", payload.SourceID)
+	}
+
+	switch job.Step {
+	case domain.JobStatusPending:
+		pwd, err := t.password(ctx, *table)
+		if err != nil {
+			return "", err
+		}
+		table.PasswordPlaintext = pwd
+		if t.canUseTemplate(*table, *source) {
+			err := t.repo.CloneTenantDatabase(ctx, *table, *source)
+			if err == nil {
+				payload.Template = true
+				if err := t.setJobPayload(ctx, job, payload); err != nil {
+					return "", err
+				}
+				return domain.JobStatusDatabaseCreated, nil
+			}
+			// Falla si el origen tiene conexiones abiertas
+			t.log.Warn(ctx, "Template clone failed, using logical copy", "job_id", job.ID, "source_id", source.ID, "error", err)
+			if err := t.repo.DropTenantDatabase(ctx, *table); err != nil {
+				return "", err
+			}
+		}
+		if err := t.repo.CreateTenantDatabase(ctx, *table); err != nil {
+			return "", err
+		}
+		return domain.JobStatusDatabaseCreated, nil
+
+	case domain.JobStatusDatabaseCreated:
+		if err := t.register(ctx, *table, true); err != nil {
+			return "", err
+		}
+		if !payload.Template {
+			if err := t.migrations.RunAllMigrations(ctx, table.ID); err != nil {
+				return "", err
+			}
+		}
+		return domain.JobStatusMigrated, nil
+
+	case domain.JobStatusMigrated:
+		if !payload.Template {
+			if err := t.copyTenantData(ctx, job, *source); err != nil {
+				return "", err
+			}
+		}
+		if payload.Anonymize {
+			rows, err := t.data.Anonymize(ctx, table.ID, t.config.TenantLifecycle.PIIColumns)
+			if err != nil {
+				return "", err
+			}
+			t.log.Info(ctx, "Clone anonymized", "job_id", job.ID, "tenant_id", table.ID, "rows", rows)
+		}
+		return domain.JobStatusSeeded, nil
+
+	case domain.JobStatusSeeded:
+		return t.activate(ctx, *table)
+	}
+
+	return "", fmt.Errorf("unknown clone step: %s", job.Step)
+}
+
+// CREATE DATABASE ... TEMPLATE solo copia bases de datos completas del mismo servidor
+func (t *tenant) canUseTemplate(table domain.TableTenant, source domain.TableTenant) bool {
+	return table.Isolation == domain.TenantIsolationDatabase &&
+		source.Isolation == domain.TenantIsolationDatabase &&
+		table.DBHost == source.DBHost && table.DBPort == source.DBPort
+}
+
+// Copia lógica: exporta el origen al archivo del job, lleva las migraciones
+// del clon a la versión del origen e importa los datos
+func (t *tenant) copyTenantData(ctx context.Context, job *domain.TableJob, source domain.TableTenant) error {
+	archivePath := t.archivePath(job.ID)
+	defer t.removeArchive(ctx, job.ID)
+
+	manifest, _, err := t.writeArchive(ctx, source, archivePath)
+	if err != nil {
+		return err
+	}
+	if err := t.migrateTo(ctx, job.TenantID, manifest.MigrationVersion, fmt.Sprintf("clone from tenant %s", source.ID)); err != nil {
+		return err
+	}
+	rows, err := t.importArchive(ctx, job.TenantID, archivePath, manifest)
+	if err != nil {
+		return err
+	}
+	t.log.Info(ctx, "Tenant data copied", "job_id", job.ID, "source_id", source.ID, "tenant_id", job.TenantID, "rows", rows)
+	return nil
+}
+
+func (t *tenant) setJobPayload(ctx context.Context, job *domain.TableJob, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	job.Payload = raw
+	_, err = t.jobs.UpdateJob(ctx, *job, "payload")
+	return err
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_tenant_canUseTemplate(t *testing.T) {
+	uc, _, _ := newTestTenant()
+	source := domain.TableTenant{Isolation: domain.TenantIsolationDatabase, DBHost: "db-1", DBPort: 5432}
+
+	tests := []struct {
+		name  string
+		table domain.TableTenant
+		want  bool
+	}{
+		{"same server", domain.TableTenant{Isolation: domain.TenantIsolationDatabase, DBHost: "db-1", DBPort: 5432}, true},
+		{"other host", domain.TableTenant{Isolation: domain.TenantIsolationDatabase, DBHost: "db-2", DBPort: 5432}, false},
+		{"other port", domain.TableTenant{Isolation: domain.TenantIsolationDatabase, DBHost: "db-1", DBPort: 5433}, false},
+		{"schema clone", domain.TableTenant{Isolation: domain.TenantIsolationSchema, DBHost: "db-1", DBPort: 5432}, false},
+	}
+	for _, tt := range tests {
+		if got := uc.canUseTemplate(tt.table, source); got != tt.want {
+			t.Errorf("%s: canUseTemplate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Un origen en un schema no es una base de datos completa
+	schemaSource := source
+	schemaSource.Isolation = domain.TenantIsolationSchema
+	if uc.canUseTemplate(tests[0].table, schemaSource) {
+		t.Error("schema source used as template")
+	}
+}
+
+func Test_tenant_CloneTenantRejects(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	ownerCtx, table := repo.addTenant(uuid.New())
+
+	other := context.WithValue(context.Background(), common.UserIDKey, uuid.New())
+	if _, err := uc.CloneTenant(other, table.ID, domain.DTOCloneTenant{Name: "Copia"}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Errorf("expected 403 cloning another user's tenant, got %v", err)
+	}
+	if _, err := uc.CloneTenant(ownerCtx, table.ID, domain.DTOCloneTenant{Name: "Copia", Isolation: domain.TenantIsolationShared}); common.StatusCode(err, 0) != http.StatusConflict {
+		t.Errorf("expected 409 cloning into shared tables, got %v", err)
+	}
+
+	stored := repo.tenants[table.ID]
+	stored.Status = domain.TenantStatusProvisioning
+	repo.tenants[table.ID] = stored
+	if _, err := uc.CloneTenant(ownerCtx, table.ID, domain.DTOCloneTenant{Name: "Copia"}); common.StatusCode(err, 0) != http.StatusConflict {
+		t.Errorf("expected 409 cloning a tenant that is not ready, got %v", err)
+	}
+}
+
+func Test_tenant_signupTemplate(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	_, template := repo.addTenant(uuid.New())
+
+	if uc.signupTemplate(context.Background(), domain.TenantIsolationDatabase) != nil {
+		t.Error("template used without TENANT_TEMPLATE_ID")
+	}
+	uc.config.TenantLifecycle.TemplateTenantID = template.ID
+	if got := uc.signupTemplate(context.Background(), domain.TenantIsolationDatabase); got == nil || got.ID != template.ID {
+		t.Errorf("expected the template tenant, got %+v", got)
+	}
+	if uc.signupTemplate(context.Background(), domain.TenantIsolationShared) != nil {
+		t.Error("template used for a shared tenant")
+	}
+
+	// Una plantilla que no está lista se ignora
+	stored := repo.tenants[template.ID]
+	stored.Status = domain.TenantStatusFailed
+	repo.tenants[template.ID] = stored
+	if uc.signupTemplate(context.Background(), domain.TenantIsolationDatabase) != nil {
+		t.Error("template used while not ready")
+	}
+	uc.config.TenantLifecycle.TemplateTenantID = uuid.New()
+	if uc.signupTemplate(context.Background(), domain.TenantIsolationDatabase) != nil {
+		t.Error("missing template used")
+	}
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RotateTenantCredentials implements Tenant.
+func (t *tenant) RotateTenantCredentials(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error) {
+	table, err := t.getOwnerTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if table.Status != domain.TenantStatusReady {
+		return nil, common.ConflictError("tenant is not ready")
+	}
+
+	if _, err := t.rotateCredentials(ctx, table, time.Now()); err != nil {
+		return nil, err
+	}
+	result := table.ToDTO()
+	return &result, nil
+}
+
+// RotateAllCredentials implements Tenant.
+// Rota las contraseñas con una antigüedad mayor a olderThan, todas si es 0.
+func (t *tenant) RotateAllCredentials(ctx context.Context, olderThan time.Duration) (*domain.DTOCredentialRotationReport, error) {
+	tenants, err := t.repo.GetAllTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &domain.DTOCredentialRotationReport{Total: len(tenants)}
+	for _, table := range tenants {
+		lastRotation := table.CredentialsRotatedAt
+		if lastRotation.IsZero() {
+			lastRotation = table.CreationDate
+		}
+		if table.Status != domain.TenantStatusReady || (olderThan > 0 && now.Sub(lastRotation) < olderThan) {
+			report.Skipped++
+			continue
+		}
+
+		rotated, err := t.rotateCredentials(ctx, &table, now.Add(-olderThan))
+		switch {
+		case err != nil:
+			t.log.Error(ctx, "Error rotating tenant credentials", "tenant_id", table.ID, "error", err)
+			report.Failed++
+			report.Failures = append(report.Failures, domain.DTOTenantFailure{TenantID: table.ID, Name: table.Name, Error: err.Error()})
+		case rotated:
+			report.Rotated++
+		default:
+			report.Skipped++
+		}
+	}
+	t.log.Info(ctx, "Credential rotation completed", "rotated", report.Rotated, "skipped", report.Skipped, "failed", report.Failed)
+	return report, nil
+}
+
+// SyncTenants implements Tenant.
+// Aplica los cambios hechos por otra instancia: registra los tenants nuevos,
+// quita los eliminados, reemplaza las conexiones con la contraseña rotada y
+// actualiza el estado y el tamaño de los pools. Es el respaldo de los eventos.
+func (t *tenant) SyncTenants(ctx context.Context) error {
+	tenants, err := t.repo.GetAllTenants(ctx)
+	if err != nil {
+		return err
+	}
+	stored := make(map[uuid.UUID]bool, len(tenants))
+	for _, table := range tenants {
+		stored[table.ID] = true
+		t.syncTenant(ctx, table)
+	}
+
+	for _, id := range t.tenantManager.TenantIDs() {
+		if stored[id] {
+			continue
+		}
+		// Un tenant recién creado en esta instancia puede no estar en la lista
+		if config, err := t.tenantManager.GetTenantConfig(id); err != nil || config.Provisioning {
+			continue
+		}
+		t.unregister(ctx, id)
+	}
+	return nil
+}
+
+// Genera una nueva contraseña, la aplica en el servidor junto con su versión
+// cifrada y reemplaza la conexión del tenant
+func (t *tenant) rotateCredentials(ctx context.Context, table *domain.TableTenant, rotatedBefore time.Time) (bool, error) {
+	password, err := t.crypto.GenerateRandomPassword()
+	if err != nil {
+		return false, err
+	}
+	sealed, err := t.crypto.Reseal(ctx, sealedPassword(*table), password)
+	if err != nil {
+		return false, err
+	}
+
+	// Postgres guarda microsegundos, así se compara igual al sincronizar
+	now := time.Now().Truncate(time.Microsecond)
+	rotation := *table
+	rotation.PasswordPlaintext = password
+	rotation.DBPassword = sealed.Ciphertext
+	rotation.IV = sealed.IV
+	rotation.DataKey = sealed.DataKey
+	rotation.Version = sealed.Version
+	rotation.CredentialsRotatedAt = now
+	rotation.UpdatedAt = now
+	rotated, err := t.repo.RotateTenantPassword(ctx, rotation, rotatedBefore)
+	if err != nil || !rotated {
+		return false, err
+	}
+	rotation.PasswordPlaintext = ""
+	*table = rotation
+	t.log.Info(ctx, "Tenant credentials rotated", "tenant_id", table.ID)
+	t.publish(ctx, table.ID, domain.TenantEventUpdated)
+
+	// Si falla, la contraseña ya cambió y la sincronización reintenta la conexión
+	if err := t.swap(ctx, rotation); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// Abre una conexión con las credenciales guardadas y reemplaza la registrada
+func (t *tenant) swap(ctx context.Context, table domain.TableTenant) error {
+	current, err := t.tenantManager.GetTenantConfig(table.ID)
+	if err != nil {
+		// No registrado en esta instancia, se conectará con las nuevas credenciales
+		return nil
+	}
+	config, err := t.tenantConfig(ctx, table, current.Provisioning)
+	if err != nil {
+		return err
+	}
+	return t.tenantManager.SwapTenant(config, t.psql.Connect)
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"api-test/src/modules/admin/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// Aplica la rotación en memoria como lo hace el repositorio: se omite si
+// otra instancia ya rotó la contraseña después de rotatedBefore
+type rotationRepository struct {
+	*memoryTenantRepository
+	failing uuid.UUID
+}
+
+func (r *rotationRepository) GetAllTenants(ctx context.Context) ([]domain.TableTenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tenants := make([]domain.TableTenant, 0, len(r.tenants))
+	for _, table := range r.tenants {
+		tenants = append(tenants, table)
+	}
+	return tenants, nil
+}
+
+func (r *rotationRepository) RotateTenantPassword(ctx context.Context, tenant domain.TableTenant, rotatedBefore time.Time) (bool, error) {
+	if tenant.ID == r.failing {
+		return false, errors.New("role does not exist")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.tenants[tenant.ID]
+	if !current.CredentialsRotatedAt.IsZero() && !current.CredentialsRotatedAt.Before(rotatedBefore) {
+		return false, nil
+	}
+	tenant.PasswordPlaintext = ""
+	r.tenants[tenant.ID] = tenant
+	return true, nil
+}
+
+// Registra los DSN con los que se abren los pools sin conectarse al servidor
+type recordingDatabase struct {
+	mu   sync.Mutex
+	dsns []string
+}
+
+func (d *recordingDatabase) Connect(tenantID uuid.UUID, dsn string) (*bun.DB, error) {
+	d.mu.Lock()
+	d.dsns = append(d.dsns, dsn)
+	d.mu.Unlock()
+	return bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New()), nil
+}
+
+func newTestRotation(t *testing.T) (*tenant, *rotationRepository, *recordingDatabase) {
+	t.Helper()
+	uc, memory, _ := newTestTenant()
+	repo := &rotationRepository{memoryTenantRepository: memory}
+	psql := &recordingDatabase{}
+	uc.repo = repo
+	uc.crypto = newTestEncryption(t)
+	uc.psql = psql
+	return uc, repo, psql
+}
+
+// Crea un tenant listo con la contraseña cifrada como al aprovisionarlo
+func addSealedTenant(t *testing.T, uc *tenant, repo *memoryTenantRepository, created time.Time) (context.Context, domain.TableTenant) {
+	t.Helper()
+	ctx, table := repo.addTenant(uuid.New())
+	sealed, err := uc.crypto.Seal(context.Background(), "initial-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.DBHost = "localhost"
+	table.DBPort = 5432
+	table.DBName = "tienda"
+	table.DBUser = "tienda_user"
+	table.DBPassword = sealed.Ciphertext
+	table.IV = sealed.IV
+	table.DataKey = sealed.DataKey
+	table.Version = sealed.Version
+	table.CreationDate = created
+	repo.tenants[table.ID] = table
+	return ctx, table
+}
+
+func Test_tenant_RotateTenantCredentials(t *testing.T) {
+	uc, repo, psql := newTestRotation(t)
+	ctx, table := addSealedTenant(t, uc, repo.memoryTenantRepository, time.Now())
+	registerTestTenant(t, uc, table)
+	// Pool abierto, la rotación debe reemplazarlo
+	before, err := uc.tenantManager.GetDB(table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Solo el dueño rota las credenciales
+	if _, err := uc.RotateTenantCredentials(repo.addMember(table.ID), table.ID); err == nil {
+		t.Fatal("member rotated the credentials")
+	}
+
+	result, err := uc.RotateTenantCredentials(ctx, table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ID != table.ID {
+		t.Fatalf("unexpected tenant: %+v", result)
+	}
+
+	stored := repo.tenants[table.ID]
+	if stored.CredentialsRotatedAt.IsZero() || stored.PasswordPlaintext != "" {
+		t.Fatalf("rotation not stored: %+v", stored)
+	}
+	password, err := uc.password(context.Background(), stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password == "initial-password" {
+		t.Fatal("password not rotated")
+	}
+
+	config, err := uc.tenantManager.GetTenantConfig(table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config.ConnectionString, password) || !config.CredentialsRotatedAt.Equal(stored.CredentialsRotatedAt) {
+		t.Fatalf("config not swapped: %+v", config)
+	}
+	if len(psql.dsns) != 1 || psql.dsns[0] != config.ConnectionString {
+		t.Fatalf("pool not reopened with the new password: %v", psql.dsns)
+	}
+	after, err := uc.tenantManager.GetDB(table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before {
+		t.Fatal("pool not swapped")
+	}
+}
+
+func Test_tenant_RotateTenantCredentialsNotReady(t *testing.T) {
+	uc, repo, _ := newTestRotation(t)
+	ctx, table := addSealedTenant(t, uc, repo.memoryTenantRepository, time.Now())
+	table.Status = domain.TenantStatusProvisioning
+	repo.tenants[table.ID] = table
+
+	if _, err := uc.RotateTenantCredentials(ctx, table.ID); err == nil {
+		t.Fatal("rotated a tenant that is not ready")
+	}
+	if !repo.tenants[table.ID].CredentialsRotatedAt.IsZero() {
+		t.Fatal("rotation stored")
+	}
+}
+
+func Test_tenant_RotateAllCredentials(t *testing.T) {
+	uc, repo, _ := newTestRotation(t)
+	old := time.Now().Add(-48 * time.Hour)
+
+	_, expired := addSealedTenant(t, uc, repo.memoryTenantRepository, old)
+	_, recent := addSealedTenant(t, uc, repo.memoryTenantRepository, time.Now())
+	// Rotado hace poco aunque se creó antes de la antigüedad máxima
+	_, rotated := addSealedTenant(t, uc, repo.memoryTenantRepository, old)
+	rotated.CredentialsRotatedAt = time.Now().Add(-time.Hour)
+	repo.tenants[rotated.ID] = rotated
+	_, provisioning := addSealedTenant(t, uc, repo.memoryTenantRepository, old)
+	provisioning.Status = domain.TenantStatusProvisioning
+	repo.tenants[provisioning.ID] = provisioning
+	_, failing := addSealedTenant(t, uc, repo.memoryTenantRepository, old)
+	repo.failing = failing.ID
+
+	report, err := uc.RotateAllCredentials(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 5 || report.Rotated != 1 || report.Skipped != 3 || report.Failed != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].TenantID != failing.ID || report.Failures[0].Error == "" {
+		t.Fatalf("unexpected failures: %+v", report.Failures)
+	}
+
+	if repo.tenants[expired.ID].CredentialsRotatedAt.IsZero() {
+		t.Fatal("expired credentials not rotated")
+	}
+	for _, id := range []uuid.UUID{recent.ID, provisioning.ID, failing.ID} {
+		if !repo.tenants[id].CredentialsRotatedAt.IsZero() {
+			t.Fatalf("tenant %s rotated", id)
+		}
+	}
+	if !repo.tenants[rotated.ID].CredentialsRotatedAt.Equal(rotated.CredentialsRotatedAt) {
+		t.Fatal("recently rotated credentials rotated again")
+	}
+}
+
+func Test_tenant_RotateAllCredentialsWithoutMaxAge(t *testing.T) {
+	uc, repo, _ := newTestRotation(t)
+	for range 3 {
+		addSealedTenant(t, uc, repo.memoryTenantRepository, time.Now())
+	}
+
+	// Sin antigüedad se rotan todos los tenants listos
+	report, err := uc.RotateAllCredentials(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 3 || report.Rotated != 3 || report.Skipped != 0 || report.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
@@ -2,19 +2,31 @@ package usecase
 
 import (
 	"api-test/src/common"
+	"api-test/src/common/kms"
 	"api-test/src/config"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
 )
 
 type encryption struct {
-	log    common.Logger
-	config *config.Config
+	log     common.Logger
+	config  *config.Config
+	keys    kms.KeyProvider
+	keysErr error
+	once    sync.Once
 }
 
 func (e *encryption) GenerateRandomKey() ([]byte, error) {
@@ -34,26 +46,197 @@ func (e *encryption) GenerateRandomPassword() (string, error) {
 	return base64.URLEncoding.EncodeToString(key), nil
 }
 
+// Versiones del cifrado de las credenciales. La versión legacy cifra
+// directamente con la clave maestra; envelope cifra con una clave de datos
+// (DEK) por tenant, envuelta por el proveedor de claves (KMS) con una clave
+// de cifrado de claves (KEK) versionada.
+const (
+	legacyVersion  = "AES-GCM-256"
+	envelopePrefix = "envelope:"
+)
+
+// Credencial cifrada tal como se guarda en tenants.tenants
+type envelope struct {
+	Ciphertext []byte
+	IV         []byte
+	DataKey    []byte
+	Version    string
+}
+
+// Proveedor de las KEK, se crea al primer uso según KMS_PROVIDER
+func (e *encryption) provider() (kms.KeyProvider, error) {
+	e.once.Do(func() {
+		if e.keys == nil {
+			e.keys, e.keysErr = kms.New(e.config)
+		}
+	})
+	return e.keys, e.keysErr
+}
+
+func (e *encryption) activeKeyID() string {
+	keys, err := e.provider()
+	if err != nil {
+		return ""
+	}
+	return keys.ActiveKeyID()
+}
+
 func (e *encryption) decodeMasterKey() ([]byte, error) {
-	masterKey, err := base64.StdEncoding.DecodeString(e.config.MasterEncryptionKey)
+	if e.config.MasterEncryptionKey == "" {
+		return nil, errors.New("MASTER_ENCRYPTION_KEY is required to decrypt legacy credentials")
+	}
+	key, err := base64.StdEncoding.DecodeString(e.config.MasterEncryptionKey)
 	if err != nil {
 		return nil, fmt.Errorf("error decodificando clave maestra: %w", err)
 	}
 
 	// Validar longitud de la clave (AES-256 requiere 32 bytes)
-	if len(masterKey) != 32 {
+	if len(key) != 32 {
 		return nil, errors.New("la clave maestra debe ser de 32 bytes (256 bits)")
 	}
-	return masterKey, nil
+	return key, nil
 }
 
-func (e *encryption) Encrypt(plaintext string) (ciphertext, iv []byte, err error) {
-	masterKey, err := e.decodeMasterKey()
+// Seal cifra el texto con una nueva clave de datos envuelta con la KEK activa
+func (e *encryption) Seal(ctx context.Context, plaintext string) (*envelope, error) {
+	dataKey, err := e.GenerateRandomKey()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	ciphertext, iv, err := e.seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, version, err := e.wrap(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &envelope{Ciphertext: ciphertext, IV: iv, DataKey: wrapped, Version: version}, nil
+}
+
+// Open descifra según la versión guardada, incluyendo la versión legacy
+func (e *encryption) Open(ctx context.Context, env envelope) (string, error) {
+	if env.Version == legacyVersion || env.Version == "" {
+		return e.Decrypt(env.Ciphertext, env.IV)
+	}
+	dataKey, err := e.unwrap(ctx, env.DataKey, env.Version)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := e.open(dataKey, env.Ciphertext, env.IV, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap envuelve la clave de datos con la KEK activa sin cambiar el texto
+// cifrado. Los registros legacy se migran a envelope. Retorna false si ya
+// estaba con la clave activa.
+func (e *encryption) Rewrap(ctx context.Context, env envelope) (*envelope, bool, error) {
+	keys, err := e.provider()
+	if err != nil {
+		return nil, false, err
 	}
+	if env.Version == envelopePrefix+keys.ActiveKeyID() {
+		return &env, false, nil
+	}
+	if env.Version == legacyVersion || env.Version == "" {
+		plaintext, err := e.Decrypt(env.Ciphertext, env.IV)
+		if err != nil {
+			return nil, false, err
+		}
+		sealed, err := e.Seal(ctx, plaintext)
+		return sealed, err == nil, err
+	}
+
+	dataKey, err := e.unwrap(ctx, env.DataKey, env.Version)
+	if err != nil {
+		return nil, false, err
+	}
+	wrapped, version, err := e.wrap(ctx, dataKey)
+	if err != nil {
+		return nil, false, err
+	}
+	return &envelope{Ciphertext: env.Ciphertext, IV: env.IV, DataKey: wrapped, Version: version}, true, nil
+}
 
-	block, err := aes.NewCipher(masterKey)
+// Reseal cifra un nuevo texto con la misma clave de datos, envuelta con la
+// KEK activa. Conservar la clave de datos mantiene la clave de las columnas cifradas.
+func (e *encryption) Reseal(ctx context.Context, env envelope, plaintext string) (*envelope, error) {
+	if env.Version == legacyVersion || env.Version == "" {
+		return e.Seal(ctx, plaintext)
+	}
+	dataKey, err := e.unwrap(ctx, env.DataKey, env.Version)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, iv, err := e.seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, version, err := e.wrap(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &envelope{Ciphertext: ciphertext, IV: iv, DataKey: wrapped, Version: version}, nil
+}
+
+// FieldKey deriva la clave para cifrar columnas del tenant a partir de su
+// clave de datos, que no cambia al rotar la KEK. Las credenciales legacy no
+// tienen clave de datos, deben migrarse antes con la rotación de claves.
+func (e *encryption) FieldKey(ctx context.Context, tenantID uuid.UUID, env envelope) ([]byte, error) {
+	if env.Version == legacyVersion || env.Version == "" {
+		return nil, errors.New("legacy credentials have no data key, rotate the encryption keys first")
+	}
+	dataKey, err := e.unwrap(ctx, env.DataKey, env.Version)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf.Key(sha256.New, dataKey, tenantID[:], "tenant-fields", 32)
+}
+
+// FieldKeyFingerprint identifica la clave de columnas sin revelarla, dos
+// tenants con la misma huella leen los mismos valores cifrados
+func (e *encryption) FieldKeyFingerprint(ctx context.Context, tenantID uuid.UUID, env envelope) (string, error) {
+	key, err := e.FieldKey(ctx, tenantID, env)
+	if err != nil {
+		return "", err
+	}
+	fingerprint, err := hkdf.Key(sha256.New, key, nil, "tenant-fields-fingerprint", 16)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(fingerprint), nil
+}
+
+// Envuelve la clave de datos con la KEK activa del proveedor
+func (e *encryption) wrap(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	keys, err := e.provider()
+	if err != nil {
+		return nil, "", err
+	}
+	id, wrapped, err := keys.Wrap(ctx, dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return wrapped, envelopePrefix + id, nil
+}
+
+func (e *encryption) unwrap(ctx context.Context, wrapped []byte, version string) ([]byte, error) {
+	id, ok := strings.CutPrefix(version, envelopePrefix)
+	if !ok {
+		return nil, fmt.Errorf("versión de cifrado desconocida: %s", version)
+	}
+	keys, err := e.provider()
+	if err != nil {
+		return nil, err
+	}
+	return keys.Unwrap(ctx, id, wrapped)
+}
+
+func (e *encryption) seal(key, plaintext, aad []byte) (ciphertext, iv []byte, err error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -68,28 +251,31 @@ func (e *encryption) Encrypt(plaintext string) (ciphertext, iv []byte, err error
 		return nil, nil, err
 	}
 
-	ciphertext = aesGCM.Seal(nil, iv, []byte(plaintext), nil)
-
-	return ciphertext, iv, nil
+	return aesGCM.Seal(nil, iv, plaintext, aad), iv, nil
 }
 
-func (e *encryption) Decrypt(ciphertext, iv []byte) (string, error) {
-	masterKey, err := e.decodeMasterKey()
+func (e *encryption) open(key, ciphertext, iv, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	block, err := aes.NewCipher(masterKey)
+	aesGCM, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	return aesGCM.Open(nil, iv, ciphertext, aad)
+}
+
+// Decrypt descifra credenciales legacy cifradas directamente con la clave maestra
+func (e *encryption) Decrypt(ciphertext, iv []byte) (string, error) {
+	masterKey, err := e.decodeMasterKey()
 	if err != nil {
 		return "", err
 	}
 
-	plaintext, err := aesGCM.Open(nil, iv, ciphertext, nil)
+	plaintext, err := e.open(masterKey, ciphertext, iv, nil)
 	if err != nil {
 		return "", err
 	}
@@ -2,9 +2,13 @@ package usecase
 
 import (
 	"api-test/src/common"
+	"api-test/src/common/kms"
 	"api-test/src/config"
+	"context"
 	"encoding/base64"
 	"testing"
+
+	"github.com/google/uuid"
 )
 
 func Test_encryption_GenerateMasterDemoKey(t *testing.T) {
@@ -17,3 +21,140 @@ func Test_encryption_GenerateMasterDemoKey(t *testing.T) {
 	keyBase64 := base64.StdEncoding.EncodeToString(key)
 	t.Logf("Nueva clave maestra (Base64): %s\n", keyBase64)
 }
+
+func newTestEncryption(t *testing.T, keys ...string) *encryption {
+	t.Helper()
+	conf := config.NewConfig()
+	conf.MasterEncryptionKey = base64.StdEncoding.EncodeToString(make([]byte, 32))
+	e := &encryption{log: common.NewLogger(), config: conf}
+	for _, id := range keys {
+		key, err := e.GenerateRandomKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.EncryptionKeys != "" {
+			conf.EncryptionKeys += ","
+		}
+		conf.EncryptionKeys += id + ":" + base64.StdEncoding.EncodeToString(key)
+	}
+	e.reload(t)
+	return e
+}
+
+// Recarga el proveedor de claves con la configuración actual
+func (e *encryption) reload(t *testing.T) {
+	t.Helper()
+	keys, err := kms.New(e.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.keys = keys
+}
+
+func Test_encryption_SealOpen(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEncryption(t, "k1")
+	e.config.EncryptionActiveKey = "k1"
+	e.reload(t)
+
+	sealed, err := e.Seal(ctx, "secreto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed.Version != "envelope:k1" {
+		t.Errorf("unexpected version %q", sealed.Version)
+	}
+	plaintext, err := e.Open(ctx, *sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "secreto" {
+		t.Errorf("expected secreto, got %q", plaintext)
+	}
+}
+
+func Test_encryption_OpenLegacy(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEncryption(t)
+	masterKey, _ := e.decodeMasterKey()
+	ciphertext, iv, err := e.seal(masterKey, []byte("legacy"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := e.Open(ctx, envelope{Ciphertext: ciphertext, IV: iv, Version: "AES-GCM-256"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "legacy" {
+		t.Errorf("expected legacy, got %q", plaintext)
+	}
+}
+
+func Test_encryption_Rewrap(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEncryption(t, "k1", "k2")
+	e.config.EncryptionActiveKey = "k1"
+	e.reload(t)
+	sealed, err := e.Seal(ctx, "secreto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotar a k2 mantiene el texto cifrado y cambia la clave envuelta
+	e.config.EncryptionActiveKey = "k2"
+	e.reload(t)
+	rotated, changed, err := e.Rewrap(ctx, *sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || rotated.Version != "envelope:k2" {
+		t.Fatalf("expected rewrap to k2, got changed=%v version=%q", changed, rotated.Version)
+	}
+	if string(rotated.Ciphertext) != string(sealed.Ciphertext) {
+		t.Error("ciphertext should not change on rewrap")
+	}
+	plaintext, err := e.Open(ctx, *rotated)
+	if err != nil || plaintext != "secreto" {
+		t.Fatalf("expected secreto, got %q, %v", plaintext, err)
+	}
+
+	// Ya rotado no debe cambiar
+	if _, changed, err := e.Rewrap(ctx, *rotated); err != nil || changed {
+		t.Errorf("expected no change, got changed=%v err=%v", changed, err)
+	}
+}
+
+func Test_encryption_ResealKeepsFieldKey(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEncryption(t, "k1")
+	e.config.EncryptionActiveKey = "k1"
+	e.reload(t)
+	tenantID := uuid.New()
+
+	sealed, err := e.Seal(ctx, "anterior")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resealed, err := e.Reseal(ctx, *sealed, "nueva")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := e.Open(ctx, *resealed)
+	if err != nil || plaintext != "nueva" {
+		t.Fatalf("expected nueva, got %q, %v", plaintext, err)
+	}
+
+	// La clave de columnas no cambia al rotar la contraseña
+	before, err := e.FieldKey(ctx, tenantID, *sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := e.FieldKey(ctx, tenantID, *resealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("field key should not change on reseal")
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListenTenantEvents implements Tenant.
+// Aplica los cambios publicados por las demás instancias. Si se pierde la
+// conexión se reintenta y se sincronizan todos los tenants, ya que los eventos
+// de ese intervalo no se reciben.
+func (t *tenant) ListenTenantEvents(ctx context.Context) error {
+	retry := time.Duration(t.config.TenantLifecycle.EventsRetryInterval) * time.Second
+	for {
+		err := t.events.ListenTenantEvents(ctx, time.Minute, func(event domain.TenantEvent) {
+			t.handleEvent(ctx, event)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		t.log.Error(ctx, "Tenant events listener disconnected", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retry):
+		}
+		if err := t.SyncTenants(ctx); err != nil {
+			t.log.Error(ctx, "Error syncing tenants", "error", err)
+		}
+	}
+}
+
+// Avisa a las demás instancias del cambio, si falla lo aplica la sincronización
+func (t *tenant) publish(ctx context.Context, id uuid.UUID, event string) {
+	err := t.events.PublishTenantEvent(ctx, domain.TenantEvent{TenantID: id, Event: event, Origin: t.instance})
+	if err != nil {
+		t.log.Warn(ctx, "Error publishing tenant event", "tenant_id", id, "event", event, "error", err)
+	}
+}
+
+func (t *tenant) handleEvent(ctx context.Context, event domain.TenantEvent) {
+	if event.Origin == t.instance {
+		return
+	}
+	t.log.Info(ctx, "Tenant event received", "tenant_id", event.TenantID, "event", event.Event)
+	t.planCache.invalidate(event.TenantID)
+	t.settingsCache.invalidate(event.TenantID)
+
+	// Se quita antes de que la base de datos se elimine
+	if event.Event == domain.TenantEventDeleted {
+		t.unregister(ctx, event.TenantID)
+		return
+	}
+
+	table, err := t.repo.GetTenantByID(ctx, event.TenantID)
+	if err != nil {
+		t.log.Error(ctx, "Error getting tenant", "tenant_id", event.TenantID, "error", err)
+		return
+	}
+	if table == nil || table.ID == uuid.Nil {
+		t.unregister(ctx, event.TenantID)
+		return
+	}
+	t.syncTenant(ctx, *table)
+}
+
+// Aplica en esta instancia el estado guardado del tenant
+func (t *tenant) syncTenant(ctx context.Context, table domain.TableTenant) {
+	// Los tenants en aprovisionamiento los registra su job
+	if table.Status != domain.TenantStatusReady {
+		return
+	}
+	config, err := t.tenantManager.GetTenantConfig(table.ID)
+	if err != nil {
+		if err := t.register(ctx, table, false); err != nil {
+			t.log.Error(ctx, "Error registering tenant", "tenant_id", table.ID, "error", err)
+			return
+		}
+		t.log.Info(ctx, "Tenant registered", "tenant", table.Name, "tenant_id", table.ID)
+		return
+	}
+	if config.Provisioning {
+		return
+	}
+
+	if config.Name != table.Name || config.Slug != table.Slug || config.Suspended != !table.IsActive || config.Plan != table.PlanID {
+		_ = t.tenantManager.UpdateConfig(table.ID, func(config *common.TenantConfig) {
+			config.Name = table.Name
+			config.Slug = table.Slug
+			config.Suspended = !table.IsActive
+			config.Plan = table.PlanID
+		})
+	}
+	if pool := poolSettings(table.PoolSettings()); config.Pool != pool {
+		if err := t.tenantManager.UpdatePool(table.ID, pool); err != nil {
+			t.log.Error(ctx, "Error updating tenant pool", "tenant_id", table.ID, "error", err)
+		}
+	}
+	if !slices.Equal(config.Replicas, table.DBReplicas) {
+		if err := t.tenantManager.UpdateReplicas(table.ID, table.DBReplicas); err != nil {
+			t.log.Error(ctx, "Error updating tenant replicas", "tenant_id", table.ID, "error", err)
+		}
+	}
+	if config.CredentialsRotatedAt.Equal(table.CredentialsRotatedAt) {
+		return
+	}
+	if err := t.swap(ctx, table); err != nil {
+		t.log.Error(ctx, "Error reloading tenant credentials", "tenant_id", table.ID, "error", err)
+		return
+	}
+	t.log.Info(ctx, "Tenant credentials reloaded", "tenant_id", table.ID)
+}
+
+// Cierra la conexión de un tenant eliminado por otra instancia
+func (t *tenant) unregister(ctx context.Context, id uuid.UUID) {
+	if _, err := t.tenantManager.GetTenantConfig(id); err != nil {
+		return
+	}
+	if err := t.tenantManager.RemoveTenant(id); err != nil {
+		t.log.Error(ctx, "Error closing tenant connection", "tenant_id", id, "error", err)
+		return
+	}
+	t.log.Info(ctx, "Tenant unregistered", "tenant_id", id)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// Registra el tenant en el manager sin conectarse al servidor
+func registerTestTenant(t *testing.T, uc *tenant, table domain.TableTenant) {
+	t.Helper()
+	err := uc.tenantManager.RegisterTenant(&common.TenantConfig{
+		TenantID:         table.ID,
+		Name:             table.Name,
+		Slug:             table.Slug,
+		ConnectionString: "postgres://u:p@localhost:5432/db",
+	}, func(tenantID uuid.UUID, dsn string) (*bun.DB, error) {
+		return bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New()), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_tenant_handleEvent(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	_, table := repo.addTenant(uuid.New())
+	registerTestTenant(t, uc, table)
+	uc.settingsCache.set(table.ID, map[string]json.RawMessage{"locale": json.RawMessage(`"es"`)}, time.Minute)
+
+	// Otra instancia renombra y suspende el tenant
+	changed := table
+	changed.Name = "Tienda Nueva"
+	changed.IsActive = false
+	repo.tenants[table.ID] = changed
+
+	// Los eventos propios ya están aplicados
+	uc.handleEvent(context.Background(), domain.TenantEvent{TenantID: table.ID, Event: domain.TenantEventUpdated, Origin: uc.instance})
+	if config, _ := uc.tenantManager.GetTenantConfig(table.ID); config.Name != table.Name {
+		t.Fatalf("own event applied: %+v", config)
+	}
+	if _, ok := uc.settingsCache.get(table.ID); !ok {
+		t.Fatal("own event invalidated the cache")
+	}
+
+	uc.handleEvent(context.Background(), domain.TenantEvent{TenantID: table.ID, Event: domain.TenantEventUpdated, Origin: uuid.New()})
+	config, err := uc.tenantManager.GetTenantConfig(table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Name != "Tienda Nueva" || !config.Suspended {
+		t.Errorf("tenant config not synced: %+v", config)
+	}
+	if _, ok := uc.settingsCache.get(table.ID); ok {
+		t.Error("settings cache not invalidated")
+	}
+
+	uc.handleEvent(context.Background(), domain.TenantEvent{TenantID: table.ID, Event: domain.TenantEventDeleted, Origin: uuid.New()})
+	if _, err := uc.tenantManager.GetTenantConfig(table.ID); err == nil {
+		t.Error("deleted tenant still registered")
+	}
+}
+
+func Test_tenant_handleEventMissingTenant(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	_, table := repo.addTenant(uuid.New())
+	registerTestTenant(t, uc, table)
+
+	// Si el tenant ya no existe se cierra su conexión
+	delete(repo.tenants, table.ID)
+	uc.handleEvent(context.Background(), domain.TenantEvent{TenantID: table.ID, Event: domain.TenantEventUpdated, Origin: uuid.New()})
+	if _, err := uc.tenantManager.GetTenantConfig(table.ID); err == nil {
+		t.Error("missing tenant still registered")
+	}
+}
+
+func Test_tenant_syncTenantSkipsProvisioning(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	_, table := repo.addTenant(uuid.New())
+
+	// Los tenants en aprovisionamiento los registra su job
+	table.Status = domain.TenantStatusProvisioning
+	uc.syncTenant(context.Background(), table)
+	if _, err := uc.tenantManager.GetTenantConfig(table.ID); err == nil {
+		t.Error("provisioning tenant registered")
+	}
+
+	registerTestTenant(t, uc, table)
+	if err := uc.tenantManager.UpdateConfig(table.ID, func(config *common.TenantConfig) { config.Provisioning = true }); err != nil {
+		t.Fatal(err)
+	}
+	table.Status = domain.TenantStatusReady
+	table.Name = "Otro"
+	uc.syncTenant(context.Background(), table)
+	if config, _ := uc.tenantManager.GetTenantConfig(table.ID); config.Name == "Otro" {
+		t.Error("config of a tenant being provisioned was overwritten")
+	}
+}
@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportTenant implements Tenant.
+// Exporta los datos del tenant en segundo plano, el archivo se descarga al terminar el job.
+func (t *tenant) ExportTenant(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error) {
+	table, err := t.getOwnedTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if table.Status != domain.TenantStatusReady {
+		return nil, common.ConflictError("tenant is not ready")
+	}
+	return t.startJob(ctx, uuid.New(), domain.JobKindExport, id, nil)
+}
+
+// ImportTenant implements Tenant.
+// Reemplaza los datos del tenant con el archivo subido o con el de una
+// exportación anterior. Solo el dueño importa, el archivo debe estar en la
+// versión de migraciones del tenant y cifrado con su clave de columnas.
+func (t *tenant) ImportTenant(ctx context.Context, id uuid.UUID, archive io.Reader, exportJobID uuid.UUID) (*domain.DTOJob, error) {
+	table, err := t.getOwnerTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if table.Status != domain.TenantStatusReady {
+		return nil, common.ConflictError("tenant is not ready")
+	}
+	// En las tablas compartidas los ids del archivo pueden chocar con los de otros tenants
+	if table.Isolation == domain.TenantIsolationShared {
+		return nil, common.ConflictError("tenants with shared tables cannot be imported")
+	}
+
+	jobID := uuid.New()
+	archivePath := t.archivePath(jobID)
+	if archive != nil {
+		if err := t.saveArchive(archivePath, archive); err != nil {
+			t.log.Error(ctx, "Error saving import archive", "tenant_id", id, "error", err)
+			return nil, err
+		}
+	} else {
+		export, err := t.getOwnedJob(ctx, exportJobID)
+		if err != nil {
+			return nil, err
+		}
+		if export.Kind != domain.JobKindExport || export.Status != domain.JobStatusReady {
+			return nil, common.ConflictError("job is not a finished export")
+		}
+		archivePath = t.archivePath(export.ID)
+	}
+
+	manifest, err := t.readManifest(archivePath)
+	if err != nil {
+		t.removeArchive(ctx, jobID)
+		return nil, common.BadRequestError(fmt.Sprintf("invalid archive: %s", err))
+	}
+	if err := t.checkImport(ctx, *table, manifest); err != nil {
+		t.removeArchive(ctx, jobID)
+		return nil, err
+	}
+	return t.startJob(ctx, jobID, domain.JobKindImport, id, domain.ImportPayload{Archive: archivePath})
+}
+
+// GetJobArchive implements Tenant.
+// Retorna la ruta del archivo de una exportación terminada.
+func (t *tenant) GetJobArchive(ctx context.Context, jobID uuid.UUID) (string, error) {
+	job, err := t.getOwnedJob(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	if job.Kind != domain.JobKindExport {
+		return "", common.BadRequestError("job is not an export")
+	}
+	if job.Status != domain.JobStatusReady {
+		return "", common.ConflictError(fmt.Sprintf("export is %s", job.Status))
+	}
+	archivePath := t.archivePath(job.ID)
+	if _, err := os.Stat(archivePath); err != nil {
+		return "", common.NotFoundError("export archive not found")
+	}
+	return archivePath, nil
+}
+
+// Crea el job del usuario del contexto y lo ejecuta en segundo plano
+func (t *tenant) startJob(ctx context.Context, id uuid.UUID, kind string, tenantID uuid.UUID, payload any) (*domain.DTOJob, error) {
+	userID, ok := ctx.Value(t.tenantManager.UserIDKey).(uuid.UUID)
+	if !ok {
+		return nil, common.UnauthorizedError("user not found in context")
+	}
+	job := domain.TableJob{
+		ID:       id,
+		Kind:     kind,
+		TenantID: tenantID,
+		UserID:   userID,
+		Status:   domain.JobStatusPending,
+		Step:     domain.JobStatusPending,
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		job.Payload = raw
+	}
+	created, err := t.jobs.CreateJob(ctx, job)
+	if err != nil {
+		t.log.Error(ctx, "Error creating job", "kind", kind, "tenant_id", tenantID, "error", err)
+		return nil, err
+	}
+	go t.runJob(context.Background(), created.ID)
+
+	result := created.ToDTO()
+	return &result, nil
+}
+
+func (t *tenant) exportStep(ctx context.Context, job *domain.TableJob) (string, error) {
+	if job.Step != domain.JobStatusPending {
+		return "", fmt.Errorf("unknown export step: %s", job.Step)
+	}
+	table, err := t.repo.GetTenantByID(ctx, job.TenantID)
+	if err != nil {
+		return "", err
+	}
+	if table == nil || table.ID == uuid.Nil {
+		return "", fmt.Errorf("tenant %s not found", job.TenantID)
+	}
+
+	archivePath := t.archivePath(job.ID)
+	manifest, size, err := t.writeArchive(ctx, *table, archivePath)
+	if err != nil {
+		return "", err
+	}
+	if err := t.setJobResult(ctx, job, domain.DTOExportResult{Archive: filepath.Base(archivePath), Size: size, Manifest: *manifest}); err != nil {
+		return "", err
+	}
+	return domain.JobStatusReady, nil
+}
+
+// Escribe el zip con una tabla por archivo y el manifiesto. Se escribe en un
+// archivo temporal para no dejar exportaciones a medias. Retorna el
+// manifiesto y el tamaño del archivo.
+func (t *tenant) writeArchive(ctx context.Context, table domain.TableTenant, archivePath string) (*domain.ExportManifest, int64, error) {
+	status, err := t.migrations.GetTenantMigrationStatus(ctx, table.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	fieldKey, err := t.fieldKeyFingerprint(ctx, table)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0o750); err != nil {
+		return nil, 0, err
+	}
+	tmp := archivePath + ".tmp"
+	defer os.Remove(tmp)
+	file, err := os.Create(tmp)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	tables, err := t.data.Export(ctx, table.ID, func(exported domain.ExportTable) (io.Writer, error) {
+		return archive.Create(exportFile(exported.Name))
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range tables {
+		tables[i].File = exportFile(tables[i].Name)
+	}
+	manifest := domain.ExportManifest{
+		FormatVersion:    domain.ExportFormatVersion,
+		TenantID:         table.ID,
+		TenantName:       table.Name,
+		Isolation:        table.Isolation,
+		MigrationVersion: status.CurrentVersion,
+		CreatedAt:        time.Now(),
+		Tables:           tables,
+		FieldKey:         fieldKey,
+	}
+	w, err := archive.Create(domain.ExportManifestFile)
+	if err != nil {
+		return nil, 0, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, 0, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, 0, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, 0, err
+	}
+	if err := os.Rename(tmp, archivePath); err != nil {
+		return nil, 0, err
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &manifest, info.Size(), nil
+}
+
+// Importa los datos si el tenant sigue en la versión del archivo. Los jobs
+// creados antes de validar la versión pueden estar en el paso migrated.
+func (t *tenant) importStep(ctx context.Context, job *domain.TableJob) (string, error) {
+	var payload domain.ImportPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return "", err
+	}
+	manifest, err := t.readManifest(payload.Archive)
+	if err != nil {
+		return "", err
+	}
+
+	switch job.Step {
+	case domain.JobStatusPending, domain.JobStatusMigrated:
+		table, err := t.repo.GetTenantByID(ctx, job.TenantID)
+		if err != nil {
+			return "", err
+		}
+		if table == nil || table.ID == uuid.Nil {
+			return "", fmt.Errorf("tenant %s not found", job.TenantID)
+		}
+		// Las migraciones del tenant pueden haber cambiado desde que se creó el job
+		if err := t.checkImport(ctx, *table, manifest); err != nil {
+			return "", err
+		}
+		rows, err := t.importArchive(ctx, job.TenantID, payload.Archive, manifest)
+		if err != nil {
+			return "", err
+		}
+		result := domain.DTOImportResult{MigrationVersion: manifest.MigrationVersion, Tables: len(manifest.Tables), Rows: rows}
+		if err := t.setJobResult(ctx, job, result); err != nil {
+			return "", err
+		}
+		t.removeArchive(ctx, job.ID)
+		return domain.JobStatusReady, nil
+	}
+
+	return "", fmt.Errorf("unknown import step: %s", job.Step)
+}
+
+// Valida que el archivo se pueda importar en el tenant sin migrarlo: las
+// migraciones hacia abajo pueden borrar datos y la versión viene del archivo.
+// Las columnas cifradas solo se leen con la misma clave de columnas.
+func (t *tenant) checkImport(ctx context.Context, table domain.TableTenant, manifest *domain.ExportManifest) error {
+	status, err := t.migrations.GetTenantMigrationStatus(ctx, table.ID)
+	if err != nil {
+		return err
+	}
+	if manifest.MigrationVersion != status.CurrentVersion {
+		return common.ConflictError(fmt.Sprintf("archive migration version %d does not match tenant version %d", manifest.MigrationVersion, status.CurrentVersion))
+	}
+	fieldKey, err := t.fieldKeyFingerprint(ctx, table)
+	if err != nil {
+		return err
+	}
+	if manifest.FieldKey != fieldKey {
+		return common.ConflictError("archive was exported with a different field encryption key")
+	}
+	return nil
+}
+
+// Huella de la clave de columnas del tenant, vacía con credenciales legacy
+// porque no tienen clave de datos
+func (t *tenant) fieldKeyFingerprint(ctx context.Context, table domain.TableTenant) (string, error) {
+	if table.Version == legacyVersion || table.Version == "" {
+		return "", nil
+	}
+	return t.crypto.FieldKeyFingerprint(ctx, fieldKeyID(table), sealedPassword(table))
+}
+
+// Lleva las migraciones del clon a la versión del origen, hacia arriba o
+// abajo. Solo se usa con tenants recién creados, todavía sin datos.
+func (t *tenant) migrateTo(ctx context.Context, tenantID uuid.UUID, version int64, reason string) error {
+	status, err := t.migrations.GetTenantMigrationStatus(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if status.CurrentVersion == version {
+		return nil
+	}
+	direction := domain.MigrationDirectionUp
+	if status.CurrentVersion > version {
+		direction = domain.MigrationDirectionDown
+	}
+	_, err = t.migrations.MigrateTenantTo(ctx, tenantID, direction, domain.DTOMigrationTarget{
+		Version:            version,
+		Reason:             reason,
+		ConfirmDestructive: true,
+	})
+	return err
+}
+
+// Reemplaza los datos del tenant con las tablas del archivo
+func (t *tenant) importArchive(ctx context.Context, tenantID uuid.UUID, archivePath string, manifest *domain.ExportManifest) (int, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer archive.Close()
+	return t.data.Import(ctx, tenantID, manifest.Tables, func(table domain.ExportTable) (io.ReadCloser, error) {
+		return archive.Open(table.File)
+	})
+}
+
+func (t *tenant) setJobResult(ctx context.Context, job *domain.TableJob, result any) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	job.Result = raw
+	_, err = t.jobs.UpdateJob(ctx, *job, "result")
+	return err
+}
+
+// Lee y valida el manifiesto del archivo
+func (t *tenant) readManifest(archivePath string) (*domain.ExportManifest, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	file, err := archive.Open(domain.ExportManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var manifest domain.ExportManifest
+	if err := json.NewDecoder(file).Decode(&manifest); err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion != domain.ExportFormatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", manifest.FormatVersion)
+	}
+	for _, table := range manifest.Tables {
+		if _, err := fs.Stat(archive, table.File); err != nil {
+			return nil, fmt.Errorf("table %s: %w", table.Name, err)
+		}
+	}
+	return &manifest, nil
+}
+
+func (t *tenant) saveArchive(archivePath string, archive io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0o750); err != nil {
+		return err
+	}
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, archive); err != nil {
+		file.Close()
+		os.Remove(archivePath)
+		return err
+	}
+	return file.Close()
+}
+
+// Elimina el archivo del job, los subidos para importar se eliminan al terminar
+func (t *tenant) removeArchive(ctx context.Context, jobID uuid.UUID) {
+	if err := os.Remove(t.archivePath(jobID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		t.log.Warn(ctx, "Error removing job archive", "job_id", jobID, "error", err)
+	}
+}
+
+func (t *tenant) archivePath(jobID uuid.UUID) string {
+	return filepath.Join(t.config.TenantLifecycle.ExportsDir, jobID.String()+".zip")
+}
+
+func exportFile(table string) string {
+	return path.Join("tables", table+".ndjson")
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Escribe un zip con los archivos indicados
+func writeTestArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "export.zip")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	archive := zip.NewWriter(file)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return archivePath
+}
+
+func testManifest(t *testing.T, version int, tables ...string) string {
+	t.Helper()
+	manifest := domain.ExportManifest{FormatVersion: version, MigrationVersion: 20261019140000}
+	for _, table := range tables {
+		manifest.Tables = append(manifest.Tables, domain.ExportTable{Name: table, File: exportFile(table)})
+	}
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func Test_tenant_readManifest(t *testing.T) {
+	uc, _, _ := newTestTenant()
+
+	valid := writeTestArchive(t, map[string]string{
+		domain.ExportManifestFile: testManifest(t, domain.ExportFormatVersion, "productos"),
+		exportFile("productos"):   "{\"id\":1}\n",
+	})
+	manifest, err := uc.readManifest(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.MigrationVersion != 20261019140000 || len(manifest.Tables) != 1 || manifest.Tables[0].File != "tables/productos.ndjson" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	invalid := map[string]map[string]string{
+		"no manifest":    {exportFile("productos"): ""},
+		"invalid json":   {domain.ExportManifestFile: "{"},
+		"format version": {domain.ExportManifestFile: testManifest(t, domain.ExportFormatVersion+1)},
+		// Una tabla del manifiesto sin su archivo
+		"missing table": {domain.ExportManifestFile: testManifest(t, domain.ExportFormatVersion, "productos", "clientes"), exportFile("productos"): ""},
+	}
+	for name, files := range invalid {
+		if _, err := uc.readManifest(writeTestArchive(t, files)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := uc.readManifest(filepath.Join(t.TempDir(), "missing.zip")); err == nil {
+		t.Error("expected an error for a missing archive")
+	}
+}
+
+// Migraciones de todos los tenants en una misma versión
+type staticMigrations struct {
+	TenantMigrations
+	version int64
+}
+
+func (m *staticMigrations) GetTenantMigrationStatus(ctx context.Context, tenantID uuid.UUID) (*domain.DTOMigrationStatus, error) {
+	return &domain.DTOMigrationStatus{CurrentVersion: m.version}, nil
+}
+
+func importManifest(t *testing.T, version int64, fieldKey string) *domain.ExportManifest {
+	t.Helper()
+	return &domain.ExportManifest{FormatVersion: domain.ExportFormatVersion, MigrationVersion: version, FieldKey: fieldKey}
+}
+
+func Test_tenant_checkImport(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	uc.crypto = newTestEncryption(t)
+	uc.migrations = &staticMigrations{version: 20261019140000}
+	_, table := addSealedTenant(t, uc, repo, time.Now())
+	_, other := addSealedTenant(t, uc, repo, time.Now())
+
+	fieldKey, err := uc.fieldKeyFingerprint(context.Background(), table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldKey == "" {
+		t.Fatal("empty field key fingerprint")
+	}
+	if err := uc.checkImport(context.Background(), table, importManifest(t, 20261019140000, fieldKey)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Un clon usa la clave del origen y puede importar sus archivos
+	clone := other
+	clone.FieldKeyID = table.ID
+	clone.DataKey, clone.Version = table.DataKey, table.Version
+	if err := uc.checkImport(context.Background(), clone, importManifest(t, 20261019140000, fieldKey)); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+
+	otherKey, err := uc.fieldKeyFingerprint(context.Background(), other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := map[string]*domain.ExportManifest{
+		// Migrar hacia abajo a la versión del archivo puede borrar datos
+		"older version": importManifest(t, 20261019130000, fieldKey),
+		"newer version": importManifest(t, 20261019150000, fieldKey),
+		"other tenant":  importManifest(t, 20261019140000, otherKey),
+		"no field key":  importManifest(t, 20261019140000, ""),
+	}
+	for name, manifest := range invalid {
+		if err := uc.checkImport(context.Background(), table, manifest); common.StatusCode(err, 0) != http.StatusConflict {
+			t.Errorf("%s: expected a conflict, got %v", name, err)
+		}
+	}
+}
+
+func Test_tenant_ImportTenantRejected(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	uc.crypto = newTestEncryption(t)
+	uc.migrations = &staticMigrations{version: 20261019140000}
+	uc.config.TenantLifecycle.ExportsDir = t.TempDir()
+	ownerCtx, table := addSealedTenant(t, uc, repo, time.Now())
+	content, err := json.Marshal(importManifest(t, 20261019130000, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := writeTestArchive(t, map[string]string{domain.ExportManifestFile: string(content)})
+
+	// Importar reemplaza los datos, un miembro no puede hacerlo
+	file, err := os.Open(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := uc.ImportTenant(repo.addMember(table.ID), table.ID, file, uuid.Nil); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+
+	if _, err := uc.ImportTenant(ownerCtx, table.ID, file, uuid.Nil); common.StatusCode(err, 0) != http.StatusConflict {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	// El archivo subido se elimina al rechazarlo
+	entries, err := os.ReadDir(uc.config.TenantLifecycle.ExportsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("archive not removed: %v", entries)
+	}
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// GetTenantsHealth implements Tenant.
+// Estado de las conexiones según la última revisión en segundo plano.
+// Lista nombres y errores de todos los tenants, solo para operadores.
+func (t *tenant) GetTenantsHealth(ctx context.Context) (*domain.DTOHealthReport, error) {
+	if err := t.tenantManager.RequireOperator(ctx); err != nil {
+		return nil, err
+	}
+	report := &domain.DTOHealthReport{}
+	for _, health := range t.tenantManager.Health() {
+		report.Total++
+		replica := health.ReplicaOf != uuid.Nil
+		switch {
+		case health.State == common.HealthHealthy:
+			report.Healthy++
+		// Con una réplica caída las lecturas van a la principal
+		case health.State == common.HealthDegraded, replica && health.State == common.HealthOpen:
+			report.Degraded++
+		case health.State == common.HealthOpen:
+			report.Open++
+		default:
+			report.Unknown++
+		}
+		report.Tenants = append(report.Tenants, domain.DTOTenantHealth{
+			TenantID:  health.TenantID,
+			ReplicaOf: health.ReplicaOf,
+			Name:      health.Name,
+			State:     health.State,
+			Failures:  health.Failures,
+			LastCheck: health.LastCheck,
+			LastError: health.LastError,
+			OpenedAt:  health.OpenedAt,
+		})
+	}
+	slices.SortFunc(report.Tenants, func(a, b domain.DTOTenantHealth) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return report, nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_tenant_GetTenantsHealthRequiresOperator(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	operator := uuid.New()
+	uc.config.Operators.UserIDs = []uuid.UUID{operator}
+	ownerCtx, _ := repo.addTenant(uuid.New())
+
+	// Un dueño de tenant no ve el estado del resto de la flota
+	if _, err := uc.GetTenantsHealth(ownerCtx); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 for a tenant owner, got %v", err)
+	}
+	if _, err := uc.GetTenantsHealth(context.Background()); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 without user, got %v", err)
+	}
+
+	report, err := uc.GetTenantsHealth(context.WithValue(context.Background(), common.UserIDKey, operator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != len(report.Tenants) {
+		t.Errorf("inconsistent report: %+v", report)
+	}
+}
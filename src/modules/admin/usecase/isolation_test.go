@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"api-test/src/modules/admin/domain"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_tenant_tenantDatabaseSchema(t *testing.T) {
+	uc, _, _ := newTestTenant()
+	uc.config.TenantLifecycle.SharedDBName = "kosvi_shared"
+	id := uuid.MustParse("1a2b3c4d-0000-4000-8000-000000000000")
+
+	isolation, dbName, dbSchema := uc.tenantDatabase(domain.TenantIsolationSchema, "Tienda Ñandú", id)
+	if isolation != domain.TenantIsolationSchema || dbName != "kosvi_shared" || dbSchema != "db_tienda_nandu_1a2b3c4d" {
+		t.Errorf("unexpected schema tenant location: %s %s %s", isolation, dbName, dbSchema)
+	}
+
+	// Sin aislamiento válido el tenant tiene base de datos propia
+	for _, requested := range []string{domain.TenantIsolationDatabase, "", "otro"} {
+		isolation, dbName, dbSchema = uc.tenantDatabase(requested, "Tienda", id)
+		if isolation != domain.TenantIsolationDatabase || !strings.HasPrefix(dbName, "db_tienda_") || dbSchema != "" {
+			t.Errorf("%q: unexpected database tenant location: %s %s %q", requested, isolation, dbName, dbSchema)
+		}
+	}
+
+	// Los tenants compartidos viven en el esquema pooled de la base compartida
+	uc.config.TenantLifecycle.PooledSchema = "pooled"
+	isolation, dbName, dbSchema = uc.tenantDatabase(domain.TenantIsolationShared, "Tienda", id)
+	if isolation != domain.TenantIsolationShared || dbName != "kosvi_shared" || dbSchema != "pooled" {
+		t.Errorf("unexpected shared tenant location: %s %s %s", isolation, dbName, dbSchema)
+	}
+}
@@ -3,12 +3,21 @@ package usecase
 import (
 	"api-test/src/common"
 	"api-test/src/config"
+	"api-test/src/database/postgres/gomigrations"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
 	"context"
 	"database/sql"
 	"embed"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
 	"github.com/uptrace/bun"
 )
 
@@ -18,20 +27,29 @@ type TenantMigrations interface {
 	RunMigration(ctx context.Context, tenantID uuid.UUID, migrationID int64) error
 	RollbackAllMigrations(ctx context.Context, tenantID uuid.UUID) error
 	RollbackMigration(ctx context.Context, tenantID uuid.UUID, migrationID int64) error
+	RunFleetMigrations(ctx context.Context, opts domain.DTOFleetMigration) (*domain.DTOFleetMigrationReport, error)
+	GetAdminMigrationStatus(ctx context.Context) (*domain.DTOMigrationStatus, error)
+	GetTenantMigrationStatus(ctx context.Context, tenantID uuid.UUID) (*domain.DTOMigrationStatus, error)
+	GetFleetMigrationStatus(ctx context.Context) (*domain.DTOFleetMigrationStatus, error)
+	MigrateTenantTo(ctx context.Context, tenantID uuid.UUID, direction string, target domain.DTOMigrationTarget) (*domain.DTOMigrationPlan, error)
+	RunSeeds(ctx context.Context, tenantID uuid.UUID) ([]string, error)
 }
 
+
 type tenantMigrations struct {
 	log                common.Logger
 	config             *config.Config
 	tenantManager      *common.TenantConnectionManager
+	audits             repository.AuditRepository
 	adminMigrationsFS  embed.FS
 	tenantMigrationsFS embed.FS
 	commonMigrationsFS embed.FS
+	seedsFS            embed.FS
 }
 
 // RollbackAllMigrations implements TenantMigrations.
 func (t *tenantMigrations) RollbackAllMigrations(ctx context.Context, tenantID uuid.UUID) error {
-db, err := t.getDB(ctx, tenantID)
+	db, err := t.tenantMigrationsDB(ctx, tenantID)
 	if err != nil {
 		return err
 	}
@@ -46,7 +64,7 @@ db, err := t.getDB(ctx, tenantID)
 
 // RollbackMigration implements TenantMigrations.
 func (t *tenantMigrations) RollbackMigration(ctx context.Context, tenantID uuid.UUID, migrationID int64) error {
-	db, err := t.getDB(ctx, tenantID)
+	db, err := t.tenantMigrationsDB(ctx, tenantID)
 	if err != nil {
 		return err
 	}
@@ -59,8 +77,10 @@ func (t *tenantMigrations) RollbackMigration(ctx context.Context, tenantID uuid.
 }
 
 // RunAllMigrations implements TenantMigrations.
+// Los tenants con tablas compartidas migran el schema del pool, que además
+// aplica las políticas RLS.
 func (t *tenantMigrations) RunAllMigrations(ctx context.Context, tenantID uuid.UUID) error {
-	db, err := t.getDB(ctx, tenantID)
+	db, shared, err := t.migrationsDB(ctx, tenantID)
 	if err != nil {
 		return err
 	}
@@ -75,12 +95,19 @@ func (t *tenantMigrations) RunAllMigrations(ctx context.Context, tenantID uuid.U
 		return err
 	}
 
+	// run shared tables migrations
+	if shared {
+		if err := t.up(ctx, db.DB, t.tenantMigrationsFS, "migrations/shared"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // RunMigration implements TenantMigrations.
 func (t *tenantMigrations) RunMigration(ctx context.Context, tenantID uuid.UUID, migrationID int64) error {
-	db, err := t.getDB(ctx, tenantID)
+	db, err := t.tenantMigrationsDB(ctx, tenantID)
 	if err != nil {
 		return err
 	}
@@ -115,6 +142,99 @@ func (t *tenantMigrations) RunAdminMigrations(ctx context.Context) error {
 }
 
 
+// RunFleetMigrations implements TenantMigrations.
+// Migra todos los tenants registrados (o los indicados) con paralelismo limitado.
+// También se ejecuta al iniciar con --migrate-tenants, sin usuario en el
+// contexto; la ruta /migrations/tenants la limita a los operadores.
+func (t *tenantMigrations) RunFleetMigrations(ctx context.Context, opts domain.DTOFleetMigration) (*domain.DTOFleetMigrationReport, error) {
+	start := time.Now()
+	tenantIDs := opts.TenantIDs
+	if len(tenantIDs) == 0 {
+		tenantIDs = t.tenantManager.TenantIDs()
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = t.config.Migrations.Concurrency
+	}
+	if opts.TimeoutSeconds <= 0 {
+		opts.TimeoutSeconds = t.config.Migrations.TenantTimeout
+	}
+	timeout := time.Duration(opts.TimeoutSeconds) * time.Second
+
+	// Sin continue-on-error el primer fallo detiene los tenants pendientes
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	t.log.Info(ctx, "Running fleet migrations", "tenants", len(tenantIDs), "concurrency", opts.Concurrency, "timeout", timeout)
+	results := make([]domain.DTOTenantMigrationResult, len(tenantIDs))
+	sem := make(chan struct{}, max(opts.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, tenantID := range tenantIDs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = t.migrateTenant(runCtx, tenantID, timeout)
+			if results[i].Status == domain.MigrationResultFailed && !opts.ContinueOnError {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := &domain.DTOFleetMigrationReport{
+		Total:    len(results),
+		Duration: time.Since(start).String(),
+		Results:  results,
+	}
+	for _, result := range results {
+		switch result.Status {
+		case domain.MigrationResultSucceeded:
+			report.Succeeded++
+		case domain.MigrationResultFailed:
+			report.Failed++
+		default:
+			report.Skipped++
+		}
+	}
+	t.log.Info(ctx, "Fleet migrations completed", "total", report.Total, "succeeded", report.Succeeded, "failed", report.Failed, "skipped", report.Skipped, "duration", report.Duration)
+	return report, nil
+}
+
+func (t *tenantMigrations) migrateTenant(ctx context.Context, tenantID uuid.UUID, timeout time.Duration) domain.DTOTenantMigrationResult {
+	start := time.Now()
+	result := domain.DTOTenantMigrationResult{TenantID: tenantID, Status: domain.MigrationResultSkipped}
+	defer func() { result.Duration = time.Since(start).String() }()
+
+	if ctx.Err() != nil {
+		result.Error = "cancelled after a previous failure"
+		return result
+	}
+	config, err := t.tenantManager.GetTenantConfig(tenantID)
+	if err != nil {
+		result.Status = domain.MigrationResultFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Name = config.Name
+	// El job de aprovisionamiento se encarga de sus migraciones
+	if config.Provisioning {
+		result.Error = "tenant is being provisioned"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := t.RunAllMigrations(ctx, tenantID); err != nil {
+		t.log.Error(ctx, "Error migrating tenant", "tenant_id", tenantID, "error", err)
+		result.Status = domain.MigrationResultFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = domain.MigrationResultSucceeded
+	return result
+}
+
 func (t *tenantMigrations) getDB(ctx context.Context, tenantID uuid.UUID) (*bun.DB, error) {
 	var db *bun.DB
 	if tenantID != uuid.Nil {
@@ -133,84 +253,161 @@ func (t *tenantMigrations) getDB(ctx context.Context, tenantID uuid.UUID) (*bun.
 	return db, nil
 }
 
-func (t *tenantMigrations) up(ctx context.Context, db *sql.DB, embedMigrations embed.FS, folder string) error {
-	goose.SetBaseFS(embedMigrations)
+// Conexión donde se migra el tenant. Las tablas compartidas se migran con el
+// usuario admin en el schema del pool, retorna true en ese caso.
+func (t *tenantMigrations) migrationsDB(ctx context.Context, tenantID uuid.UUID) (*bun.DB, bool, error) {
+	if tenantID != uuid.Nil {
+		if config, err := t.tenantManager.GetTenantConfig(tenantID); err == nil && config.RowLevelSecurity {
+			db, err := t.tenantManager.GetPooledDB()
+			return db, true, err
+		}
+	}
+	db, err := t.getDB(ctx, tenantID)
+	return db, false, err
+}
 
-	if err := goose.SetDialect("postgres"); err != nil {
-		t.log.Error(ctx, "Error setting dialect", "error", err)
+// Conexión para migrar un solo tenant. Con tablas compartidas el cambio
+// afectaría a todos los tenants del pool, por lo que no se permite.
+func (t *tenantMigrations) tenantMigrationsDB(ctx context.Context, tenantID uuid.UUID) (*bun.DB, error) {
+	db, shared, err := t.migrationsDB(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		return nil, common.ConflictError("tenant uses shared tables, they are migrated with the whole pool")
+	}
+	return db, nil
+}
+
+// Crea un provider de goose por base de datos y carpeta, sin estado global.
+// El session locker toma un advisory lock de Postgres para que varias
+// réplicas no migren la misma base de datos a la vez. En la base de datos
+// compartida el lock es por schema para migrar los tenants en paralelo.
+func (t *tenantMigrations) provider(ctx context.Context, db *sql.DB, embedMigrations embed.FS, folder string) (*goose.Provider, error) {
+	fsys, err := fs.Sub(embedMigrations, folder)
+	if err != nil {
+		return nil, err
+	}
+	var schema string
+	if err := db.QueryRowContext(ctx, "SELECT current_schema()").Scan(&schema); err != nil {
+		return nil, err
+	}
+	locker, err := lock.NewPostgresSessionLocker(lock.WithLockID(migrationLockID(schema)))
+	if err != nil {
+		return nil, err
+	}
+	return goose.NewProvider(goose.DialectPostgres, db, fsys,
+		goose.WithSessionLocker(locker),
+		goose.WithDisableGlobalRegistry(true),
+		goose.WithGoMigrations(gomigrations.For(path.Base(folder))...),
+	)
+}
+
+// Advisory lock de las migraciones, el de goose para public y uno por schema
+// en la base de datos compartida
+func migrationLockID(schema string) int64 {
+	if schema == "public" {
+		return lock.DefaultLockID
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(schema))
+	return int64(hash.Sum64())
+}
+
+func (t *tenantMigrations) up(ctx context.Context, db *sql.DB, embedMigrations embed.FS, folder string) error {
+	provider, err := t.provider(ctx, db, embedMigrations, folder)
+	if err != nil {
+		t.log.Error(ctx, "Error creating migrations provider", "error", err)
 		return err
 	}
 
-	if err := goose.Up(db, folder); err != nil {
+	results, err := provider.Up(ctx)
+	if err != nil {
 		t.log.Error(ctx, "Error running migrations", "error", err)
 		return err
 	}
+	t.logResults(ctx, results)
 
 	return nil
 }
 
 func (t *tenantMigrations) upTo(ctx context.Context, db *sql.DB, embedMigrations embed.FS, folder string, version int64) error {
-	goose.SetBaseFS(embedMigrations)
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		t.log.Error(ctx, "Error setting dialect", "error", err)
+	provider, err := t.provider(ctx, db, embedMigrations, folder)
+	if err != nil {
+		t.log.Error(ctx, "Error creating migrations provider", "error", err)
 		return err
 	}
 
-	if err := goose.UpTo(db, folder, version); err != nil {
+	results, err := provider.UpTo(ctx, version)
+	if err != nil {
 		t.log.Error(ctx, "Error running migrations", "error", err)
 		return err
 	}
+	t.logResults(ctx, results)
 
 	return nil
 }
 
 func (t *tenantMigrations) down(ctx context.Context, db *sql.DB, embedMigrations embed.FS, folder string) error {
-	goose.SetBaseFS(embedMigrations)
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		t.log.Error(ctx, "Error setting dialect", "error", err)
+	provider, err := t.provider(ctx, db, embedMigrations, folder)
+	if err != nil {
+		t.log.Error(ctx, "Error creating migrations provider", "error", err)
 		return err
 	}
 
-	if err := goose.Down(db, folder); err != nil {
+	result, err := provider.Down(ctx)
+	if err != nil {
 		t.log.Error(ctx, "Error running migrations", "error", err)
 		return err
 	}
+	if result != nil {
+		t.logResults(ctx, []*goose.MigrationResult{result})
+	}
 
 	return nil
 }
 
 func (t *tenantMigrations) downTo(ctx context.Context, db *sql.DB, embedMigrations embed.FS, folder string, version int64) error {
-	goose.SetBaseFS(embedMigrations)
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		t.log.Error(ctx, "Error setting dialect", "error", err)
+	provider, err := t.provider(ctx, db, embedMigrations, folder)
+	if err != nil {
+		t.log.Error(ctx, "Error creating migrations provider", "error", err)
 		return err
 	}
 
-	if err := goose.DownTo(db, folder, version); err != nil {
+	results, err := provider.DownTo(ctx, version)
+	if err != nil {
 		t.log.Error(ctx, "Error running migrations", "error", err)
 		return err
 	}
+	t.logResults(ctx, results)
 
 	return nil
 }
 
+func (t *tenantMigrations) logResults(ctx context.Context, results []*goose.MigrationResult) {
+	for _, result := range results {
+		t.log.Info(ctx, "Migration applied", "version", result.Source.Version, "direction", result.Direction, "duration", result.Duration)
+	}
+}
+
 func NewTenantMigrations(
 	log common.Logger,
 	config *config.Config,
 	tenantManager *common.TenantConnectionManager,
+	audits repository.AuditRepository,
 	adminMigrationsFS embed.FS,
 	tenantMigrationsFS embed.FS,
-	commonMigrationsFS embed.FS) TenantMigrations {
+	commonMigrationsFS embed.FS,
+	seedsFS embed.FS) TenantMigrations {
 	return &tenantMigrations{
 		log:                log,
 		config:             config,
 		tenantManager:      tenantManager,
+		audits:             audits,
 		adminMigrationsFS:  adminMigrationsFS,
 		tenantMigrationsFS: tenantMigrationsFS,
 		commonMigrationsFS: commonMigrationsFS,
+		seedsFS:            seedsFS,
 	}
 }
 
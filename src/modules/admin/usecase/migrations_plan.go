@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
+)
+
+// Sentencias que eliminan datos y requieren confirmación al revertir
+var destructiveStatement = regexp.MustCompile(`(?i)\b(DROP\s+(TABLE|COLUMN|SCHEMA|DATABASE|TYPE)|TRUNCATE|DELETE\s+FROM)\b`)
+
+// Obtiene las sentencias de la sección Up o Down de un archivo de goose,
+// respetando los bloques StatementBegin/StatementEnd
+func parseStatements(content string, up bool) []string {
+	section := "-- +goose Down"
+	if up {
+		section = "-- +goose Up"
+	}
+
+	var statements []string
+	var buf strings.Builder
+	inSection, inBlock := false, false
+	flush := func() {
+		if statement := strings.TrimSpace(buf.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		buf.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "-- +goose ") {
+			annotation := strings.Fields(trimmed)[2]
+			switch annotation {
+			case "Up", "Down":
+				flush()
+				inSection = trimmed == section
+			case "StatementBegin":
+				inBlock = true
+			case "StatementEnd":
+				inBlock = false
+				if inSection {
+					flush()
+				}
+			}
+			continue
+		}
+		if !inSection || (!inBlock && (trimmed == "" || strings.HasPrefix(trimmed, "--"))) {
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	if inSection {
+		flush()
+	}
+	return statements
+}
+
+func isDestructive(statements []string) bool {
+	for _, statement := range statements {
+		if destructiveStatement.MatchString(statement) {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateTenantTo implements TenantMigrations.
+// Lleva las migraciones del tenant hasta la versión indicada en la dirección
+// indicada. Con dry run solo retorna el plan con las sentencias a ejecutar.
+func (t *tenantMigrations) MigrateTenantTo(ctx context.Context, tenantID uuid.UUID, direction string, target domain.DTOMigrationTarget) (*domain.DTOMigrationPlan, error) {
+	if strings.TrimSpace(target.Reason) == "" {
+		return nil, common.BadRequestError("a reason is required")
+	}
+	if direction != domain.MigrationDirectionUp && direction != domain.MigrationDirectionDown {
+		return nil, common.BadRequestError(fmt.Sprintf("invalid direction: %s", direction))
+	}
+	db, err := t.tenantMigrationsDB(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := t.plan(ctx, db.DB, direction, target.Version)
+	if err != nil {
+		return nil, err
+	}
+	plan.TenantID = tenantID
+	plan.DryRun = target.DryRun
+	if target.DryRun {
+		return plan, nil
+	}
+	if direction == domain.MigrationDirectionDown && plan.Destructive && !target.ConfirmDestructive {
+		return nil, common.ConflictError("rollback includes destructive migrations, confirm_destructive is required")
+	}
+
+	if len(plan.Migrations) > 0 {
+		if direction == domain.MigrationDirectionUp {
+			err = t.upTo(ctx, db.DB, t.tenantMigrationsFS, "migrations/tenants", target.Version)
+		} else {
+			// Las versiones de common comparten la tabla de versiones y no se
+			// revierten, por lo que se detiene antes de la última migración del plan
+			lowest := plan.Migrations[len(plan.Migrations)-1].Version
+			err = t.downTo(ctx, db.DB, t.tenantMigrationsFS, "migrations/tenants", max(target.Version, lowest-1))
+		}
+	}
+	t.audit(ctx, tenantID, direction, target.Reason, plan, err)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Calcula las migraciones a ejecutar y sus sentencias
+func (t *tenantMigrations) plan(ctx context.Context, db *sql.DB, direction string, version int64) (*domain.DTOMigrationPlan, error) {
+	provider, err := t.provider(ctx, db, t.tenantMigrationsFS, "migrations/tenants")
+	if err != nil {
+		return nil, err
+	}
+	current, err := provider.GetDBVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	up := direction == domain.MigrationDirectionUp
+	plan := &domain.DTOMigrationPlan{
+		Direction:   direction,
+		FromVersion: current,
+		ToVersion:   version,
+		Migrations:  []domain.DTOPlannedMigration{},
+	}
+	for _, status := range statuses {
+		selected := status.State == goose.StatePending && status.Source.Version <= version
+		if !up {
+			selected = status.State == goose.StateApplied && status.Source.Version > version
+		}
+		if !selected {
+			continue
+		}
+
+		migration := domain.DTOPlannedMigration{
+			Version: status.Source.Version,
+			Source:  status.Source.Path,
+		}
+		if status.Source.Type == goose.TypeSQL {
+			content, err := fs.ReadFile(t.tenantMigrationsFS, path.Join("migrations/tenants", status.Source.Path))
+			if err != nil {
+				return nil, err
+			}
+			migration.Statements = parseStatements(string(content), up)
+			migration.Destructive = isDestructive(migration.Statements)
+		} else {
+			// Las migraciones Go no se pueden inspeccionar, revertirlas requiere confirmación
+			migration.Destructive = !up
+		}
+		plan.Destructive = plan.Destructive || migration.Destructive
+		plan.Migrations = append(plan.Migrations, migration)
+	}
+
+	// Al revertir se ejecutan de la más reciente a la más antigua
+	if !up {
+		slices.Reverse(plan.Migrations)
+	}
+	return plan, nil
+}
+
+func (t *tenantMigrations) audit(ctx context.Context, tenantID uuid.UUID, direction, reason string, plan *domain.DTOMigrationPlan, cause error) {
+	details := map[string]any{"plan": plan}
+	if cause != nil {
+		details["error"] = cause.Error()
+	}
+	raw, err := json.Marshal(details)
+	if err != nil {
+		t.log.Error(ctx, "Error encoding audit log", "error", err)
+		return
+	}
+
+	action := domain.AuditMigrationUpTo
+	if direction == domain.MigrationDirectionDown {
+		action = domain.AuditMigrationDownTo
+	}
+	userID, _ := ctx.Value(t.tenantManager.UserIDKey).(uuid.UUID)
+	if err := t.audits.CreateAuditLog(ctx, domain.TableAuditLog{
+		ID:       uuid.New(),
+		Action:   action,
+		TenantID: tenantID,
+		UserID:   userID,
+		Reason:   reason,
+		Details:  raw,
+	}); err != nil {
+		t.log.Error(ctx, "Error creating audit log", "action", action, "tenant_id", tenantID, "error", err)
+	}
+}
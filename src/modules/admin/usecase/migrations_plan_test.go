@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"testing"
+)
+
+const testMigration = `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE clientes (
+    id SERIAL PRIMARY KEY
+);
+-- +goose StatementEnd
+CREATE INDEX idx_clientes ON clientes (id);
+
+-- +goose Down
+-- comentario fuera del bloque
+DROP INDEX idx_clientes;
+-- +goose StatementBegin
+DROP TABLE clientes;
+-- +goose StatementEnd
+`
+
+func Test_parseStatements(t *testing.T) {
+	up := parseStatements(testMigration, true)
+	if len(up) != 2 {
+		t.Fatalf("expected 2 up statements, got %d: %q", len(up), up)
+	}
+	if isDestructive(up) {
+		t.Errorf("up statements should not be destructive: %q", up)
+	}
+
+	down := parseStatements(testMigration, false)
+	if len(down) != 2 {
+		t.Fatalf("expected 2 down statements, got %d: %q", len(down), down)
+	}
+	if down[1] != "DROP TABLE clientes;" {
+		t.Errorf("unexpected down statement: %q", down[1])
+	}
+	if !isDestructive(down) {
+		t.Errorf("down statements should be destructive: %q", down)
+	}
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"api-test/src/database/postgres/gomigrations"
+	"api-test/src/modules/admin/domain"
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
+)
+
+type migrationSource struct {
+	fs     embed.FS
+	folder string
+}
+
+// GetAdminMigrationStatus implements TenantMigrations.
+func (t *tenantMigrations) GetAdminMigrationStatus(ctx context.Context) (*domain.DTOMigrationStatus, error) {
+	db, err := t.tenantManager.GetKosviTenantDB()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := t.status(ctx, db.DB, t.adminSources())
+	if err != nil {
+		return nil, err
+	}
+	status.TenantID = t.config.TenantID
+	status.Name = "KOSVI"
+	return status, nil
+}
+
+// GetTenantMigrationStatus implements TenantMigrations.
+func (t *tenantMigrations) GetTenantMigrationStatus(ctx context.Context, tenantID uuid.UUID) (*domain.DTOMigrationStatus, error) {
+	db, shared, err := t.migrationsDB(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := t.tenantSources()
+	if shared {
+		sources = append(sources, migrationSource{fs: t.tenantMigrationsFS, folder: "migrations/shared"})
+	}
+	status, err := t.status(ctx, db.DB, sources)
+	if err != nil {
+		return nil, err
+	}
+	status.TenantID = tenantID
+	if config, err := t.tenantManager.GetTenantConfig(tenantID); err == nil {
+		status.Name = config.Name
+	}
+	return status, nil
+}
+
+// GetFleetMigrationStatus implements TenantMigrations.
+// Agrupa los tenants por versión para validar que todos estén al día.
+func (t *tenantMigrations) GetFleetMigrationStatus(ctx context.Context) (*domain.DTOFleetMigrationStatus, error) {
+	tenantIDs := t.tenantManager.TenantIDs()
+	statuses := make([]domain.DTOMigrationStatus, len(tenantIDs))
+	sem := make(chan struct{}, max(t.config.Migrations.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, tenantID := range tenantIDs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			status, err := t.GetTenantMigrationStatus(ctx, tenantID)
+			if err != nil {
+				t.log.Error(ctx, "Error getting migration status", "tenant_id", tenantID, "error", err)
+				statuses[i] = domain.DTOMigrationStatus{TenantID: tenantID, Error: err.Error()}
+				return
+			}
+			statuses[i] = *status
+		}()
+	}
+	wg.Wait()
+	return fleetStatus(t.latestVersion(t.tenantSources()), statuses), nil
+}
+
+// Resume el estado de los tenants y los agrupa por versión, de la más reciente
+// a la más antigua
+func fleetStatus(latestVersion int64, statuses []domain.DTOMigrationStatus) *domain.DTOFleetMigrationStatus {
+	fleet := &domain.DTOFleetMigrationStatus{
+		LatestVersion: latestVersion,
+		Total:         len(statuses),
+		Tenants:       statuses,
+	}
+	versions := map[int64][]uuid.UUID{}
+	for _, status := range statuses {
+		switch {
+		case status.Error != "":
+			fleet.Failed++
+			continue
+		case status.Dirty:
+			fleet.Dirty++
+		case status.UpToDate:
+			fleet.UpToDate++
+		default:
+			fleet.Behind++
+		}
+		versions[status.CurrentVersion] = append(versions[status.CurrentVersion], status.TenantID)
+	}
+	for version, tenants := range versions {
+		fleet.Versions = append(fleet.Versions, domain.DTOMigrationVersionGroup{Version: version, Tenants: tenants})
+	}
+	slices.SortFunc(fleet.Versions, func(a, b domain.DTOMigrationVersionGroup) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+	return fleet
+}
+
+func (t *tenantMigrations) adminSources() []migrationSource {
+	return []migrationSource{
+		{fs: t.commonMigrationsFS, folder: "migrations/common"},
+		{fs: t.adminMigrationsFS, folder: "migrations/admin"},
+	}
+}
+
+func (t *tenantMigrations) tenantSources() []migrationSource {
+	return []migrationSource{
+		{fs: t.commonMigrationsFS, folder: "migrations/common"},
+		{fs: t.tenantMigrationsFS, folder: "migrations/tenants"},
+	}
+}
+
+// Combina el estado de todas las carpetas que comparten la tabla de versiones
+func (t *tenantMigrations) status(ctx context.Context, db *sql.DB, sources []migrationSource) (*domain.DTOMigrationStatus, error) {
+	status := &domain.DTOMigrationStatus{}
+	known := map[int64]bool{}
+	for _, source := range sources {
+		provider, err := t.provider(ctx, db, source.fs, source.folder)
+		if err != nil {
+			return nil, err
+		}
+		migrations, err := provider.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, migration := range migrations {
+			known[migration.Source.Version] = true
+			status.LatestVersion = max(status.LatestVersion, migration.Source.Version)
+			dto := domain.DTOMigration{
+				Version:   migration.Source.Version,
+				Source:    migration.Source.Path,
+				State:     string(migration.State),
+				AppliedAt: migration.AppliedAt,
+			}
+			if migration.State == goose.StateApplied {
+				status.Applied = append(status.Applied, dto)
+			} else {
+				status.Pending = append(status.Pending, dto)
+			}
+		}
+	}
+
+	// Versiones registradas en la base de datos que no existen en el código
+	rows, err := db.QueryContext(ctx, "SELECT version_id FROM goose_db_version WHERE version_id > 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		status.CurrentVersion = max(status.CurrentVersion, version)
+		if !known[version] {
+			status.Unknown = append(status.Unknown, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, pending := range status.Pending {
+		if pending.Version < status.CurrentVersion {
+			status.OutOfOrder = append(status.OutOfOrder, pending.Version)
+		}
+	}
+
+	byVersion := func(a, b domain.DTOMigration) int { return cmp.Compare(a.Version, b.Version) }
+	slices.SortFunc(status.Applied, byVersion)
+	slices.SortFunc(status.Pending, byVersion)
+	slices.Sort(status.Unknown)
+	slices.Sort(status.OutOfOrder)
+	status.Dirty = len(status.Unknown) > 0 || len(status.OutOfOrder) > 0
+	status.UpToDate = !status.Dirty && len(status.Pending) == 0
+	return status, nil
+}
+
+// Última versión disponible en el código
+func (t *tenantMigrations) latestVersion(sources []migrationSource) int64 {
+	var latest int64
+	for _, source := range sources {
+		entries, err := fs.ReadDir(source.fs, source.folder)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			version, err := goose.NumericComponent(entry.Name())
+			if err != nil {
+				continue
+			}
+			latest = max(latest, version)
+		}
+		for _, migration := range gomigrations.For(path.Base(source.folder)) {
+			latest = max(latest, migration.Version)
+		}
+	}
+	return latest
+}
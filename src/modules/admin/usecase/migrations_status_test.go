@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"api-test/src/database/postgres"
+	"api-test/src/modules/admin/domain"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_tenantMigrations_latestVersion(t *testing.T) {
+	m := newTestMigrations()
+	m.tenantMigrationsFS = postgres.Tenants
+	// Incluye las migraciones SQL y las registradas en Go
+	if latest := m.latestVersion(m.tenantSources()); latest != 20261019140000 {
+		t.Errorf("unexpected latest version %d", latest)
+	}
+}
+
+func Test_fleetStatus(t *testing.T) {
+	a, b, c, d, e := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	fleet := fleetStatus(3, []domain.DTOMigrationStatus{
+		{TenantID: a, CurrentVersion: 3, UpToDate: true},
+		{TenantID: b, CurrentVersion: 2},
+		{TenantID: c, CurrentVersion: 3, UpToDate: true},
+		{TenantID: d, CurrentVersion: 2, Dirty: true},
+		{TenantID: e, Error: "connection refused"},
+	})
+
+	if fleet.Total != 5 || fleet.UpToDate != 2 || fleet.Behind != 1 || fleet.Dirty != 1 || fleet.Failed != 1 {
+		t.Fatalf("unexpected counts: %+v", fleet)
+	}
+	// Los tenants con error no se agrupan por versión
+	if len(fleet.Versions) != 2 || fleet.Versions[0].Version != 3 || fleet.Versions[1].Version != 2 {
+		t.Fatalf("unexpected version groups: %+v", fleet.Versions)
+	}
+	if len(fleet.Versions[0].Tenants) != 2 || len(fleet.Versions[1].Tenants) != 2 {
+		t.Errorf("unexpected tenants per version: %+v", fleet.Versions)
+	}
+}
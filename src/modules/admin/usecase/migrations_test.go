@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func newTestMigrations(operators ...uuid.UUID) *tenantMigrations {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.Migrations.Concurrency = 1
+	conf.Migrations.TenantTimeout = 5
+	conf.Operators.UserIDs = operators
+	return &tenantMigrations{
+		log:           common.NewLogger(),
+		config:        conf,
+		tenantManager: common.NewTenantConnectionManager(conf),
+	}
+}
+
+// --migrate-tenants migra la flota al iniciar sin usuario en el contexto
+func Test_tenantMigrations_RunFleetMigrationsStartup(t *testing.T) {
+	m := newTestMigrations(uuid.New())
+	if err := m.tenantManager.RegisterTenant(&common.TenantConfig{TenantID: uuid.New(), Name: "nuevo", Provisioning: true}, nil); err != nil {
+		t.Fatal(err)
+	}
+	report, err := m.RunFleetMigrations(context.Background(), domain.DTOFleetMigration{ContinueOnError: true})
+	if err != nil {
+		t.Fatalf("startup fleet migrations should not require a user: %v", err)
+	}
+	if report.Total != 1 || report.Skipped != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func Test_tenantMigrations_RunFleetMigrationsReport(t *testing.T) {
+	m := newTestMigrations()
+	ctx := context.Background()
+
+	// Los tenants en aprovisionamiento los migra su job
+	provisioning := uuid.New()
+	if err := m.tenantManager.RegisterTenant(&common.TenantConfig{TenantID: provisioning, Name: "nuevo", Provisioning: true}, nil); err != nil {
+		t.Fatal(err)
+	}
+	unknown := uuid.New()
+
+	report, err := m.RunFleetMigrations(ctx, domain.DTOFleetMigration{TenantIDs: []uuid.UUID{provisioning, unknown, uuid.New()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 3 || report.Failed != 1 || report.Skipped != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Results[0].Name != "nuevo" || report.Results[0].Status != domain.MigrationResultSkipped {
+		t.Errorf("provisioning tenant should be skipped: %+v", report.Results[0])
+	}
+	if report.Results[1].Status != domain.MigrationResultFailed {
+		t.Errorf("unregistered tenant should fail: %+v", report.Results[1])
+	}
+	// Sin continue_on_error el fallo detiene los tenants pendientes
+	if report.Results[2].Status != domain.MigrationResultSkipped || report.Results[2].Error == "" {
+		t.Errorf("pending tenant should be cancelled: %+v", report.Results[2])
+	}
+}
+
+func Test_migrationLockID(t *testing.T) {
+	if migrationLockID("public") != lock.DefaultLockID {
+		t.Error("public should use the goose lock")
+	}
+	a, b := migrationLockID("tenant_a"), migrationLockID("tenant_b")
+	if a == b || a == lock.DefaultLockID || a != migrationLockID("tenant_a") {
+		t.Errorf("schemas should get distinct, stable locks: %d %d", a, b)
+	}
+}
+
+func Test_tenantMigrations_providerHonoursContext(t *testing.T) {
+	m := newTestMigrations()
+	db := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN("postgres://u:p@localhost:5432/db?sslmode=disable")))
+	defer db.Close()
+
+	// Cancelada por el timeout de la flota antes de consultar el schema
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.provider(ctx, db, m.tenantMigrationsFS, "."); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
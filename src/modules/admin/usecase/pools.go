@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetTenantPool implements Tenant.
+func (t *tenant) GetTenantPool(ctx context.Context, id uuid.UUID) (*domain.DTOPoolStats, error) {
+	table, err := t.getReadableTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, used, budget := t.tenantManager.PoolStats()
+	result := &domain.DTOPoolStats{
+		TenantID:   table.ID,
+		Overrides:  table.PoolSettings(),
+		BudgetUsed: used,
+		Budget:     budget,
+	}
+	for _, pool := range stats {
+		if pool.TenantID != table.ID {
+			continue
+		}
+		result.Open = true
+		result.Settings = domain.DTOPoolSettings{
+			MaxOpenConns:    pool.Settings.MaxOpenConns,
+			MaxIdleConns:    pool.Settings.MaxIdleConns,
+			ConnMaxLifetime: int(pool.Settings.ConnMaxLifetime / time.Second),
+		}
+		result.LastUsed = pool.LastUsed
+		result.OpenConnections = pool.Stats.OpenConnections
+		result.InUse = pool.Stats.InUse
+		result.Idle = pool.Stats.Idle
+		result.WaitCount = pool.Stats.WaitCount
+		result.WaitDurationMs = pool.Stats.WaitDuration.Milliseconds()
+		result.MaxIdleClosed = pool.Stats.MaxIdleClosed
+		result.MaxLifetimeClosed = pool.Stats.MaxLifetimeClosed
+	}
+	return result, nil
+}
+
+// UpdateTenantPool implements Tenant.
+// Guarda el tamaño del pool del tenant y lo aplica si la conexión está abierta.
+// Solo los operadores lo cambian, un tenant podría acaparar el presupuesto de
+// conexiones de los demás.
+func (t *tenant) UpdateTenantPool(ctx context.Context, id uuid.UUID, dto domain.DTOPoolSettings) (*domain.DTOPoolStats, error) {
+	table, err := t.getOperatedTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dto.MaxOpenConns > 0 && dto.MaxIdleConns > dto.MaxOpenConns {
+		return nil, common.BadRequestError("max_idle_conns cannot be greater than max_open_conns")
+	}
+
+	// Se valida contra el presupuesto antes de guardar
+	if _, err := t.tenantManager.GetTenantConfig(table.ID); err == nil {
+		if err := t.tenantManager.UpdatePool(table.ID, poolSettings(dto)); err != nil {
+			return nil, err
+		}
+	}
+
+	table.PoolMaxOpenConns = dto.MaxOpenConns
+	table.PoolMaxIdleConns = dto.MaxIdleConns
+	table.PoolConnMaxLifetime = dto.ConnMaxLifetime
+	table.UpdatedAt = time.Now()
+	if _, err := t.repo.UpdateTenant(ctx, *table, "pool_max_open_conns", "pool_max_idle_conns", "pool_conn_max_lifetime", "updated_at"); err != nil {
+		t.log.Error(ctx, "Error updating tenant pool", "tenant_id", table.ID, "error", err)
+		return nil, err
+	}
+	t.publish(ctx, table.ID, domain.TenantEventUpdated)
+	return t.GetTenantPool(ctx, id)
+}
+
+func poolSettings(dto domain.DTOPoolSettings) common.PoolSettings {
+	return common.PoolSettings{
+		MaxOpenConns:    dto.MaxOpenConns,
+		MaxIdleConns:    dto.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(dto.ConnMaxLifetime) * time.Second,
+	}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_tenant_UpdateTenantPoolRequiresOperator(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	operator := uuid.New()
+	uc.config.Operators.UserIDs = []uuid.UUID{operator}
+	ownerCtx, table := repo.addTenant(uuid.New())
+
+	// El dueño del tenant no puede tomar más conexiones del presupuesto
+	dto := domain.DTOPoolSettings{MaxOpenConns: 50, MaxIdleConns: 10}
+	if _, err := uc.UpdateTenantPool(ownerCtx, table.ID, dto); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 for the tenant owner, got %v", err)
+	}
+	if repo.tenants[table.ID].PoolMaxOpenConns != 0 {
+		t.Fatal("pool override saved for the tenant owner")
+	}
+
+	operatorCtx := context.WithValue(context.Background(), common.UserIDKey, operator)
+	stats, err := uc.UpdateTenantPool(operatorCtx, table.ID, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Overrides.MaxOpenConns != 50 || repo.tenants[table.ID].PoolMaxIdleConns != 10 {
+		t.Errorf("pool override not saved: %+v", stats.Overrides)
+	}
+
+	// El dueño puede consultar su pool
+	if _, err := uc.GetTenantPool(ownerCtx, table.ID); err != nil {
+		t.Errorf("owner should read the pool: %v", err)
+	}
+	if _, err := uc.UpdateTenantPool(operatorCtx, table.ID, domain.DTOPoolSettings{MaxOpenConns: 5, MaxIdleConns: 10}); common.StatusCode(err, 0) != http.StatusBadRequest {
+		t.Errorf("expected 400 with more idle than open connections, got %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tiempo que una instancia retiene un job sin renovarlo antes de que otra
+// pueda retomarlo. Mientras un paso se ejecuta el lease se renueva cada
+// jobLease/3, así los pasos largos (exportar, importar, clonar) no lo pierden.
+const jobLease = 2 * time.Minute
+
+func (t *tenant) GetJob(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error) {
+	job, err := t.getOwnedJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := job.ToDTO()
+	return &result, nil
+}
+
+func (t *tenant) RetryJob(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error) {
+	job, err := t.getOwnedJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusFailed {
+		return nil, common.ConflictError("only failed jobs can be retried")
+	}
+
+	// Se reanuda desde el último paso completado
+	job.Status = job.Step
+	job.LastError = ""
+	if _, err := t.jobs.UpdateJob(ctx, *job, "status", "last_error"); err != nil {
+		t.log.Error(ctx, "Error retrying job", "job_id", id, "error", err)
+		return nil, err
+	}
+	if job.CreatesTenant() {
+		if err := t.setTenantStatus(ctx, job.TenantID, domain.TenantStatusProvisioning); err != nil {
+			return nil, err
+		}
+	}
+	go t.runJob(context.Background(), job.ID)
+
+	result := job.ToDTO()
+	return &result, nil
+}
+
+func (t *tenant) CancelJob(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error) {
+	job, err := t.getOwnedJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return nil, common.ConflictError(fmt.Sprintf("job is already %s", job.Status))
+	}
+
+	// Tomar el job evita revertir mientras otra instancia lo ejecuta
+	owner := uuid.New()
+	claimed, err := t.jobs.ClaimJob(ctx, id, owner, jobLease)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, common.ConflictError("job is running, try again later")
+	}
+	defer t.release(ctx, id, owner)
+
+	if err := t.compensate(ctx, job); err != nil {
+		return nil, err
+	}
+	result := job.ToDTO()
+	return &result, nil
+}
+
+func (t *tenant) ResumeJobs(ctx context.Context) error {
+	jobs, err := t.jobs.GetResumableJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		t.log.Info(ctx, "Resuming job", "job_id", job.ID, "kind", job.Kind, "status", job.Status)
+		t.runJob(ctx, job.ID)
+	}
+	return nil
+}
+
+// Ejecuta el job desde su último paso completado hasta terminar o fallar
+func (t *tenant) runJob(ctx context.Context, id uuid.UUID) {
+	owner := uuid.New()
+	claimed, err := t.jobs.ClaimJob(ctx, id, owner, jobLease)
+	if err != nil {
+		t.log.Error(ctx, "Error claiming job", "job_id", id, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+	defer t.release(ctx, id, owner)
+	stepCtx, stop := t.keepLease(ctx, id, owner, jobLease/3)
+	defer stop()
+
+	job, err := t.jobs.GetJobByID(ctx, id)
+	if err != nil || job.ID == uuid.Nil {
+		t.log.Error(ctx, "Error getting job", "job_id", id, "error", err)
+		return
+	}
+	var step func(ctx context.Context, job *domain.TableJob) (string, error)
+	switch job.Kind {
+	case domain.JobKindProvision:
+		step = t.provisionStep
+	case domain.JobKindExport:
+		step = t.exportStep
+	case domain.JobKindImport:
+		step = t.importStep
+	case domain.JobKindClone:
+		step = t.cloneStep
+	default:
+		t.log.Error(ctx, "Unknown job kind", "job_id", id, "kind", job.Kind)
+		return
+	}
+
+	for job.Step != domain.JobStatusReady {
+		next, err := step(stepCtx, job)
+		// Otra instancia tomó el job, ella lo continúa
+		if stepCtx.Err() != nil && ctx.Err() == nil {
+			t.log.Warn(ctx, "Job lease lost, stopping", "job_id", id, "step", job.Step)
+			return
+		}
+		if err != nil {
+			t.fail(ctx, job, err)
+			return
+		}
+		// Solo quien tiene el lease avanza el job
+		if ok, err := t.jobs.RenewJob(ctx, id, owner, jobLease); err != nil || !ok {
+			t.log.Warn(ctx, "Job lease lost, stopping", "job_id", id, "step", next, "error", err)
+			return
+		}
+		job.Step = next
+		job.Status = next
+		if _, err := t.jobs.UpdateJob(ctx, *job, "status", "step"); err != nil {
+			t.log.Error(ctx, "Error updating job", "job_id", id, "error", err)
+			return
+		}
+		t.log.Info(ctx, "Job step completed", "job_id", id, "kind", job.Kind, "tenant_id", job.TenantID, "step", next)
+	}
+}
+
+// Aplica el paso correspondiente al estado actual y retorna el siguiente estado
+func (t *tenant) provisionStep(ctx context.Context, job *domain.TableJob) (string, error) {
+	table, err := t.repo.GetTenantByID(ctx, job.TenantID)
+	if err != nil {
+		return "", err
+	}
+	if table == nil || table.ID == uuid.Nil {
+		return "", fmt.Errorf("tenant %s not found", job.TenantID)
+	}
+
+	switch job.Step {
+	case domain.JobStatusPending:
+		pwd, err := t.password(ctx, *table)
+		if err != nil {
+			return "", err
+		}
+		table.PasswordPlaintext = pwd
+		if err := t.repo.CreateTenantDatabase(ctx, *table); err != nil {
+			return "", err
+		}
+		return domain.JobStatusDatabaseCreated, nil
+
+	case domain.JobStatusDatabaseCreated:
+		if err := t.register(ctx, *table, true); err != nil {
+			return "", err
+		}
+		if err := t.migrations.RunAllMigrations(ctx, table.ID); err != nil {
+			return "", err
+		}
+		return domain.JobStatusMigrated, nil
+
+	case domain.JobStatusMigrated:
+		if _, err := t.migrations.RunSeeds(ctx, table.ID); err != nil {
+			return "", err
+		}
+		return domain.JobStatusSeeded, nil
+
+	case domain.JobStatusSeeded:
+		return t.activate(ctx, *table)
+	}
+
+	return "", fmt.Errorf("unknown provisioning step: %s", job.Step)
+}
+
+// Último paso de la creación del tenant: lo marca listo y habilita su conexión
+func (t *tenant) activate(ctx context.Context, table domain.TableTenant) (string, error) {
+	if err := t.setTenantStatus(ctx, table.ID, domain.TenantStatusReady); err != nil {
+		return "", err
+	}
+	// Si la instancia se reinició el tenant puede no estar registrado
+	if err := t.register(ctx, table, false); err != nil {
+		return "", err
+	}
+	if err := t.tenantManager.UpdateConfig(table.ID, func(config *common.TenantConfig) { config.Provisioning = false }); err != nil {
+		return "", err
+	}
+	t.publish(ctx, table.ID, domain.TenantEventCreated)
+	return domain.JobStatusReady, nil
+}
+
+// Marca el job como fallido y revierte el tenant creado al agotar los intentos
+func (t *tenant) fail(ctx context.Context, job *domain.TableJob, cause error) {
+	t.log.Error(ctx, "Job step failed", "job_id", job.ID, "kind", job.Kind, "tenant_id", job.TenantID, "step", job.Step, "attempt", job.Attempts, "error", cause)
+
+	job.Status = domain.JobStatusFailed
+	job.LastError = cause.Error()
+	if _, err := t.jobs.UpdateJob(ctx, *job, "status", "last_error"); err != nil {
+		t.log.Error(ctx, "Error updating job", "job_id", job.ID, "error", err)
+	}
+	// Las exportaciones e importaciones fallidas no afectan al tenant
+	if !job.CreatesTenant() {
+		return
+	}
+	if err := t.setTenantStatus(ctx, job.TenantID, domain.TenantStatusFailed); err != nil {
+		t.log.Error(ctx, "Error updating tenant status", "tenant_id", job.TenantID, "error", err)
+	}
+
+	if job.Attempts >= t.config.TenantLifecycle.ProvisioningMaxAttempts {
+		t.log.Warn(ctx, "Job attempts exhausted, rolling back", "job_id", job.ID, "tenant_id", job.TenantID)
+		if err := t.compensate(ctx, job); err != nil {
+			t.log.Error(ctx, "Error rolling back provisioning", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// Revierte todo lo creado por el aprovisionamiento o la clonación: conexión,
+// base de datos, usuario y los registros en tenants.tenants y user_tenants.
+// En las exportaciones e importaciones solo elimina su archivo.
+func (t *tenant) compensate(ctx context.Context, job *domain.TableJob) error {
+	t.removeArchive(ctx, job.ID)
+	if !job.CreatesTenant() {
+		job.Status = domain.JobStatusCancelled
+		_, err := t.jobs.UpdateJob(ctx, *job, "status")
+		return err
+	}
+
+	var errs []error
+	if err := t.tenantManager.RemoveTenant(job.TenantID); err != nil {
+		errs = append(errs, err)
+	}
+	table, err := t.repo.GetTenantByID(ctx, job.TenantID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if table != nil && table.ID != uuid.Nil {
+		if err := t.repo.DropTenantDatabase(ctx, *table); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// Devuelve los usuarios que createTenantRecord contó en la cuota del plan
+	members, err := t.repo.CountUserTenants(ctx, job.TenantID)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.repo.DeleteUserTenants(ctx, job.TenantID); err != nil {
+		errs = append(errs, err)
+	} else {
+		t.Release(ctx, job.TenantID, common.ResourceUsers, members)
+	}
+	if err := t.repo.DeleteTenant(ctx, job.TenantID); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	job.Status = domain.JobStatusCancelled
+	if _, err := t.jobs.UpdateJob(ctx, *job, "status"); err != nil {
+		return err
+	}
+	t.log.Info(ctx, "Provisioning rolled back", "job_id", job.ID, "tenant_id", job.TenantID)
+	return nil
+}
+
+func (t *tenant) release(ctx context.Context, id uuid.UUID, owner uuid.UUID) {
+	if err := t.jobs.ReleaseJob(ctx, id, owner); err != nil {
+		t.log.Error(ctx, "Error releasing job", "job_id", id, "error", err)
+	}
+}
+
+// Renueva el lease del job mientras se ejecuta. El contexto retornado se
+// cancela si otra instancia tomó el job o si no se pudo renovar antes de que
+// venciera, para que el paso en curso no siga en paralelo con ella.
+func (t *tenant) keepLease(ctx context.Context, id uuid.UUID, owner uuid.UUID, every time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		renewed := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			ok, err := t.jobs.RenewJob(ctx, id, owner, jobLease)
+			if err == nil && ok {
+				renewed = time.Now()
+				continue
+			}
+			if err != nil && time.Since(renewed) < jobLease*2/3 {
+				t.log.Warn(ctx, "Error renewing job lease", "job_id", id, "error", err)
+				continue
+			}
+			t.log.Error(ctx, "Job lease lost", "job_id", id, "error", err)
+			cancel()
+			return
+		}
+	}()
+	return ctx, cancel
+}
+
+func (t *tenant) setTenantStatus(ctx context.Context, id uuid.UUID, status string) error {
+	_, err := t.repo.UpdateTenant(ctx, domain.TableTenant{ID: id, Status: status, UpdatedAt: time.Now()}, "status", "updated_at")
+	if err != nil {
+		t.log.Error(ctx, "Error updating tenant status", "tenant_id", id, "status", status, "error", err)
+	}
+	return err
+}
+
+// Obtiene el job validando que pertenezca al usuario del contexto
+func (t *tenant) getOwnedJob(ctx context.Context, id uuid.UUID) (*domain.TableJob, error) {
+	userID, ok := ctx.Value(t.tenantManager.UserIDKey).(uuid.UUID)
+	if !ok {
+		return nil, common.UnauthorizedError("user not found in context")
+	}
+
+	job, err := t.jobs.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.ID == uuid.Nil {
+		return nil, common.NotFoundError("job not found")
+	}
+	if job.UserID != userID {
+		return nil, common.ForbiddenError("job no autorizado")
+	}
+	return job, nil
+}
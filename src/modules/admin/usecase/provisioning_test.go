@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lease de un job en memoria, los demás métodos no se usan
+type leaseJobRepository struct {
+	repository.JobRepository
+	mu     sync.Mutex
+	owner  uuid.UUID
+	renews int
+}
+
+func (r *leaseJobRepository) RenewJob(ctx context.Context, id uuid.UUID, owner uuid.UUID, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renews++
+	return r.owner == owner, nil
+}
+
+func (r *leaseJobRepository) UpdateJob(ctx context.Context, job domain.TableJob, columns ...string) (*domain.TableJob, error) {
+	return &job, nil
+}
+
+func (r *leaseJobRepository) steal() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.owner = uuid.New()
+}
+
+func Test_tenant_keepLease(t *testing.T) {
+	owner := uuid.New()
+	jobs := &leaseJobRepository{owner: owner}
+	uc := &tenant{log: common.NewLogger(), jobs: jobs}
+
+	ctx, stop := uc.keepLease(context.Background(), uuid.New(), owner, 5*time.Millisecond)
+	defer stop()
+	time.Sleep(30 * time.Millisecond)
+	if ctx.Err() != nil {
+		t.Fatal("context cancelled while the lease is renewed")
+	}
+
+	// Otra instancia toma el job, el paso en curso debe detenerse
+	jobs.steal()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not cancelled after losing the lease")
+	}
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	if jobs.renews < 2 {
+		t.Errorf("expected the lease to be renewed, got %d renewals", jobs.renews)
+	}
+}
+
+func Test_tenant_compensateReleasesUsers(t *testing.T) {
+	uc, repo, plans, _, tenantID := newTestQuotas("free")
+	uc.jobs = &leaseJobRepository{}
+	key := usageKey{tenantID, common.ResourceUsers, domain.UsagePeriodTotal}
+	if err := uc.Consume(context.Background(), tenantID, common.ResourceUsers, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// El aprovisionamiento falla y se revierte el tenant
+	job := &domain.TableJob{ID: uuid.New(), Kind: domain.JobKindProvision, TenantID: tenantID}
+	if err := uc.compensate(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != domain.JobStatusCancelled {
+		t.Errorf("job not cancelled: %s", job.Status)
+	}
+	if used := plans.usage[key]; used != 0 {
+		t.Errorf("users quota not released, used %d", used)
+	}
+	if _, ok := repo.tenants[tenantID]; ok {
+		t.Error("tenant not deleted")
+	}
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Consume implements common.QuotaEnforcer.
+// Sin plan no se cuenta el uso. Si el uso no se puede consultar la petición
+// continúa, los límites no deben dejar a los tenants sin servicio.
+func (t *tenant) Consume(ctx context.Context, tenantID uuid.UUID, resource string, n int64) error {
+	plan, err := t.tenantPlan(ctx, tenantID)
+	if err != nil {
+		t.log.Warn(ctx, "Error getting tenant plan, quota not enforced", "tenant_id", tenantID, "resource", resource, "error", err)
+		return nil
+	}
+	if plan == nil {
+		return nil
+	}
+	limit := plan.Limits[resource]
+	ok, err := t.plans.ConsumeUsage(ctx, tenantID, resource, usagePeriod(resource, time.Now()), n, limit)
+	if err != nil {
+		t.log.Warn(ctx, "Error updating usage, quota not enforced", "tenant_id", tenantID, "resource", resource, "error", err)
+		return nil
+	}
+	if ok {
+		return nil
+	}
+	if resource == common.ResourceAPICalls {
+		return common.TooManyRequestsError(fmt.Sprintf("monthly limit of %d api calls reached for plan %s", limit, plan.Name))
+	}
+	return common.QuotaExceededError(fmt.Sprintf("limit of %d %s reached for plan %s", limit, resource, plan.Name))
+}
+
+// Release implements common.QuotaEnforcer.
+func (t *tenant) Release(ctx context.Context, tenantID uuid.UUID, resource string, n int64) {
+	plan, err := t.tenantPlan(ctx, tenantID)
+	if err != nil || plan == nil {
+		return
+	}
+	if err := t.plans.ReleaseUsage(ctx, tenantID, resource, usagePeriod(resource, time.Now()), n); err != nil {
+		t.log.Warn(ctx, "Error releasing usage", "tenant_id", tenantID, "resource", resource, "error", err)
+	}
+}
+
+// ListPlans implements Tenant.
+func (t *tenant) ListPlans(ctx context.Context) ([]domain.DTOPlan, error) {
+	plans, err := t.plans.GetPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.DTOPlan, len(plans))
+	for i, plan := range plans {
+		result[i] = plan.ToDTO()
+	}
+	return result, nil
+}
+
+// UpdateTenantPlan implements Tenant.
+// Al asignar un plan se recalcula el uso para aplicar los límites desde el
+// estado real del tenant. Los planes se venden, solo los asignan los operadores.
+func (t *tenant) UpdateTenantPlan(ctx context.Context, id uuid.UUID, dto domain.DTOAssignPlan) (*domain.DTOTenant, error) {
+	table, err := t.getOperatedTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var plan *domain.TablePlan
+	if dto.PlanID != "" {
+		if plan, err = t.getPlan(ctx, dto.PlanID); err != nil {
+			return nil, err
+		}
+	}
+
+	table.PlanID = dto.PlanID
+	table.UpdatedAt = time.Now()
+	if _, err := t.repo.UpdateTenant(ctx, *table, "plan_id", "updated_at"); err != nil {
+		t.log.Error(ctx, "Error updating tenant plan", "tenant_id", id, "error", err)
+		return nil, err
+	}
+	t.planCache.invalidate(id)
+	if err := t.tenantManager.UpdateConfig(id, func(config *common.TenantConfig) { config.Plan = dto.PlanID }); err != nil {
+		t.log.Warn(ctx, "Tenant not registered in connection manager", "tenant_id", id)
+	}
+	t.publish(ctx, id, domain.TenantEventUpdated)
+	if plan != nil {
+		t.recountUsage(ctx, *table, plan)
+	}
+
+	result := table.ToDTO()
+	return &result, nil
+}
+
+// GetTenantUsage implements Tenant.
+// Retorna el uso del mes de api_calls y el total de los demás recursos.
+func (t *tenant) GetTenantUsage(ctx context.Context, id uuid.UUID) (*domain.DTOUsage, error) {
+	table, err := t.getReadableTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := domain.DTOUsage{TenantID: id, Resources: []domain.DTOResourceUsage{}}
+	var plan *domain.TablePlan
+	if table.PlanID != "" {
+		if plan, err = t.getPlan(ctx, table.PlanID); err != nil {
+			return nil, err
+		}
+		dto := plan.ToDTO()
+		result.Plan = &dto
+		t.recountUsage(ctx, *table, plan)
+	}
+
+	now := time.Now()
+	usage, err := t.plans.GetUsage(ctx, id, domain.UsagePeriodTotal, usagePeriod(common.ResourceAPICalls, now))
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]int64{}
+	for _, u := range usage {
+		used[u.Resource] = u.Used
+	}
+	resources := slices.Collect(maps.Keys(used))
+	if plan != nil {
+		for resource := range plan.Limits {
+			if !slices.Contains(resources, resource) {
+				resources = append(resources, resource)
+			}
+		}
+	}
+	slices.Sort(resources)
+	for _, resource := range resources {
+		item := domain.DTOResourceUsage{Resource: resource, Period: usagePeriod(resource, now), Used: used[resource]}
+		if plan != nil {
+			item.Limit = plan.Limits[resource]
+		}
+		result.Resources = append(result.Resources, item)
+	}
+	return &result, nil
+}
+
+// Recalcula el total de filas de los recursos del plan, corrige el uso que
+// no se descontó por transacciones revertidas o filas creadas sin límites
+func (t *tenant) recountUsage(ctx context.Context, table domain.TableTenant, plan *domain.TablePlan) {
+	if table.Status != domain.TenantStatusReady {
+		return
+	}
+	if _, ok := plan.Limits[common.ResourceUsers]; ok {
+		t.recountUsers(ctx, table.ID)
+	}
+	var resources []string
+	for resource := range plan.Limits {
+		if resource != common.ResourceAPICalls && resource != common.ResourceUsers {
+			resources = append(resources, resource)
+		}
+	}
+	if len(resources) == 0 {
+		return
+	}
+	counts, err := t.data.CountRows(ctx, table.ID, resources)
+	if err != nil {
+		t.log.Warn(ctx, "Error counting tenant rows", "tenant_id", table.ID, "error", err)
+		return
+	}
+	for resource, count := range counts {
+		if err := t.plans.SetUsage(ctx, table.ID, resource, domain.UsagePeriodTotal, count); err != nil {
+			t.log.Warn(ctx, "Error updating usage", "tenant_id", table.ID, "resource", resource, "error", err)
+		}
+	}
+}
+
+// Los usuarios se cuentan en tenants.user_tenants, no en las tablas del tenant
+func (t *tenant) recountUsers(ctx context.Context, tenantID uuid.UUID) {
+	count, err := t.repo.CountUserTenants(ctx, tenantID)
+	if err != nil {
+		t.log.Warn(ctx, "Error counting tenant users", "tenant_id", tenantID, "error", err)
+		return
+	}
+	if err := t.plans.SetUsage(ctx, tenantID, common.ResourceUsers, domain.UsagePeriodTotal, count); err != nil {
+		t.log.Warn(ctx, "Error updating usage", "tenant_id", tenantID, "resource", common.ResourceUsers, "error", err)
+	}
+}
+
+func (t *tenant) getPlan(ctx context.Context, id string) (*domain.TablePlan, error) {
+	plan, err := t.plans.GetPlanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || plan.ID == "" {
+		return nil, common.NotFoundError(fmt.Sprintf("plan %s not found", id))
+	}
+	return plan, nil
+}
+
+// Plan del tenant desde la caché, nil si no tiene plan
+func (t *tenant) tenantPlan(ctx context.Context, tenantID uuid.UUID) (*domain.TablePlan, error) {
+	if plan, ok := t.planCache.get(tenantID); ok {
+		return plan, nil
+	}
+
+	table, err := t.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	var plan *domain.TablePlan
+	if table != nil && table.PlanID != "" {
+		if plan, err = t.getPlan(ctx, table.PlanID); err != nil {
+			return nil, err
+		}
+	}
+	t.planCache.set(tenantID, plan, time.Duration(t.config.Quotas.CacheTTL)*time.Second)
+	return plan, nil
+}
+
+// api_calls se cuenta por mes, los demás recursos son totales
+func usagePeriod(resource string, now time.Time) string {
+	if resource == common.ResourceAPICalls {
+		return now.UTC().Format("2006-01")
+	}
+	return domain.UsagePeriodTotal
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type usageKey struct {
+	tenantID uuid.UUID
+	resource string
+	period   string
+}
+
+// Planes y uso en memoria, err hace fallar las operaciones de uso
+type memoryPlanRepository struct {
+	repository.PlanRepository
+	mu    sync.Mutex
+	plans map[string]domain.TablePlan
+	usage map[usageKey]int64
+	err   error
+}
+
+func newMemoryPlanRepository() *memoryPlanRepository {
+	return &memoryPlanRepository{
+		plans: map[string]domain.TablePlan{
+			"free":       {ID: "free", Name: "Free", Limits: map[string]int64{"productos": 2, common.ResourceAPICalls: 1, common.ResourceUsers: 1}},
+			"enterprise": {ID: "enterprise", Name: "Enterprise", Limits: map[string]int64{}},
+		},
+		usage: map[usageKey]int64{},
+	}
+}
+
+func (r *memoryPlanRepository) GetPlanByID(ctx context.Context, id string) (*domain.TablePlan, error) {
+	plan := r.plans[id]
+	return &plan, nil
+}
+
+func (r *memoryPlanRepository) ConsumeUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, n, limit int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return false, r.err
+	}
+	key := usageKey{tenantID, resource, period}
+	if limit > 0 && r.usage[key]+n > limit {
+		return false, nil
+	}
+	r.usage[key] += n
+	return true, nil
+}
+
+func (r *memoryPlanRepository) ReleaseUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, n int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage[usageKey{tenantID, resource, period}] = max(0, r.usage[usageKey{tenantID, resource, period}]-n)
+	return r.err
+}
+
+func (r *memoryPlanRepository) SetUsage(ctx context.Context, tenantID uuid.UUID, resource, period string, used int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage[usageKey{tenantID, resource, period}] = used
+	return r.err
+}
+
+func (r *memoryPlanRepository) GetUsage(ctx context.Context, tenantID uuid.UUID, periods ...string) ([]domain.TableUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []domain.TableUsage
+	for key, used := range r.usage {
+		for _, period := range periods {
+			if key.tenantID == tenantID && key.period == period {
+				result = append(result, domain.TableUsage{TenantID: tenantID, Resource: key.resource, Period: period, Used: used})
+			}
+		}
+	}
+	return result, nil
+}
+
+// Cuenta las filas de las tablas del tenant, todas con la misma cantidad
+type countingDataRepository struct {
+	repository.TenantDataRepository
+	rows int64
+}
+
+func (r *countingDataRepository) CountRows(ctx context.Context, tenantID uuid.UUID, tables []string) (map[string]int64, error) {
+	counts := map[string]int64{}
+	for _, table := range tables {
+		counts[table] = r.rows
+	}
+	return counts, nil
+}
+
+// Tenant con el plan indicado y el contexto de su dueño
+func newTestQuotas(planID string) (*tenant, *memoryTenantRepository, *memoryPlanRepository, context.Context, uuid.UUID) {
+	uc, repo, _ := newTestTenant()
+	plans := newMemoryPlanRepository()
+	uc.plans = plans
+	uc.data = &countingDataRepository{}
+	ownerCtx, table := repo.addTenant(uuid.New())
+	table.PlanID = planID
+	repo.tenants[table.ID] = table
+	return uc, repo, plans, ownerCtx, table.ID
+}
+
+func Test_usagePeriod(t *testing.T) {
+	now := time.Date(2026, 10, 31, 23, 30, 0, 0, time.FixedZone("COT", -5*3600))
+	// El mes se toma en UTC
+	if got := usagePeriod(common.ResourceAPICalls, now); got != "2026-11" {
+		t.Errorf("usagePeriod(api_calls) = %s", got)
+	}
+	for _, resource := range []string{"productos", common.ResourceUsers} {
+		if got := usagePeriod(resource, now); got != domain.UsagePeriodTotal {
+			t.Errorf("usagePeriod(%s) = %s", resource, got)
+		}
+	}
+}
+
+func Test_tenant_ConsumeRelease(t *testing.T) {
+	uc, _, plans, _, tenantID := newTestQuotas("free")
+	ctx := context.Background()
+
+	for range 2 {
+		if err := uc.Consume(ctx, tenantID, "productos", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := uc.Consume(ctx, tenantID, "productos", 1); common.StatusCode(err, 0) != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 over the rows limit, got %v", err)
+	}
+	uc.Release(ctx, tenantID, "productos", 1)
+	if used := plans.usage[usageKey{tenantID, "productos", domain.UsagePeriodTotal}]; used != 1 {
+		t.Errorf("expected 1 product after release, got %d", used)
+	}
+	if err := uc.Consume(ctx, tenantID, "productos", 1); err != nil {
+		t.Errorf("release should free a unit: %v", err)
+	}
+
+	// Las llamadas a la API se limitan por mes con 429
+	if err := uc.Consume(ctx, tenantID, common.ResourceAPICalls, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.Consume(ctx, tenantID, common.ResourceAPICalls, 1); common.StatusCode(err, 0) != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the api calls limit, got %v", err)
+	}
+	if used := plans.usage[usageKey{tenantID, common.ResourceAPICalls, usagePeriod(common.ResourceAPICalls, time.Now())}]; used != 1 {
+		t.Errorf("expected 1 api call this month, got %d", used)
+	}
+
+	// Sin límite para el recurso se cuenta el uso sin fallar
+	for range 5 {
+		if err := uc.Consume(ctx, tenantID, "clientes", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_tenant_ConsumeFailsOpen(t *testing.T) {
+	uc, _, plans, _, tenantID := newTestQuotas("")
+	ctx := context.Background()
+
+	// Sin plan no se cuenta el uso
+	if err := uc.Consume(ctx, tenantID, "productos", 100); err != nil || len(plans.usage) != 0 {
+		t.Fatalf("tenant without plan should not be limited: %v %v", err, plans.usage)
+	}
+
+	uc, _, plans, _, tenantID = newTestQuotas("free")
+	plans.err = errors.New("connection refused")
+	for range 3 {
+		if err := uc.Consume(ctx, tenantID, "productos", 1); err != nil {
+			t.Fatalf("usage errors should not block requests: %v", err)
+		}
+	}
+}
+
+func Test_tenant_UpdateTenantPlanRequiresOperator(t *testing.T) {
+	uc, repo, _, ownerCtx, tenantID := newTestQuotas("free")
+	operator := uuid.New()
+	uc.config.Operators.UserIDs = []uuid.UUID{operator}
+
+	// El dueño no puede asignarse un plan sin pagarlo
+	if _, err := uc.UpdateTenantPlan(ownerCtx, tenantID, domain.DTOAssignPlan{PlanID: "enterprise"}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 for the tenant owner, got %v", err)
+	}
+	if repo.tenants[tenantID].PlanID != "free" {
+		t.Fatal("plan changed by the tenant owner")
+	}
+
+	operatorCtx := context.WithValue(context.Background(), common.UserIDKey, operator)
+	if _, err := uc.UpdateTenantPlan(operatorCtx, tenantID, domain.DTOAssignPlan{PlanID: "enterprise"}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.tenants[tenantID].PlanID != "enterprise" {
+		t.Error("plan not assigned by the operator")
+	}
+}
+
+func Test_tenant_GetTenantUsageCountsUsers(t *testing.T) {
+	uc, _, _, ownerCtx, tenantID := newTestQuotas("free")
+
+	usage, err := uc.GetTenantUsage(ownerCtx, tenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, resource := range usage.Resources {
+		if resource.Resource == common.ResourceUsers {
+			found = true
+			if resource.Used != 1 || resource.Limit != 1 || resource.Period != domain.UsagePeriodTotal {
+				t.Errorf("unexpected users usage: %+v", resource)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("users missing from usage: %+v", usage.Resources)
+	}
+
+	// Con el límite alcanzado no se asocian más usuarios
+	if err := uc.Consume(context.Background(), tenantID, common.ResourceUsers, 1); common.StatusCode(err, 0) != http.StatusPaymentRequired {
+		t.Errorf("expected 402 over the users limit, got %v", err)
+	}
+}
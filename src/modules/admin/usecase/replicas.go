@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdateTenantReplicas implements Tenant.
+// Guarda las réplicas de lectura del tenant y las aplica sin cerrar su conexión.
+// Las réplicas usan las credenciales de la principal, por eso solo los
+// operadores las cambian y solo a hosts de TENANT_REPLICA_HOSTS.
+func (t *tenant) UpdateTenantReplicas(ctx context.Context, id uuid.UUID, dto domain.DTOReplicas) (*domain.DTOTenant, error) {
+	table, err := t.getOperatedTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range dto.Replicas {
+		if !t.tenantManager.ReplicaAllowed(host) {
+			return nil, common.BadRequestError(fmt.Sprintf("replica host not allowed: %s", host))
+		}
+	}
+	if table.Status != domain.TenantStatusReady {
+		return nil, common.ConflictError("tenant is not ready")
+	}
+
+	table.DBReplicas = dto.Replicas
+	table.UpdatedAt = time.Now()
+	if _, err := t.repo.UpdateTenant(ctx, *table, "db_replicas", "updated_at"); err != nil {
+		t.log.Error(ctx, "Error updating tenant replicas", "tenant_id", id, "error", err)
+		return nil, err
+	}
+	if err := t.tenantManager.UpdateReplicas(id, dto.Replicas); err != nil {
+		t.log.Warn(ctx, "Tenant not registered in connection manager", "tenant_id", id)
+	}
+	t.publish(ctx, id, domain.TenantEventUpdated)
+
+	result := table.ToDTO()
+	return &result, nil
+}
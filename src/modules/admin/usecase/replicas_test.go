@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_tenant_UpdateTenantReplicas(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	operator := uuid.New()
+	uc.config.Operators.UserIDs = []uuid.UUID{operator}
+	uc.config.Replicas.AllowedHosts = []string{"replica-1:5432"}
+	ownerCtx, table := repo.addTenant(uuid.New())
+	operatorCtx := context.WithValue(context.Background(), common.UserIDKey, operator)
+
+	// El dueño del tenant no elige a qué host se envían sus credenciales
+	if _, err := uc.UpdateTenantReplicas(ownerCtx, table.ID, domain.DTOReplicas{Replicas: []string{"replica-1:5432"}}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 for the tenant owner, got %v", err)
+	}
+	if _, err := uc.UpdateTenantReplicas(operatorCtx, table.ID, domain.DTOReplicas{Replicas: []string{"replica-1:5432", "10.0.0.1:6379"}}); common.StatusCode(err, 0) != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a host outside the allowlist, got %v", err)
+	}
+	if len(repo.tenants[table.ID].DBReplicas) != 0 {
+		t.Fatal("replicas saved after a rejected update")
+	}
+
+	if _, err := uc.UpdateTenantReplicas(operatorCtx, table.ID, domain.DTOReplicas{Replicas: []string{"replica-1:5432"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(repo.tenants[table.ID].DBReplicas, []string{"replica-1:5432"}) {
+		t.Errorf("replicas not saved: %v", repo.tenants[table.ID].DBReplicas)
+	}
+}
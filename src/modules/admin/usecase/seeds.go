@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// RunSeeds implements TenantMigrations.
+// Aplica los seeds base y los del ambiente actual que no estén registrados en
+// seed_history. Cada seed se ejecuta una sola vez, en su propia transacción.
+func (t *tenantMigrations) RunSeeds(ctx context.Context, tenantID uuid.UUID) ([]string, error) {
+	db, err := t.getDB(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	// Con tablas compartidas los seeds se aplican con el usuario del tenant
+	// y las políticas RLS asignan las filas al tenant
+	rowLevelTenant := uuid.Nil
+	if config, err := t.tenantManager.GetTenantConfig(tenantID); err == nil && config.RowLevelSecurity {
+		rowLevelTenant = tenantID
+	}
+
+	names, err := seedFiles(t.seedsFS, t.config.Environment.Name)
+	if err != nil {
+		return nil, err
+	}
+	applied := []string{}
+	for _, name := range names {
+		content, err := fs.ReadFile(t.seedsFS, path.Join("seeds", name))
+		if err != nil {
+			return applied, err
+		}
+		ok, err := t.seed(ctx, db, rowLevelTenant, name, content)
+		if err != nil {
+			t.log.Error(ctx, "Error running seed", "tenant_id", tenantID, "seed", name, "error", err)
+			return applied, err
+		}
+		if ok {
+			t.log.Info(ctx, "Seed applied", "tenant_id", tenantID, "seed", name)
+			applied = append(applied, name)
+		}
+	}
+	return applied, nil
+}
+
+// Archivos de seeds a aplicar en orden: primero los base y luego los del
+// ambiente, cada grupo ordenado por nombre
+func seedFiles(fsys fs.FS, environment string) ([]string, error) {
+	names := []string{}
+	for _, set := range []string{"base", environment} {
+		entries, err := fs.ReadDir(fsys, path.Join("seeds", set))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			names = append(names, path.Join(set, entry.Name()))
+		}
+	}
+	return names, nil
+}
+
+// Ejecuta el seed si no fue aplicado antes, retorna true si se ejecutó
+func (t *tenantMigrations) seed(ctx context.Context, db *bun.DB, rowLevelTenant uuid.UUID, name string, content []byte) (bool, error) {
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	executed := false
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if rowLevelTenant != uuid.Nil {
+			if err := common.SetTenant(ctx, tx, rowLevelTenant); err != nil {
+				return err
+			}
+		}
+		// Evita que dos instancias apliquen el mismo seed a la vez
+		if _, err := tx.Tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('seed_history'))"); err != nil {
+			return err
+		}
+
+		var existing string
+		err := tx.Tx.QueryRowContext(ctx, "SELECT checksum FROM seed_history WHERE name = $1", name).Scan(&existing)
+		if err == nil {
+			if existing != checksum {
+				t.log.Warn(ctx, "Seed changed after being applied, it will not run again", "seed", name)
+			}
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		// Se usa la transacción de database/sql para que bun no interprete los '?' del archivo
+		if _, err := tx.Tx.ExecContext(ctx, string(content)); err != nil {
+			return err
+		}
+		if _, err := tx.Tx.ExecContext(ctx, "INSERT INTO seed_history (name, checksum) VALUES ($1, $2)", name, checksum); err != nil {
+			return err
+		}
+		executed = true
+		return nil
+	})
+	return executed, err
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"api-test/src/database/postgres"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_seedFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"seeds/base/0002_impuestos.sql":     {},
+		"seeds/base/0001_categorias.sql":    {},
+		"seeds/base/README.md":              {},
+		"seeds/development/0001_demo.sql":   {},
+		"seeds/production/0001_precios.sql": {},
+		"seeds/development/nested/0001.sql": {},
+	}
+
+	names, err := seedFiles(fsys, "development")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"base/0001_categorias.sql", "base/0002_impuestos.sql", "development/0001_demo.sql"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	// Un ambiente sin carpeta solo aplica los seeds base
+	names, err = seedFiles(fsys, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, expected[:2]) {
+		t.Errorf("expected base seeds, got %v", names)
+	}
+}
+
+func Test_seedFiles_Embedded(t *testing.T) {
+	names, err := seedFiles(postgres.Seeds, "development")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 || names[0] != "base/0001_categorias.sql" {
+		t.Errorf("unexpected embedded seeds: %v", names)
+	}
+}
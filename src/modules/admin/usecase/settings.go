@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Claves en minúsculas separadas por puntos, por ejemplo carrito.checkout
+var settingKeyPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_-]+)*$`)
+
+// Settings implements common.SettingsStore.
+// El mapa retornado es compartido, no se debe modificar.
+func (t *tenant) Settings(ctx context.Context, tenantID uuid.UUID) (map[string]json.RawMessage, error) {
+	if values, ok := t.settingsCache.get(tenantID); ok {
+		return values, nil
+	}
+	stored, err := t.settings.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	values := maps.Clone(t.settingDefaults)
+	for _, setting := range stored {
+		values[setting.Key] = setting.Value
+	}
+	t.settingsCache.set(tenantID, values, time.Duration(t.config.Settings.CacheTTL)*time.Second)
+	return values, nil
+}
+
+// GetTenantSettings implements Tenant.
+// Retorna las claves del tenant y las que tienen valor por defecto.
+func (t *tenant) GetTenantSettings(ctx context.Context, id uuid.UUID) ([]domain.DTOSetting, error) {
+	if _, err := t.getOwnedTenant(ctx, id); err != nil {
+		return nil, err
+	}
+	stored, err := t.settings.GetSettings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []domain.DTOSetting{}
+	for key, value := range t.settingDefaults {
+		if slices.ContainsFunc(stored, func(s domain.TableSetting) bool { return s.Key == key }) {
+			continue
+		}
+		result = append(result, domain.DTOSetting{Key: key, Value: value, Type: settingType(value), Default: true})
+	}
+	for _, setting := range stored {
+		result = append(result, domain.DTOSetting{
+			Key:       setting.Key,
+			Value:     setting.Value,
+			Type:      settingType(setting.Value),
+			UpdatedAt: setting.UpdatedAt,
+		})
+	}
+	slices.SortFunc(result, func(a, b domain.DTOSetting) int { return strings.Compare(a.Key, b.Key) })
+	return result, nil
+}
+
+// UpdateTenantSettings implements Tenant.
+// Las claves con null vuelven al valor por defecto.
+func (t *tenant) UpdateTenantSettings(ctx context.Context, id uuid.UUID, dto domain.DTOUpdateSettings) ([]domain.DTOSetting, error) {
+	if _, err := t.getOwnedTenant(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var settings []domain.TableSetting
+	var remove []string
+	for key, value := range dto.Settings {
+		if !settingKeyPattern.MatchString(key) {
+			return nil, common.BadRequestError(fmt.Sprintf("invalid setting key %q", key))
+		}
+		kind := settingType(value)
+		if kind == "" {
+			remove = append(remove, key)
+			continue
+		}
+		// Los valores por defecto fijan el tipo de la clave
+		if value, ok := t.settingDefaults[key]; ok && settingType(value) != kind {
+			return nil, common.BadRequestError(fmt.Sprintf("setting %s must be a %s", key, settingType(value)))
+		}
+		if strings.HasPrefix(key, common.ModuleSettingPrefix) && kind != domain.SettingTypeBool {
+			return nil, common.BadRequestError(fmt.Sprintf("setting %s must be a %s", key, domain.SettingTypeBool))
+		}
+		settings = append(settings, domain.TableSetting{TenantID: id, Key: key, Value: value})
+	}
+
+	if err := t.settings.UpdateSettings(ctx, id, settings, remove); err != nil {
+		t.log.Error(ctx, "Error updating tenant settings", "tenant_id", id, "error", err)
+		return nil, err
+	}
+	t.settingsCache.invalidate(id)
+	t.publish(ctx, id, domain.TenantEventUpdated)
+	return t.GetTenantSettings(ctx, id)
+}
+
+// Tipo del valor JSON, vacío para null
+func settingType(value json.RawMessage) string {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return ""
+	}
+	switch value[0] {
+	case 'n':
+		return ""
+	case '"':
+		return domain.SettingTypeString
+	case 't', 'f':
+		return domain.SettingTypeBool
+	case '{':
+		return domain.SettingTypeObject
+	case '[':
+		return domain.SettingTypeArray
+	}
+	return domain.SettingTypeNumber
+}
+
+// Los valores de TENANT_SETTINGS_DEFAULTS que no son JSON válido son textos
+func parseSettingDefaults(defaults map[string]string) map[string]json.RawMessage {
+	result := make(map[string]json.RawMessage, len(defaults))
+	for key, value := range defaults {
+		if json.Valid([]byte(value)) {
+			result[key] = json.RawMessage(value)
+			continue
+		}
+		raw, _ := json.Marshal(value)
+		result[key] = raw
+	}
+	return result
+}
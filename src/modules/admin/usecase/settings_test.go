@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Configuración de los tenants en memoria, cuenta las lecturas
+type memorySettingsRepository struct {
+	repository.SettingsRepository
+	mu     sync.Mutex
+	values map[uuid.UUID]map[string]json.RawMessage
+	reads  int
+}
+
+func (r *memorySettingsRepository) GetSettings(ctx context.Context, tenantID uuid.UUID) ([]domain.TableSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reads++
+	var result []domain.TableSetting
+	for key, value := range r.values[tenantID] {
+		result = append(result, domain.TableSetting{TenantID: tenantID, Key: key, Value: value})
+	}
+	return result, nil
+}
+
+func (r *memorySettingsRepository) UpdateSettings(ctx context.Context, tenantID uuid.UUID, settings []domain.TableSetting, remove []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.values[tenantID] == nil {
+		r.values[tenantID] = map[string]json.RawMessage{}
+	}
+	for _, setting := range settings {
+		r.values[tenantID][setting.Key] = setting.Value
+	}
+	for _, key := range remove {
+		delete(r.values[tenantID], key)
+	}
+	return nil
+}
+
+func newTestSettings() (*tenant, *memorySettingsRepository, context.Context, uuid.UUID) {
+	uc, repo, _ := newTestTenant()
+	settings := &memorySettingsRepository{values: map[uuid.UUID]map[string]json.RawMessage{}}
+	uc.settings = settings
+	uc.config.Settings.CacheTTL = 60
+	uc.settingDefaults = parseSettingDefaults(map[string]string{"currency": "COP", "max_items": "20"})
+	ctx, table := repo.addTenant(uuid.New())
+	return uc, settings, ctx, table.ID
+}
+
+func Test_settingType(t *testing.T) {
+	tests := map[string]string{
+		`"COP"`:   domain.SettingTypeString,
+		`true`:    domain.SettingTypeBool,
+		` false `: domain.SettingTypeBool,
+		`12.5`:    domain.SettingTypeNumber,
+		`-1`:      domain.SettingTypeNumber,
+		`{"a":1}`: domain.SettingTypeObject,
+		`[1,2]`:   domain.SettingTypeArray,
+		`null`:    "",
+		``:        "",
+		"  \n":    "",
+	}
+	for value, want := range tests {
+		if got := settingType(json.RawMessage(value)); got != want {
+			t.Errorf("settingType(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func Test_parseSettingDefaults(t *testing.T) {
+	defaults := parseSettingDefaults(map[string]string{
+		"currency":       "COP",
+		"locale":         "es-CO",
+		"max_items":      "20",
+		"modules.ventas": "true",
+		"quoted":         `"texto"`,
+	})
+	want := map[string]string{
+		"currency":       `"COP"`,
+		"locale":         `"es-CO"`,
+		"max_items":      `20`,
+		"modules.ventas": `true`,
+		"quoted":         `"texto"`,
+	}
+	for key, value := range want {
+		if string(defaults[key]) != value {
+			t.Errorf("%s: got %s, want %s", key, defaults[key], value)
+		}
+	}
+}
+
+func Test_tenant_UpdateTenantSettings(t *testing.T) {
+	uc, settings, ctx, tenantID := newTestSettings()
+
+	invalid := []map[string]json.RawMessage{
+		{"Currency": json.RawMessage(`"USD"`)},
+		{"carrito..checkout": json.RawMessage(`true`)},
+		// El valor por defecto fija el tipo
+		{"max_items": json.RawMessage(`"20"`)},
+		// Los módulos solo se activan o desactivan
+		{"modules.ventas": json.RawMessage(`"on"`)},
+	}
+	for _, values := range invalid {
+		if _, err := uc.UpdateTenantSettings(ctx, tenantID, domain.DTOUpdateSettings{Settings: values}); common.StatusCode(err, 0) != http.StatusBadRequest {
+			t.Errorf("%v: expected 400, got %v", values, err)
+		}
+	}
+	if len(settings.values[tenantID]) != 0 {
+		t.Fatalf("invalid settings saved: %v", settings.values[tenantID])
+	}
+
+	result, err := uc.UpdateTenantSettings(ctx, tenantID, domain.DTOUpdateSettings{Settings: map[string]json.RawMessage{
+		"currency":       json.RawMessage(`"USD"`),
+		"modules.ventas": json.RawMessage(`false`),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, len(result))
+	for i, setting := range result {
+		keys[i] = setting.Key
+	}
+	if !slices.Equal(keys, []string{"currency", "max_items", "modules.ventas"}) {
+		t.Errorf("unexpected settings: %v", keys)
+	}
+	for _, setting := range result {
+		if setting.Key == "currency" && (setting.Default || string(setting.Value) != `"USD"`) {
+			t.Errorf("currency not overridden: %+v", setting)
+		}
+	}
+
+	// null vuelve al valor por defecto
+	if _, err := uc.UpdateTenantSettings(ctx, tenantID, domain.DTOUpdateSettings{Settings: map[string]json.RawMessage{"currency": json.RawMessage(`null`)}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := settings.values[tenantID]["currency"]; ok {
+		t.Error("currency not removed")
+	}
+}
+
+func Test_tenant_SettingsCache(t *testing.T) {
+	uc, settings, ctx, tenantID := newTestSettings()
+	settings.values[tenantID] = map[string]json.RawMessage{"currency": json.RawMessage(`"USD"`)}
+
+	for range 3 {
+		values, err := uc.Settings(context.Background(), tenantID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(values["currency"]) != `"USD"` || string(values["max_items"]) != `20` {
+			t.Fatalf("unexpected settings: %v", values)
+		}
+	}
+	if settings.reads != 1 {
+		t.Errorf("expected a single read, got %d", settings.reads)
+	}
+
+	// Al actualizar se invalida la caché
+	if _, err := uc.UpdateTenantSettings(ctx, tenantID, domain.DTOUpdateSettings{Settings: map[string]json.RawMessage{"currency": json.RawMessage(`"EUR"`)}}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := uc.Settings(context.Background(), tenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(values["currency"]) != `"EUR"` {
+		t.Errorf("stale settings after update: %v", values)
+	}
+}
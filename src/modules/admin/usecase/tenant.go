@@ -7,10 +7,16 @@ import (
 	"api-test/src/modules/admin/domain"
 	"api-test/src/modules/admin/repository"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/google/uuid"
@@ -18,26 +24,65 @@ import (
 )
 
 type Tenant interface {
-	CreateTenant(ctx context.Context, tenant domain.DTOTenant) (*domain.DTOTenant, error)
+	common.QuotaEnforcer
+	common.SettingsStore
+	CreateTenant(ctx context.Context, tenant domain.DTOTenant) (*domain.DTOJob, error)
+	CloneTenant(ctx context.Context, id uuid.UUID, dto domain.DTOCloneTenant) (*domain.DTOJob, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error)
+	RetryJob(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error)
+	CancelJob(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error)
+	GetJobArchive(ctx context.Context, id uuid.UUID) (string, error)
+	ResumeJobs(ctx context.Context) error
 	GetTenantByID(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error)
 	GetTenantByName(ctx context.Context, name string) (*domain.DTOTenant, error)
-	UpdateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error)
-	DeleteTenant(ctx context.Context, id uuid.UUID) error
+	UpdateTenant(ctx context.Context, id uuid.UUID, dto domain.DTOUpdateTenant) (*domain.DTOTenant, error)
+	SuspendTenant(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error)
+	ReactivateTenant(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error)
+	RequestTenantDeletion(ctx context.Context, id uuid.UUID) (*domain.DTODeletionRequest, error)
+	DeleteTenant(ctx context.Context, id uuid.UUID, dto domain.DTODeleteTenant) (*domain.DTOTenant, error)
+	PurgeTenants(ctx context.Context) error
 	RegisterAllTenants(ctx context.Context) error
+	RotateEncryptionKey(ctx context.Context) (*domain.DTOKeyRotationReport, error)
+	RotateTenantCredentials(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error)
+	RotateAllCredentials(ctx context.Context, olderThan time.Duration) (*domain.DTOCredentialRotationReport, error)
+	SyncTenants(ctx context.Context) error
+	ListenTenantEvents(ctx context.Context) error
+	GetTenantPool(ctx context.Context, id uuid.UUID) (*domain.DTOPoolStats, error)
+	UpdateTenantPool(ctx context.Context, id uuid.UUID, dto domain.DTOPoolSettings) (*domain.DTOPoolStats, error)
+	UpdateTenantReplicas(ctx context.Context, id uuid.UUID, dto domain.DTOReplicas) (*domain.DTOTenant, error)
+	ExportTenant(ctx context.Context, id uuid.UUID) (*domain.DTOJob, error)
+	ImportTenant(ctx context.Context, id uuid.UUID, archive io.Reader, exportJobID uuid.UUID) (*domain.DTOJob, error)
+	GetTenantsHealth(ctx context.Context) (*domain.DTOHealthReport, error)
 	ListTenants(ctx context.Context) ([]domain.DTOTenant, error)
+	ListPlans(ctx context.Context) ([]domain.DTOPlan, error)
+	UpdateTenantPlan(ctx context.Context, id uuid.UUID, dto domain.DTOAssignPlan) (*domain.DTOTenant, error)
+	GetTenantUsage(ctx context.Context, id uuid.UUID) (*domain.DTOUsage, error)
+	GetTenantSettings(ctx context.Context, id uuid.UUID) ([]domain.DTOSetting, error)
+	UpdateTenantSettings(ctx context.Context, id uuid.UUID, dto domain.DTOUpdateSettings) ([]domain.DTOSetting, error)
 }
 
 type tenant struct {
 	log           common.Logger
 	repo          repository.TenantRepository
+	jobs          repository.JobRepository
+	events        repository.TenantEventRepository
+	data          repository.TenantDataRepository
+	plans         repository.PlanRepository
+	settings      repository.SettingsRepository
 	tenantManager *common.TenantConnectionManager
 	migrations    TenantMigrations
 	psql          postgres.Database
 	config        *config.Config
 	crypto        *encryption
+	// Identifica a la instancia en los eventos que publica
+	instance      uuid.UUID
+	planCache     *tenantCache[*domain.TablePlan]
+	settingsCache *tenantCache[map[string]json.RawMessage]
+	// Valores de TENANT_SETTINGS_DEFAULTS
+	settingDefaults map[string]json.RawMessage
 }
 
-func (t *tenant) CreateTenant(ctx context.Context, tenant domain.DTOTenant) (*domain.DTOTenant, error) {
+func (t *tenant) CreateTenant(ctx context.Context, tenant domain.DTOTenant) (*domain.DTOJob, error) {
 	// Extraer el usuario ID del contexto
 	userFromCtx := ctx.Value(t.tenantManager.UserIDKey)
 	if userFromCtx == nil {
@@ -49,105 +94,368 @@ func (t *tenant) CreateTenant(ctx context.Context, tenant domain.DTOTenant) (*do
 		t.log.Error(ctx, "Error creating user tenant", "error", "invalid user id")
 		return nil, errors.New("invalid user id to associate with tenants")
 	}
+	if tenant.Isolation == "" {
+		tenant.Isolation = t.config.TenantLifecycle.DefaultIsolation
+	}
+
+	newTenant, err := t.createTenantRecord(ctx, userUUID, tenant, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Con un tenant plantilla se clona en lugar de migrar y ejecutar los seeds.
+	// Los datos personales de la plantilla no se copian.
+	if template := t.signupTemplate(ctx, tenant.Isolation); template != nil {
+		return t.startJob(ctx, uuid.New(), domain.JobKindClone, newTenant.ID, domain.ClonePayload{SourceID: template.ID, Anonymize: true})
+	}
+	// La base de datos, el usuario y las migraciones se crean en segundo plano
+	return t.startJob(ctx, uuid.New(), domain.JobKindProvision, newTenant.ID, nil)
+}
+
+// Registra el tenant en aprovisionamiento con credenciales nuevas y lo asocia
+// al usuario. Con fieldKey el tenant usa la clave de columnas cifradas de ese
+// tenant para poder leer sus datos.
+func (t *tenant) createTenantRecord(ctx context.Context, userID uuid.UUID, tenant domain.DTOTenant, fieldKey *domain.TableTenant) (*domain.TableTenant, error) {
 	// Generar credenciales
 	password, err := t.crypto.GenerateRandomPassword()
 	if err != nil {
 		return nil, err
 	}
-	ciphertext, iv, err := t.crypto.Encrypt(password)
+	var sealed *envelope
+	keyID := uuid.Nil
+	if fieldKey == nil {
+		sealed, err = t.crypto.Seal(ctx, password)
+	} else {
+		// Reseal conserva la clave de datos, de la que se deriva la de las columnas
+		sealed, err = t.crypto.Reseal(ctx, sealedPassword(*fieldKey), password)
+		keyID = fieldKeyID(*fieldKey)
+	}
 	if err != nil {
 		return nil, err
 	}
 	id, _ := uuid.NewRandom()
-	dbName := t.generateDBName(tenant.Name, id)
+	slug, err := t.slug(ctx, tenant.Slug, tenant.Name, id)
+	if err != nil {
+		return nil, err
+	}
+	isolation, dbName, dbSchema := t.tenantDatabase(tenant.Isolation, tenant.Name, id)
 
 	// Create tenant in database
 	newTenant, err := t.repo.CreateTenant(ctx, domain.TableTenant{
-		ID:                id,
-		Name:              tenant.Name,
-		DBName:            dbName,
-		DBHost:            t.config.DBConfig.DBHost,
-		DBPort:            t.config.DBConfig.DBPort,
-		DBUser:            t.generateDBUser(id),
-		DBPassword:        ciphertext,
-		IsActive:          true,
-		IV:                iv,
-		Version:           "AES-GCM-256",
-		PasswordPlaintext: password,
+		ID:         id,
+		Name:       tenant.Name,
+		UserID:     userID,
+		Slug:       slug,
+		DBName:     dbName,
+		DBSchema:   dbSchema,
+		Isolation:  isolation,
+		DBHost:     t.config.DBConfig.DBHost,
+		DBPort:     t.config.DBConfig.DBPort,
+		DBUser:     t.generateDBUser(id),
+		DBPassword: sealed.Ciphertext,
+		IsActive:   true,
+		Status:     domain.TenantStatusProvisioning,
+		IV:         sealed.IV,
+		DataKey:    sealed.DataKey,
+		FieldKeyID: keyID,
+		PlanID:     t.config.Quotas.DefaultPlan,
+		Version:    sealed.Version,
 	})
 	if err != nil {
 		t.log.Error(ctx, "Error creating tenant", "error", err)
 		return nil, err
 	}
 
-	// Asociar el tenant con el usuario
+	// Asociar el tenant con el usuario, cuenta para el límite de usuarios del plan
+	if err := t.Consume(ctx, newTenant.ID, common.ResourceUsers, 1); err != nil {
+		return nil, err
+	}
 	_, err = t.repo.CreateUserTenant(ctx, domain.TableUserTenant{
 		ID:       uuid.New(),
 		TenantID: newTenant.ID,
-		UserID:   userUUID,
+		UserID:   userID,
 	})
 	if err != nil {
+		t.Release(ctx, newTenant.ID, common.ResourceUsers, 1)
 		t.log.Error(ctx, "Error creating user tenant", "error", err)
 		return nil, err
 	}
+	return newTenant, nil
+}
 
-	// Register tenant in connection manager
-	pwd, err := t.crypto.Decrypt(ciphertext, iv)
+func (t *tenant) GetTenantByID(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error) {
+	result, err := t.repo.GetTenantByID(ctx, id)
 	if err != nil {
-		t.log.Error(ctx, "Error decrypting password", "error", err)
 		return nil, err
 	}
-	dsn := t.tenantManager.DSN(common.DSNConfig{
-		Host:     newTenant.DBHost,
-		Port:     newTenant.DBPort,
-		User:     newTenant.DBUser,
-		Password: string(pwd),
-		Database: newTenant.DBName,
-		SSLMode:  t.config.SSLMode,
-	})
-	if err := t.tenantManager.RegisterTenant(&common.TenantConfig{
-		TenantID:         newTenant.ID,
-		Name:             newTenant.Name,
-		ConnectionString: dsn,
-	}, t.psql.Connect); err != nil {
-		t.log.Error(ctx, "Error registering tenant", "error", err)
+	dto := result.ToDTO()
+	return &dto, nil
+}
+
+func (t *tenant) GetTenantByName(ctx context.Context, name string) (*domain.DTOTenant, error) {
+	result, err := t.repo.GetTenantByName(ctx, name)
+	if err != nil {
 		return nil, err
 	}
+	dto := result.ToDTO()
+	return &dto, nil
+}
 
-	// Run migrations
-	if err := t.migrations.RunAllMigrations(ctx, newTenant.ID); err != nil {
-		t.log.Error(ctx, "Error running migrations", "error", err)
+func (t *tenant) UpdateTenant(ctx context.Context, id uuid.UUID, dto domain.DTOUpdateTenant) (*domain.DTOTenant, error) {
+	table, err := t.getOwnedTenant(ctx, id)
+	if err != nil {
 		return nil, err
 	}
 
-	result := newTenant.ToDTO()
+	if dto.Slug != "" && dto.Slug != table.Slug {
+		if table.Slug, err = t.slug(ctx, dto.Slug, dto.Name, id); err != nil {
+			return nil, err
+		}
+	}
+	table.Name = dto.Name
+	table.UpdatedAt = time.Now()
+	if _, err := t.repo.UpdateTenant(ctx, *table, "name", "slug", "updated_at"); err != nil {
+		t.log.Error(ctx, "Error updating tenant", "tenant_id", id, "error", err)
+		return nil, err
+	}
+	if err := t.tenantManager.UpdateConfig(id, func(config *common.TenantConfig) {
+		config.Name = table.Name
+		config.Slug = table.Slug
+	}); err != nil {
+		t.log.Warn(ctx, "Tenant not registered in connection manager", "tenant_id", id)
+	}
+	t.publish(ctx, id, domain.TenantEventUpdated)
+
+	result := table.ToDTO()
 	return &result, nil
 }
 
-func (t *tenant) GetTenantByID(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error) {
-	result, err := t.repo.GetTenantByID(ctx, id)
+func (t *tenant) SuspendTenant(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error) {
+	table, err := t.getOwnerTenant(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	dto := result.ToDTO()
-	return &dto, nil
+	if !table.IsActive {
+		return nil, common.ConflictError("tenant is already suspended")
+	}
+
+	if err := t.suspend(ctx, table); err != nil {
+		return nil, err
+	}
+
+	result := table.ToDTO()
+	return &result, nil
 }
 
-func (t *tenant) GetTenantByName(ctx context.Context, name string) (*domain.DTOTenant, error) {
-	result, err := t.repo.GetTenantByName(ctx, name)
+func (t *tenant) ReactivateTenant(ctx context.Context, id uuid.UUID) (*domain.DTOTenant, error) {
+	table, err := t.getOwnerTenant(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	dto := result.ToDTO()
-	return &dto, nil
+	if table.IsActive {
+		return nil, common.ConflictError("tenant is already active")
+	}
+
+	// Reactivar cancela también la eliminación programada
+	table.IsActive = true
+	table.SuspendedAt = time.Time{}
+	table.DeleteAfter = time.Time{}
+	table.UpdatedAt = time.Now()
+	if _, err := t.repo.UpdateTenant(ctx, *table, "is_active", "suspended_at", "delete_after", "updated_at"); err != nil {
+		t.log.Error(ctx, "Error reactivating tenant", "tenant_id", id, "error", err)
+		return nil, err
+	}
+	if err := t.tenantManager.UpdateConfig(id, func(config *common.TenantConfig) { config.Suspended = false }); err != nil {
+		t.log.Warn(ctx, "Tenant not registered in connection manager", "tenant_id", id)
+	}
+	t.publish(ctx, id, domain.TenantEventReactivated)
+
+	result := table.ToDTO()
+	return &result, nil
 }
 
-func (t *tenant) UpdateTenant(ctx context.Context, tenant domain.TableTenant) (*domain.TableTenant, error) {
-	return t.repo.UpdateTenant(ctx, tenant)
+func (t *tenant) RequestTenantDeletion(ctx context.Context, id uuid.UUID) (*domain.DTODeletionRequest, error) {
+	table, err := t.getOwnerTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := t.crypto.GenerateRandomPassword()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(token))
+	expiresAt := time.Now().Add(time.Duration(t.config.TenantLifecycle.DeletionTokenTTL) * time.Second)
+
+	table.DeletionToken = hash[:]
+	table.DeletionExpiresAt = expiresAt
+	if _, err := t.repo.UpdateTenant(ctx, *table, "deletion_token", "deletion_token_expires_at"); err != nil {
+		t.log.Error(ctx, "Error requesting tenant deletion", "tenant_id", id, "error", err)
+		return nil, err
+	}
+
+	return &domain.DTODeletionRequest{
+		TenantID:          table.ID,
+		ConfirmationToken: token,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+func (t *tenant) DeleteTenant(ctx context.Context, id uuid.UUID, dto domain.DTODeleteTenant) (*domain.DTOTenant, error) {
+	table, err := t.getOwnerTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validar el token de confirmación
+	hash := sha256.Sum256([]byte(dto.ConfirmationToken))
+	if len(table.DeletionToken) == 0 || subtle.ConstantTimeCompare(hash[:], table.DeletionToken) != 1 {
+		return nil, common.ForbiddenError("invalid confirmation token")
+	}
+	if time.Now().After(table.DeletionExpiresAt) {
+		return nil, common.ForbiddenError("confirmation token expired")
+	}
+	table.DeletionToken = nil
+	table.DeletionExpiresAt = time.Time{}
+
+	// Sin periodo de gracia se elimina de inmediato
+	if dto.GracePeriodHours == 0 {
+		if err := t.purge(ctx, *table); err != nil {
+			return nil, err
+		}
+		result := table.ToDTO()
+		return &result, nil
+	}
+
+	table.DeleteAfter = time.Now().Add(time.Duration(dto.GracePeriodHours) * time.Hour)
+	if table.IsActive {
+		if err := t.suspend(ctx, table); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := t.repo.UpdateTenant(ctx, *table, "delete_after", "deletion_token", "deletion_token_expires_at"); err != nil {
+		t.log.Error(ctx, "Error scheduling tenant deletion", "tenant_id", id, "error", err)
+		return nil, err
+	}
+	t.log.Info(ctx, "Tenant deletion scheduled", "tenant_id", id, "delete_after", table.DeleteAfter)
+
+	result := table.ToDTO()
+	return &result, nil
 }
 
-func (t *tenant) DeleteTenant(ctx context.Context, id uuid.UUID) error {
-	return t.repo.DeleteTenant(ctx, id)
+func (t *tenant) PurgeTenants(ctx context.Context) error {
+	tenants, err := t.repo.GetTenantsPendingDeletion(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, tenant := range tenants {
+		if err := t.purge(ctx, tenant); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Elimina la base de datos, el usuario, la conexión y el registro del tenant
+func (t *tenant) purge(ctx context.Context, table domain.TableTenant) error {
+	t.log.Info(ctx, "Deleting tenant", "tenant", table.Name, "tenant_id", table.ID)
+	if err := t.tenantManager.RemoveTenant(table.ID); err != nil {
+		t.log.Error(ctx, "Error closing tenant connection", "tenant_id", table.ID, "error", err)
+		return err
+	}
+	// Las demás instancias cierran sus conexiones antes de eliminar la base de datos
+	t.publish(ctx, table.ID, domain.TenantEventDeleted)
+	if err := t.repo.DropTenantDatabase(ctx, table); err != nil {
+		t.log.Error(ctx, "Error dropping tenant database", "tenant_id", table.ID, "error", err)
+		return err
+	}
+	if err := t.repo.DeleteTenant(ctx, table.ID); err != nil {
+		t.log.Error(ctx, "Error deleting tenant", "tenant_id", table.ID, "error", err)
+		return err
+	}
+	t.log.Info(ctx, "Tenant deleted", "tenant", table.Name, "tenant_id", table.ID)
+	return nil
+}
+
+func (t *tenant) suspend(ctx context.Context, table *domain.TableTenant) error {
+	table.IsActive = false
+	table.SuspendedAt = time.Now()
+	table.UpdatedAt = table.SuspendedAt
+	if _, err := t.repo.UpdateTenant(ctx, *table, "is_active", "suspended_at", "updated_at"); err != nil {
+		t.log.Error(ctx, "Error suspending tenant", "tenant_id", table.ID, "error", err)
+		return err
+	}
+	if err := t.tenantManager.UpdateConfig(table.ID, func(config *common.TenantConfig) { config.Suspended = true }); err != nil {
+		t.log.Warn(ctx, "Tenant not registered in connection manager", "tenant_id", table.ID)
+	}
+	t.publish(ctx, table.ID, domain.TenantEventSuspended)
+	return nil
+}
+
+// Obtiene el tenant validando que el usuario del contexto pertenezca a él
+func (t *tenant) getOwnedTenant(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error) {
+	userID, ok := ctx.Value(t.tenantManager.UserIDKey).(uuid.UUID)
+	if !ok {
+		t.log.Error(ctx, "Error getting user id", "error", "user not found in context")
+		return nil, common.UnauthorizedError("user not found in context")
+	}
+
+	userTenants, err := t.repo.GetTenantsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(userTenants, func(ut domain.TableUserTenant) bool { return ut.TenantID == id }) {
+		return nil, common.ForbiddenError("tenant no autorizado")
+	}
+
+	table, err := t.repo.GetTenantByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil || table.ID == uuid.Nil {
+		return nil, common.NotFoundError("tenant not found")
+	}
+	return table, nil
+}
+
+// Obtiene el tenant si el usuario del contexto lo creó o es operador, para
+// las acciones que los demás miembros no pueden hacer
+func (t *tenant) getOwnerTenant(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error) {
+	if t.tenantManager.IsOperator(ctx) {
+		return t.getOperatedTenant(ctx, id)
+	}
+	table, err := t.getOwnedTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userID, _ := ctx.Value(t.tenantManager.UserIDKey).(uuid.UUID); table.UserID != userID {
+		return nil, common.ForbiddenError("only the tenant owner can do this")
+	}
+	return table, nil
+}
+
+// Obtiene el tenant para una acción de operador, que no necesita pertenecer a él
+func (t *tenant) getOperatedTenant(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error) {
+	if err := t.tenantManager.RequireOperator(ctx); err != nil {
+		return nil, err
+	}
+	table, err := t.repo.GetTenantByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil || table.ID == uuid.Nil {
+		return nil, common.NotFoundError("tenant not found")
+	}
+	return table, nil
+}
+
+// Obtiene el tenant si el usuario del contexto pertenece a él o es operador
+func (t *tenant) getReadableTenant(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error) {
+	if t.tenantManager.IsOperator(ctx) {
+		return t.getOperatedTenant(ctx, id)
+	}
+	return t.getOwnedTenant(ctx, id)
 }
 
 func (t *tenant) RegisterAllTenants(ctx context.Context) error {
@@ -157,34 +465,147 @@ func (t *tenant) RegisterAllTenants(ctx context.Context) error {
 		return err
 	}
 
+	var errs []error
 	for _, tenant := range tenants {
-		pwd, err := t.crypto.Decrypt(tenant.DBPassword, tenant.IV)
-		if err != nil {
-			t.log.Error(ctx, "Error decrypting password", "error", err)
-			return err
+		// Los tenants en aprovisionamiento se registran al reanudar su job
+		if tenant.Status != domain.TenantStatusReady {
+			t.log.Warn(ctx, "Skipping tenant not ready", "tenant", tenant.Name, "tenant_id", tenant.ID, "status", tenant.Status)
+			continue
 		}
-		dsn := t.tenantManager.DSN(common.DSNConfig{
-			Host:     tenant.DBHost,
-			Port:     tenant.DBPort,
-			User:     tenant.DBUser,
-			Password: string(pwd),
-			Database: tenant.DBName,
-			SSLMode:  t.config.SSLMode,
-		})
-		t.log.Info(ctx, "Registering tenant", "tenant", tenant.Name, "tenant_id", tenant.ID)
-		if err := t.tenantManager.RegisterTenant(&common.TenantConfig{
-			TenantID:         tenant.ID,
-			Name:             tenant.Name,
-			ConnectionString: dsn,
-		}, t.psql.Connect); err != nil {
-			t.log.Error(ctx, "Error registering tenant", "error", err)
-			return err
+		// La conexión se abre en el primer uso; un tenant con error no detiene el resto
+		if err := t.register(ctx, tenant, false); err != nil {
+			t.log.Error(ctx, "Error registering tenant", "tenant", tenant.Name, "tenant_id", tenant.ID, "error", err)
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant.ID, err))
+			continue
 		}
 		t.log.Info(ctx, "Tenant registered", "tenant", tenant.Name, "tenant_id", tenant.ID)
 	}
+	return errors.Join(errs...)
+}
+
+// Registra la conexión del tenant en el connection manager
+func (t *tenant) register(ctx context.Context, tenant domain.TableTenant, provisioning bool) error {
+	config, err := t.tenantConfig(ctx, tenant, provisioning)
+	if err != nil {
+		return err
+	}
+	if err := t.tenantManager.RegisterTenant(config, t.psql.Connect); err != nil {
+		t.log.Error(ctx, "Error registering tenant", "error", err)
+		return err
+	}
 	return nil
 }
 
+// Configuración de conexión del tenant con su contraseña descifrada
+func (t *tenant) tenantConfig(ctx context.Context, tenant domain.TableTenant, provisioning bool) (*common.TenantConfig, error) {
+	pwd, err := t.password(ctx, tenant)
+	if err != nil {
+		t.log.Error(ctx, "Error decrypting password", "error", err)
+		return nil, err
+	}
+	dsn := t.tenantManager.DSN(common.DSNConfig{
+		Host:     tenant.DBHost,
+		Port:     tenant.DBPort,
+		User:     tenant.DBUser,
+		Password: pwd,
+		Database: tenant.DBName,
+		SSLMode:  t.config.SSLMode,
+		Schema:   tenant.DBSchema,
+	})
+	return &common.TenantConfig{
+		TenantID:             tenant.ID,
+		Name:                 tenant.Name,
+		Slug:                 tenant.Slug,
+		ConnectionString:     dsn,
+		Suspended:            !tenant.IsActive,
+		Provisioning:         provisioning,
+		FieldCipher:          t.fieldCipher(ctx, tenant),
+		CredentialsRotatedAt: tenant.CredentialsRotatedAt,
+		Pool:                 poolSettings(tenant.PoolSettings()),
+		RowLevelSecurity:     tenant.Isolation == domain.TenantIsolationShared,
+		Replicas:             tenant.DBReplicas,
+		Plan:                 tenant.PlanID,
+	}, nil
+}
+
+// RotateEncryptionKey envuelve las claves de datos de todos los tenants con la
+// clave activa. Las contraseñas no cambian, por lo que las conexiones abiertas
+// no se ven afectadas mientras ambas claves estén cargadas.
+func (t *tenant) RotateEncryptionKey(ctx context.Context) (*domain.DTOKeyRotationReport, error) {
+	tenants, err := t.repo.GetAllTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DTOKeyRotationReport{ActiveKey: t.crypto.activeKeyID(), Total: len(tenants)}
+	for _, table := range tenants {
+		sealed, changed, err := t.crypto.Rewrap(ctx, sealedPassword(table))
+		legacy := table.Version == legacyVersion || table.Version == ""
+		if err == nil && changed {
+			table.DBPassword = sealed.Ciphertext
+			table.IV = sealed.IV
+			table.DataKey = sealed.DataKey
+			table.Version = sealed.Version
+			table.UpdatedAt = time.Now()
+			_, err = t.repo.UpdateTenant(ctx, table, "db_password", "iv", "data_key", "version", "updated_at")
+		}
+		// Al migrar desde legacy el tenant obtiene su primera clave de datos
+		if err == nil && changed && legacy {
+			cipher := t.fieldCipher(ctx, table)
+			_ = t.tenantManager.UpdateConfig(table.ID, func(config *common.TenantConfig) { config.FieldCipher = cipher })
+		}
+		switch {
+		case err != nil:
+			t.log.Error(ctx, "Error rotating tenant key", "tenant_id", table.ID, "error", err)
+			report.Failed++
+			report.Failures = append(report.Failures, domain.DTOTenantFailure{TenantID: table.ID, Name: table.Name, Error: err.Error()})
+		case changed:
+			report.Rotated++
+		default:
+			report.Unchanged++
+		}
+	}
+	t.log.Info(ctx, "Encryption key rotation completed", "active_key", report.ActiveKey, "rotated", report.Rotated, "failed", report.Failed)
+	return report, nil
+}
+
+// Cifrador de las columnas con el tag encrypt. Sin clave de datos el tenant
+// funciona, pero los modelos con columnas cifradas retornan error.
+func (t *tenant) fieldCipher(ctx context.Context, table domain.TableTenant) *common.FieldCipher {
+	key, err := t.crypto.FieldKey(ctx, fieldKeyID(table), sealedPassword(table))
+	if err == nil {
+		var cipher *common.FieldCipher
+		if cipher, err = common.NewFieldCipher(key); err == nil {
+			return cipher
+		}
+	}
+	t.log.Warn(ctx, "Field encryption not available for tenant", "tenant_id", table.ID, "error", err)
+	return nil
+}
+
+// Los clones derivan la clave de columnas del tenant de origen
+func fieldKeyID(table domain.TableTenant) uuid.UUID {
+	if table.FieldKeyID != uuid.Nil {
+		return table.FieldKeyID
+	}
+	return table.ID
+}
+
+// Descifra la contraseña de la base de datos del tenant
+func (t *tenant) password(ctx context.Context, table domain.TableTenant) (string, error) {
+	return t.crypto.Open(ctx, sealedPassword(table))
+}
+
+// Contraseña cifrada tal como está guardada en tenants.tenants
+func sealedPassword(table domain.TableTenant) envelope {
+	return envelope{
+		Ciphertext: table.DBPassword,
+		IV:         table.IV,
+		DataKey:    table.DataKey,
+		Version:    table.Version,
+	}
+}
+
 func (t *tenant) ListTenants(ctx context.Context) ([]domain.DTOTenant, error) {
 	// Get user id
 	userFromCtx := ctx.Value(t.tenantManager.UserIDKey)
@@ -227,6 +648,19 @@ func (t *tenant) removeAccentsAndSpecialChars(input string) string {
 	return sb.String()
 }
 
+// Aislamiento, base de datos y schema del tenant. Con schema propio o tablas
+// compartidas el tenant vive en la base de datos compartida.
+func (t *tenant) tenantDatabase(isolation string, name string, id uuid.UUID) (string, string, string) {
+	dbName := t.generateDBName(name, id)
+	switch isolation {
+	case domain.TenantIsolationSchema:
+		return isolation, t.config.TenantLifecycle.SharedDBName, dbName
+	case domain.TenantIsolationShared:
+		return isolation, t.config.TenantLifecycle.SharedDBName, t.config.TenantLifecycle.PooledSchema
+	}
+	return domain.TenantIsolationDatabase, dbName, ""
+}
+
 func (t *tenant) generateDBName(tenantName string, id uuid.UUID) string {
 	normalized := t.normalizeName(tenantName)
 	shortID := id.String()[:8]
@@ -255,21 +689,57 @@ func (t *tenant) normalizeName(name string) string {
 	return name
 }
 
+// Valida el slug pedido o lo genera a partir del nombre. Uno generado que ya
+// existe se completa con el inicio del id, uno pedido que existe es un conflicto.
+func (t *tenant) slug(ctx context.Context, requested string, name string, id uuid.UUID) (string, error) {
+	slug := requested
+	if slug == "" {
+		slug = strings.ReplaceAll(t.normalizeName(name), "_", "-")
+		if len(slug) > 40 {
+			slug = strings.TrimRight(slug[:40], "-")
+		}
+		if slug == "" || !unicode.IsLetter(rune(slug[0])) {
+			slug = "t-" + slug
+		}
+	}
+
+	existing, err := t.repo.GetTenantBySlug(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil || existing.ID == uuid.Nil || existing.ID == id {
+		return slug, nil
+	}
+	if requested != "" {
+		return "", common.ConflictError("tenant slug already in use")
+	}
+	return slug + "-" + id.String()[:8], nil
+}
+
 func (t *tenant) generateDBUser(id uuid.UUID) string {
 	shortID := id.String()[:8]
 	dbUser := fmt.Sprintf("kosvi_admin_%s", shortID)
 	return dbUser
 }
 
-func NewTenant(log common.Logger, repo repository.TenantRepository, migrations TenantMigrations, config *config.Config, tenantManager *common.TenantConnectionManager, psql postgres.Database) Tenant {
+func NewTenant(log common.Logger, repo repository.TenantRepository, jobs repository.JobRepository, events repository.TenantEventRepository, data repository.TenantDataRepository, plans repository.PlanRepository, settings repository.SettingsRepository, migrations TenantMigrations, config *config.Config, tenantManager *common.TenantConnectionManager, psql postgres.Database) Tenant {
 	return &tenant{
 		log:           log,
 		repo:          repo,
+		jobs:          jobs,
+		events:        events,
+		data:          data,
+		plans:         plans,
+		settings:      settings,
 		config:        config,
 		tenantManager: tenantManager,
 		migrations:    migrations,
 		psql:          psql,
 		crypto:        NewEncryption(log, config),
+		instance:      uuid.New(),
+		planCache:     newTenantCache[*domain.TablePlan](),
+		settingsCache: newTenantCache[map[string]json.RawMessage](),
+		settingDefaults: parseSettingDefaults(config.Settings.Defaults),
 	}
 }
 
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"api-test/src/modules/admin/domain"
+	"api-test/src/modules/admin/repository"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Registro de tenants en memoria, los demás métodos no se usan
+type memoryTenantRepository struct {
+	repository.TenantRepository
+	mu      sync.Mutex
+	tenants map[uuid.UUID]domain.TableTenant
+	members map[uuid.UUID][]uuid.UUID
+}
+
+func (r *memoryTenantRepository) GetTenantByID(ctx context.Context, id uuid.UUID) (*domain.TableTenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	table := r.tenants[id]
+	return &table, nil
+}
+
+func (r *memoryTenantRepository) UpdateTenant(ctx context.Context, tenant domain.TableTenant, columns ...string) (*domain.TableTenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants[tenant.ID] = tenant
+	return &tenant, nil
+}
+
+func (r *memoryTenantRepository) GetTenantsByUser(ctx context.Context, userID uuid.UUID) ([]domain.TableUserTenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []domain.TableUserTenant
+	for _, tenantID := range r.members[userID] {
+		result = append(result, domain.TableUserTenant{UserID: userID, TenantID: tenantID})
+	}
+	return result, nil
+}
+
+func (r *memoryTenantRepository) CountUserTenants(ctx context.Context, tenantID uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, tenants := range r.members {
+		for _, id := range tenants {
+			if id == tenantID {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// Guarda los eventos publicados
+type memoryEventRepository struct {
+	repository.TenantEventRepository
+	mu     sync.Mutex
+	events []domain.TenantEvent
+}
+
+func (r *memoryEventRepository) PublishTenantEvent(ctx context.Context, event domain.TenantEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func newTestTenant() (*tenant, *memoryTenantRepository, *memoryEventRepository) {
+	conf := &config.Config{TenantID: uuid.New()}
+	conf.TenantLifecycle.DeletionTokenTTL = 600
+	repo := &memoryTenantRepository{tenants: map[uuid.UUID]domain.TableTenant{}, members: map[uuid.UUID][]uuid.UUID{}}
+	events := &memoryEventRepository{}
+	return &tenant{
+		log:           common.NewLogger(),
+		repo:          repo,
+		events:        events,
+		config:        conf,
+		crypto:        &encryption{log: common.NewLogger(), config: conf},
+		tenantManager: common.NewTenantConnectionManager(conf),
+		instance:      uuid.New(),
+		planCache:     newTenantCache[*domain.TablePlan](),
+		settingsCache: newTenantCache[map[string]json.RawMessage](),
+	}, repo, events
+}
+
+// Crea un tenant activo del que userID es miembro y el contexto del usuario
+func (r *memoryTenantRepository) addTenant(userID uuid.UUID) (context.Context, domain.TableTenant) {
+	table := domain.TableTenant{ID: uuid.New(), Name: "Tienda", UserID: userID, Slug: "tienda", IsActive: true, Status: domain.TenantStatusReady}
+	r.tenants[table.ID] = table
+	r.members[userID] = append(r.members[userID], table.ID)
+	return context.WithValue(context.Background(), common.UserIDKey, userID), table
+}
+
+func (r *memoryTenantRepository) DropTenantDatabase(ctx context.Context, tenant domain.TableTenant) error {
+	return nil
+}
+
+func (r *memoryTenantRepository) DeleteUserTenants(ctx context.Context, tenantID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for userID, tenants := range r.members {
+		r.members[userID] = slices.DeleteFunc(tenants, func(id uuid.UUID) bool { return id == tenantID })
+	}
+	return nil
+}
+
+func (r *memoryTenantRepository) DeleteTenant(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tenants, id)
+	return nil
+}
+
+// Asocia otro usuario al tenant sin ser su dueño y retorna su contexto
+func (r *memoryTenantRepository) addMember(tenantID uuid.UUID) context.Context {
+	userID := uuid.New()
+	r.members[userID] = append(r.members[userID], tenantID)
+	return context.WithValue(context.Background(), common.UserIDKey, userID)
+}
+
+func Test_tenant_getOwnedTenant(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	ctx, table := repo.addTenant(uuid.New())
+
+	if _, err := uc.getOwnedTenant(ctx, table.ID); err != nil {
+		t.Fatalf("owner should get the tenant: %v", err)
+	}
+	other := context.WithValue(context.Background(), common.UserIDKey, uuid.New())
+	if _, err := uc.getOwnedTenant(other, table.ID); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Errorf("expected 403 for another user, got %v", err)
+	}
+	if _, err := uc.getOwnedTenant(context.Background(), table.ID); common.StatusCode(err, 0) != http.StatusUnauthorized {
+		t.Errorf("expected 401 without user, got %v", err)
+	}
+}
+
+func Test_tenant_SuspendReactivate(t *testing.T) {
+	uc, repo, events := newTestTenant()
+	ctx, table := repo.addTenant(uuid.New())
+
+	suspended, err := uc.SuspendTenant(ctx, table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suspended.IsActive || suspended.SuspendedAt.IsZero() {
+		t.Errorf("tenant not suspended: %+v", suspended)
+	}
+	if _, err := uc.SuspendTenant(ctx, table.ID); common.StatusCode(err, 0) != http.StatusConflict {
+		t.Errorf("expected 409 suspending twice, got %v", err)
+	}
+
+	// Reactivar cancela la eliminación programada
+	stored := repo.tenants[table.ID]
+	stored.DeleteAfter = time.Now().Add(time.Hour)
+	repo.tenants[table.ID] = stored
+	reactivated, err := uc.ReactivateTenant(ctx, table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reactivated.IsActive || !reactivated.DeleteAfter.IsZero() {
+		t.Errorf("tenant not reactivated: %+v", reactivated)
+	}
+	if len(events.events) != 2 || events.events[0].Event != domain.TenantEventSuspended || events.events[1].Event != domain.TenantEventReactivated {
+		t.Errorf("unexpected events: %+v", events.events)
+	}
+}
+
+func Test_tenant_DeleteTenantConfirmation(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	ctx, table := repo.addTenant(uuid.New())
+
+	if _, err := uc.DeleteTenant(ctx, table.ID, domain.DTODeleteTenant{ConfirmationToken: "x"}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 without a requested deletion, got %v", err)
+	}
+	request, err := uc.RequestTenantDeletion(ctx, table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.DeleteTenant(ctx, table.ID, domain.DTODeleteTenant{ConfirmationToken: "x"}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Fatalf("expected 403 with a wrong token, got %v", err)
+	}
+
+	// Con periodo de gracia se suspende y se programa la eliminación
+	deleted, err := uc.DeleteTenant(ctx, table.ID, domain.DTODeleteTenant{ConfirmationToken: request.ConfirmationToken, GracePeriodHours: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.IsActive || deleted.DeleteAfter.Before(time.Now().Add(23*time.Hour)) {
+		t.Errorf("deletion not scheduled: %+v", deleted)
+	}
+	// El token solo se puede usar una vez
+	if _, err := uc.DeleteTenant(ctx, table.ID, domain.DTODeleteTenant{ConfirmationToken: request.ConfirmationToken}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Errorf("expected 403 reusing the token, got %v", err)
+	}
+
+	expired, err := uc.RequestTenantDeletion(ctx, table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := repo.tenants[table.ID]
+	stored.DeletionExpiresAt = time.Now().Add(-time.Second)
+	repo.tenants[table.ID] = stored
+	if _, err := uc.DeleteTenant(ctx, table.ID, domain.DTODeleteTenant{ConfirmationToken: expired.ConfirmationToken}); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Errorf("expected 403 with an expired token, got %v", err)
+	}
+}
+
+func Test_tenant_LifecycleRequiresOwner(t *testing.T) {
+	uc, repo, _ := newTestTenant()
+	operator := uuid.New()
+	uc.config.Operators.UserIDs = []uuid.UUID{operator}
+	_, table := repo.addTenant(uuid.New())
+	memberCtx := repo.addMember(table.ID)
+
+	// Un miembro puede leer el tenant pero no suspenderlo, eliminarlo ni rotar sus credenciales
+	if _, err := uc.getOwnedTenant(memberCtx, table.ID); err != nil {
+		t.Fatalf("member should read the tenant: %v", err)
+	}
+	actions := map[string]func(ctx context.Context) error{
+		"suspend": func(ctx context.Context) error { _, err := uc.SuspendTenant(ctx, table.ID); return err },
+		"deletion": func(ctx context.Context) error {
+			_, err := uc.RequestTenantDeletion(ctx, table.ID)
+			return err
+		},
+		"delete": func(ctx context.Context) error {
+			_, err := uc.DeleteTenant(ctx, table.ID, domain.DTODeleteTenant{ConfirmationToken: "x"})
+			return err
+		},
+		"rotate": func(ctx context.Context) error { _, err := uc.RotateTenantCredentials(ctx, table.ID); return err },
+	}
+	for name, action := range actions {
+		if err := action(memberCtx); common.StatusCode(err, 0) != http.StatusForbidden {
+			t.Errorf("%s: expected 403 for a member, got %v", name, err)
+		}
+	}
+	if !repo.tenants[table.ID].IsActive || len(repo.tenants[table.ID].DeletionToken) != 0 {
+		t.Fatalf("tenant modified by a member: %+v", repo.tenants[table.ID])
+	}
+
+	// Los operadores actúan sin pertenecer al tenant
+	operatorCtx := context.WithValue(context.Background(), common.UserIDKey, operator)
+	if _, err := uc.SuspendTenant(operatorCtx, table.ID); err != nil {
+		t.Errorf("operator should suspend the tenant: %v", err)
+	}
+	if _, err := uc.ReactivateTenant(memberCtx, table.ID); common.StatusCode(err, 0) != http.StatusForbidden {
+		t.Errorf("expected 403 reactivating as a member, got %v", err)
+	}
+}
@@ -34,5 +34,7 @@ func NewCarritoCompraAPI(log common.Logger, app fiber.Router, config *config.Con
 }
 
 func (api *CarritoCompraAPI) Register() {
+	// Los tenants pueden deshabilitar el módulo con modules.carritocompra
+	api.app.Use("/carrito-compra", api.tenant.RequireModule("carritocompra"))
 	api.routes.RegisterRoutes()
 }
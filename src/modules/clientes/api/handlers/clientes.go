@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/clientes/domain"
+	"api-test/src/modules/clientes/usecase"
+)
+
+type ClientesHandler struct {
+	log common.Logger
+	uc  usecase.ClientesUseCase
+	*common.GenericHandler[domain.CreateClientesDTO, domain.ResponseClientesDTO, domain.UpdateClientesDTO, int64]
+}
+
+func NewClientesHandler(log common.Logger, uc usecase.ClientesUseCase) *ClientesHandler {
+	handlers := common.NewGenericHandler(log, uc)
+	return &ClientesHandler{
+		log:            log,
+		uc:             uc,
+		GenericHandler: handlers,
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"api-test/src/common"
+	"api-test/src/modules/clientes/api/handlers"
+	"api-test/src/modules/clientes/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ClientesRoutes interface {
+	RegisterRoutes()
+}
+
+type clientesRoutes struct {
+	log      common.Logger
+	uc       usecase.ClientesUseCase
+	handlers *handlers.ClientesHandler
+	app      fiber.Router
+}
+
+func (r *clientesRoutes) RegisterRoutes() {
+	r.app.Get("/clientes", r.handlers.Search)
+	r.app.Get("/clientes/:id", r.handlers.Get)
+	r.app.Post("/clientes", r.handlers.Create)
+	r.app.Put("/clientes/:id", r.handlers.Update)
+	r.app.Delete("/clientes/:id", r.handlers.Delete)
+}
+
+func NewClientesRoutes(log common.Logger, uc usecase.ClientesUseCase, app fiber.Router) ClientesRoutes {
+	return &clientesRoutes{
+		log:      log,
+		uc:       uc,
+		handlers: handlers.NewClientesHandler(log, uc),
+		app:      app,
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"api-test/src/modules/clientes/domain"
+	"api-test/src/modules/clientes/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ClientesAPI struct {
+	log    common.Logger
+	config *config.Config
+	app    fiber.Router
+	uc     usecase.ClientesUseCase
+	routes ClientesRoutes
+	tenant *common.TenantConnectionManager
+}
+
+func NewClientesAPI(log common.Logger, app fiber.Router, config *config.Config, tenant *common.TenantConnectionManager) *ClientesAPI {
+	repo := common.NewRepository[domain.ClientesTable, int64](config, log, tenant)
+	uc := usecase.NewClientesUseCase(config, log, tenant, repo)
+	routes := NewClientesRoutes(log, uc, app)
+	return &ClientesAPI{
+		log:    log,
+		config: config,
+		app:    app,
+		uc:     uc,
+		routes: routes,
+		tenant: tenant,
+	}
+}
+
+func (api *ClientesAPI) Register() {
+	// Los tenants pueden deshabilitar el módulo con modules.clientes
+	api.app.Use("/clientes", api.tenant.RequireModule("clientes"))
+	api.routes.RegisterRoutes()
+}
@@ -0,0 +1,43 @@
+package domain
+
+type ResponseClientesDTO struct {
+	Id        int64   `json:"id" params:"id"`
+	Nombre    string  `json:"nombre"`
+	Documento *string `json:"documento,omitempty"`
+	Email     *string `json:"email,omitempty"`
+	Direccion *string `json:"direccion,omitempty"`
+}
+
+type UpdateClientesDTO struct {
+	Id        int64   `json:"id" params:"id" validate:"required"`
+	Nombre    string  `json:"nombre"`
+	Documento *string `json:"documento"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+	Direccion *string `json:"direccion"`
+}
+
+func (dto *UpdateClientesDTO) ToTable() ClientesTable {
+	return ClientesTable{
+		ID:        dto.Id,
+		Nombre:    dto.Nombre,
+		Documento: dto.Documento,
+		Email:     dto.Email,
+		Direccion: dto.Direccion,
+	}
+}
+
+type CreateClientesDTO struct {
+	Nombre    string  `json:"nombre" validate:"required"`
+	Documento *string `json:"documento"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+	Direccion *string `json:"direccion"`
+}
+
+func (dto *CreateClientesDTO) ToTable() ClientesTable {
+	return ClientesTable{
+		Nombre:    dto.Nombre,
+		Documento: dto.Documento,
+		Email:     dto.Email,
+		Direccion: dto.Direccion,
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "github.com/uptrace/bun"
+
+// Los datos personales se guardan cifrados, documento y email se pueden
+// filtrar por igualdad a través de su blind index
+type ClientesTable struct {
+	bun.BaseModel `bun:"table:clientes,alias:c"`
+	ID            int64   `bun:"id,pk,autoincrement"`
+	Nombre        string  `bun:"nombre,notnull"`
+	Documento     *string `bun:"documento" encrypt:"blind:documento_bidx"`
+	DocumentoBIdx *string `bun:"documento_bidx"`
+	Email         *string `bun:"email" encrypt:"blind:email_bidx"`
+	EmailBIdx     *string `bun:"email_bidx"`
+	Direccion     *string `bun:"direccion" encrypt:""`
+}
+
+func (c *ClientesTable) ToDTO() ResponseClientesDTO {
+	return ResponseClientesDTO{
+		Id:        c.ID,
+		Nombre:    c.Nombre,
+		Documento: c.Documento,
+		Email:     c.Email,
+		Direccion: c.Direccion,
+	}
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"api-test/src/common"
+	"api-test/src/config"
+	"api-test/src/modules/clientes/domain"
+)
+
+type ClientesUseCase interface {
+	common.UseCase[domain.CreateClientesDTO, domain.ResponseClientesDTO, domain.UpdateClientesDTO, int64]
+}
+
+type clientesUseCase struct {
+	config *config.Config
+	log    common.Logger
+	tenant *common.TenantConnectionManager
+	repo   common.Repository[domain.ClientesTable, int64]
+	common.UseCase[domain.CreateClientesDTO, domain.ResponseClientesDTO, domain.UpdateClientesDTO, int64]
+}
+
+func NewClientesUseCase(config *config.Config, log common.Logger, tenant *common.TenantConnectionManager, repo common.Repository[domain.ClientesTable, int64]) ClientesUseCase {
+	createToTable := func(dto domain.CreateClientesDTO) domain.ClientesTable {
+		return dto.ToTable()
+	}
+
+	toDTO := func(table domain.ClientesTable) domain.ResponseClientesDTO {
+		return table.ToDTO()
+	}
+
+	updateToTable := func(dto domain.UpdateClientesDTO) domain.ClientesTable {
+		return dto.ToTable()
+	}
+
+	genericUC := common.NewUseCase(config, log, tenant, repo, createToTable, updateToTable, toDTO)
+
+	return &clientesUseCase{config, log, tenant, repo, genericUC}
+}
+
+var _ ClientesUseCase = (*clientesUseCase)(nil)
@@ -5,6 +5,7 @@ import (
 	"api-test/src/config"
 	"api-test/src/modules/productos/domain"
 	"api-test/src/modules/productos/usecase"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,6 +21,8 @@ type ProductosAPI struct {
 
 func NewProductosAPI(log common.Logger, app fiber.Router, config *config.Config, tenant *common.TenantConnectionManager) *ProductosAPI {
 	repo := common.NewRepository[domain.ProductosTable, int64](config, log, tenant)
+	// El catálogo se lee mucho más de lo que se modifica
+	repo = common.NewCachedRepository(log, tenant, repo, time.Duration(config.Cache.TTL)*time.Second)
 	uc := usecase.NewProductosUseCase(config, log, tenant, repo)
 	routes := NewProductosRoutes(log, uc, app)
 	return &ProductosAPI{
@@ -33,5 +36,7 @@ func NewProductosAPI(log common.Logger, app fiber.Router, config *config.Config,
 }
 
 func (api *ProductosAPI) Register() {
+	// Los tenants pueden deshabilitar el módulo con modules.productos
+	api.app.Use("/productos", api.tenant.RequireModule("productos"))
 	api.routes.RegisterRoutes()
 }